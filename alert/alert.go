@@ -0,0 +1,176 @@
+// Package alert notifies the owners of the saved searches of the new news matching them.
+package alert
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// The number of alerts listed in a message, the others are only counted.
+const maxListed = 50
+
+// Notifier delivers the alerts of a saved search to its target, e.g an email address.
+type Notifier interface {
+	Notify(search *model.SavedSearch, alerts []*model.Alert) error
+}
+
+// Alerter saves the alerts of the inserted news matching the saved searches, see store.Listener.
+// The immediate alerts are delivered right away, the hourly and daily ones when Deliver is called for their frequency.
+type Alerter struct {
+	store     store.NewsStore
+	notifiers map[string]Notifier
+
+	// Serialises the deliveries, so an alert is not notified twice.
+	mu sync.Mutex
+}
+
+// NewAlerter creates the alerter, the notifiers are keyed by the name used by the saved searches.
+func NewAlerter(store store.NewsStore, notifiers map[string]Notifier) *Alerter {
+	return &Alerter{
+		store:     store,
+		notifiers: notifiers,
+	}
+}
+
+// Inserted saves the alerts of the news, then delivers the immediate alerts in the background.
+func (a *Alerter) Inserted(news []*model.News) {
+	searches, err := a.store.GetSearches("")
+	if err != nil {
+		log.Println("alert: ", err)
+		return
+	}
+
+	var alerts []*model.Alert
+	for _, s := range searches {
+		for _, n := range news {
+			if Match(s, n) {
+				alerts = append(alerts, model.NewAlert(s, n))
+			}
+		}
+	}
+
+	if len(alerts) == 0 {
+		return
+	}
+
+	if err := a.store.SaveAlerts(alerts); err != nil {
+		log.Println("alert: ", err)
+		return
+	}
+
+	go func() {
+		if err := a.Deliver(model.Immediate); err != nil {
+			log.Println("alert: ", err)
+		}
+	}()
+}
+
+// Deliver notifies the pending alerts of the saved searches of the frequencies, a message per saved search.
+// The alerts that fail to be notified are kept pending, they are retried on the next delivery.
+func (a *Alerter) Deliver(frequencies ...string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	pending, err := a.store.GetPendingAlerts()
+	if err != nil {
+		return err
+	}
+
+	var order []string
+	var bySearch = make(map[string][]*model.Alert)
+	for _, v := range pending {
+		if _, exist := bySearch[v.SearchId]; !exist {
+			order = append(order, v.SearchId)
+		}
+		bySearch[v.SearchId] = append(bySearch[v.SearchId], v)
+	}
+
+	for _, id := range order {
+		s, err := a.store.GetSearch(id)
+		if err == store.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if !contains(frequencies, s.Frequency) {
+			continue
+		}
+
+		notifier := a.notifiers[s.Notifier]
+		if notifier == nil {
+			log.Printf("alert: search %s: unknown notifier %s", s.Id, s.Notifier)
+			continue
+		}
+
+		alerts := bySearch[id]
+		if err := notifier.Notify(s, alerts); err != nil {
+			log.Printf("alert: search %s: %s", s.Id, err)
+			continue
+		}
+
+		var ids = make([]string, 0, len(alerts))
+		for _, v := range alerts {
+			ids = append(ids, v.Id)
+		}
+
+		if err := a.store.SetDelivered(ids, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match returns true if the news contains the query of the saved search, and is of its newspapers and language.
+func Match(s *model.SavedSearch, n *model.News) bool {
+	if len(s.Newspapers) > 0 && !contains(s.Newspapers, n.Source.NewspaperId) {
+		return false
+	}
+
+	if s.Language != "" && s.Language != n.Language {
+		return false
+	}
+
+	return language.Contains(n.Title+"\n"+n.Content, s.Query, n.Language)
+}
+
+// message returns the subject and the plain text body listing the alerts.
+func message(s *model.SavedSearch, alerts []*model.Alert) (string, string) {
+	name := s.Name
+	if name == "" {
+		name = s.Query
+	}
+
+	subject := fmt.Sprintf("%d new news for %s", len(alerts), name)
+
+	buf := &bytes.Buffer{}
+	for i, v := range alerts {
+		if i == maxListed {
+			fmt.Fprintf(buf, "... and %d more\n", len(alerts)-maxListed)
+			break
+		}
+
+		fmt.Fprintf(buf, "- %s (%s)\n  %s\n", v.Title, v.Newspaper, v.Url)
+	}
+
+	return subject, buf.String()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package alert
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+)
+
+// smtpServer accepts the mails of a single connection at a time, and sends their data to the channel.
+func smtpServer(t *testing.T, mails chan<- string) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			r := bufio.NewReader(conn)
+			reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+			reply("220 localhost")
+
+			var data []string
+			var inData bool
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					break
+				}
+				line = strings.TrimRight(line, "\r\n")
+
+				if inData {
+					if line == "." {
+						inData = false
+						mails <- strings.Join(data, "\n")
+						data = nil
+						reply("250 OK")
+					} else {
+						data = append(data, line)
+					}
+					continue
+				}
+
+				switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+				case "EHLO", "HELO", "MAIL", "RCPT", "RSET", "NOOP":
+					reply("250 OK")
+				case "DATA":
+					inData = true
+					reply("354 Go ahead")
+				case "QUIT":
+					reply("221 Bye")
+				default:
+					reply("502 Not implemented")
+				}
+
+				if strings.HasPrefix(strings.ToUpper(line), "QUIT") {
+					break
+				}
+			}
+			conn.Close()
+		}
+	}()
+
+	return l
+}
+
+func testNews(id, title string) *model.News {
+	return &model.News{
+		Id:       id,
+		Title:    title,
+		Content:  "Hujan lebat sejak pagi.",
+		Url:      "https://example.com/" + id,
+		Language: "ms",
+		Source:   model.NewsSource{NewspaperId: model.BharianId, NewspaperName: "Berita Harian"},
+	}
+}
+
+func TestMatch(t *testing.T) {
+	s := &model.SavedSearch{Query: "banjir", Newspapers: []string{model.BharianId}}
+
+	if !Match(s, testNews("1", "Banjir di Kelantan")) {
+		t.Error("expected the news to match")
+	}
+
+	if Match(s, testNews("2", "Kemarau di Perlis")) {
+		t.Error("expected the news without the query not to match")
+	}
+
+	n := testNews("3", "Banjir di Kelantan")
+	n.Source.NewspaperId = model.NstId
+	if Match(s, n) {
+		t.Error("expected the news of another newspaper not to match")
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	mails := make(chan string, 1)
+	l := smtpServer(t, mails)
+	defer l.Close()
+
+	notifier := &SMTP{Addr: l.Addr().String(), From: "alerts@example.com"}
+	search := &model.SavedSearch{Id: "s", Name: "Floods", Query: "banjir", Target: "analyst@example.com"}
+	alert := model.NewAlert(search, testNews("1", "Banjir di Kelantan"))
+
+	if err := notifier.Notify(search, []*model.Alert{alert}); err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-mails
+	for _, want := range []string{"To: analyst@example.com", "Subject: 1 new news for Floods", "Banjir di Kelantan", "https://example.com/1"} {
+		if !strings.Contains(mail, want) {
+			t.Errorf("expected the mail to contain %q, got:\n%s", want, mail)
+		}
+	}
+}
+
+func TestAlerter(t *testing.T) {
+	var texts []string
+	var fail bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var payload struct {
+			Text string `json:"text"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Error(err)
+		}
+		texts = append(texts, payload.Text)
+	}))
+	defer server.Close()
+
+	s := &storetest.Memory{Searches: []*model.SavedSearch{
+		{Id: "immediate", Query: "banjir", Frequency: model.Immediate, Notifier: "slack", Target: server.URL},
+		{Id: "hourly", Query: "banjir", Frequency: model.Hourly, Notifier: "slack", Target: server.URL},
+	}}
+	a := NewAlerter(s, map[string]Notifier{"slack": &Slack{HttpClient: server.Client()}})
+
+	news := []*model.News{testNews("1", "Banjir di Kelantan"), testNews("2", "Kemarau di Perlis")}
+
+	// Save the alerts as Inserted does, without its background delivery, so the failures are observed.
+	fail = true
+	for _, v := range s.Searches {
+		for _, n := range news {
+			if Match(v, n) {
+				s.SaveAlerts([]*model.Alert{model.NewAlert(v, n)})
+			}
+		}
+	}
+	if len(s.Alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(s.Alerts))
+	}
+
+	if err := a.Deliver(model.Immediate); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := s.GetPendingAlerts(); len(pending) != 2 {
+		t.Fatalf("expected the failed alerts to be pending, got %d pending", len(pending))
+	}
+
+	fail = false
+	if err := a.Deliver(model.Immediate); err != nil {
+		t.Fatal(err)
+	}
+	if len(texts) != 1 || !strings.Contains(texts[0], "Banjir di Kelantan") {
+		t.Fatalf("expected the immediate alert, got %q", texts)
+	}
+
+	if err := a.Deliver(model.Immediate, model.Hourly); err != nil {
+		t.Fatal(err)
+	}
+	if len(texts) != 2 {
+		t.Fatalf("expected the hourly digest, got %q", texts)
+	}
+
+	if pending, _ := s.GetPendingAlerts(); len(pending) != 0 {
+		t.Fatalf("expected no pending alert, got %d", len(pending))
+	}
+}
@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// SMTP emails the alerts to the target address of the saved search.
+type SMTP struct {
+	// Addr is the host and port of the server, e.g localhost:25.
+	Addr string
+	// Auth is nil if the server does not require authentication.
+	Auth smtp.Auth
+	From string
+}
+
+func (s *SMTP) Notify(search *model.SavedSearch, alerts []*model.Alert) error {
+	subject, body := message(search, alerts)
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(msg, "To: %s\r\n", search.Target)
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprint(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprint(msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprint(msg, "\r\n")
+	msg.Write(bytes.Replace([]byte(body), []byte("\n"), []byte("\r\n"), -1))
+
+	return smtp.SendMail(s.Addr, s.Auth, s.From, []string{search.Target}, msg.Bytes())
+}
+
+// Slack posts the alerts to the target url of the saved search, a Slack compatible incoming webhook.
+type Slack struct {
+	HttpClient *http.Client
+}
+
+func (s *Slack) Notify(search *model.SavedSearch, alerts []*model.Alert) error {
+	subject, body := message(search, alerts)
+
+	payload, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{"*" + subject + "*\n" + body})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.HttpClient.Post(search.Target, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
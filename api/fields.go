@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// parseFields returns the fields parameter, e.g id,title,datetime,source.name, see store.ParseFields.
+func parseFields(r *http.Request) ([]string, error) {
+	value := r.FormValue("fields")
+	if value == "" {
+		return store.ParseFields(nil)
+	}
+
+	return store.ParseFields(strings.Split(value, ","))
+}
+
+// project returns the news with only the fields.
+func project(list []*model.News, fields []string) ([]map[string]json.RawMessage, error) {
+	var result = make([]map[string]json.RawMessage, 0, len(list))
+
+	for _, n := range list {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+
+		var source map[string]json.RawMessage
+		if err := json.Unmarshal(all["source"], &source); err != nil {
+			return nil, err
+		}
+
+		var projection = make(map[string]json.RawMessage)
+		var projectedSource map[string]json.RawMessage
+		for _, field := range fields {
+			if !strings.HasPrefix(field, "source.") {
+				if value, exist := all[field]; exist {
+					projection[field] = value
+				}
+				continue
+			}
+
+			if projectedSource == nil {
+				projectedSource = make(map[string]json.RawMessage)
+			}
+			key := strings.TrimPrefix(field, "source.")
+			projectedSource[key] = source[key]
+		}
+
+		if _, exist := projection["source"]; !exist && projectedSource != nil {
+			data, err := json.Marshal(projectedSource)
+			if err != nil {
+				return nil, err
+			}
+			projection["source"] = data
+		}
+
+		result = append(result, projection)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,556 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/graphql"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/go-chi/chi"
+)
+
+// The page size of newsList, by default and at most.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var errInvalidCursor = errors.New("Invalid cursor")
+
+// GraphQLHandler serves the GraphQL queries of the news, see queryType for the schema.
+type GraphQLHandler struct {
+	Logger    *log.Logger
+	schema    *graphql.Schema
+	newsStore store.NewsStore
+}
+
+// NewGraphQLHandler creates the handler, the queries deeper than maxDepth or more complex than maxComplexity are rejected.
+func NewGraphQLHandler(newsStore store.NewsStore, maxDepth int, maxComplexity int) *GraphQLHandler {
+	h := &GraphQLHandler{
+		Logger:    log.New(os.Stderr, "", log.LstdFlags),
+		newsStore: newsStore,
+	}
+
+	schema, err := graphql.NewSchema(h.queryType())
+	if err != nil {
+		// The schema is static, so it is a programming error.
+		panic(err)
+	}
+	schema.MaxDepth = maxDepth
+	schema.MaxComplexity = maxComplexity
+
+	h.schema = schema
+	return h
+}
+
+func (h *GraphQLHandler) Routes() chi.Router {
+	router := chi.NewRouter()
+
+	router.Get("/", h.query)
+	router.Post("/", h.query)
+	router.Get("/schema", h.getSchema)
+	return router
+}
+
+// query executes the query of the query, operationName and variables parameters of a GET,
+// or of the JSON body of a POST. A POST of application/graphql is the query itself.
+func (h *GraphQLHandler) query(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+
+	switch {
+	case r.Method == http.MethodGet:
+		req.Query = r.FormValue("query")
+		req.OperationName = r.FormValue("operationName")
+		if v := r.FormValue("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				h.renderError(w, http.StatusBadRequest, "Invalid JSON variables")
+				return
+			}
+		}
+
+	case strings.HasPrefix(r.Header.Get("Content-Type"), "application/graphql"):
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			h.renderError(w, http.StatusBadRequest, "Invalid body")
+			return
+		}
+		req.Query = string(data)
+
+	default:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.renderError(w, http.StatusBadRequest, "Invalid JSON body")
+			return
+		}
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		h.renderError(w, http.StatusBadRequest, "The query is required")
+		return
+	}
+
+	resp := h.schema.Do(r.Context(), req)
+
+	// The query failed before its execution, e.g a syntax error.
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+
+	h.render(w, status, resp)
+}
+
+// getSchema returns the schema in the schema definition language.
+func (h *GraphQLHandler) getSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(h.schema.String()))
+}
+
+func (h *GraphQLHandler) render(w http.ResponseWriter, status int, data interface{}) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		h.Logger.Printf("ERROR: graphql: marshal json: %s", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonData)
+}
+
+func (h *GraphQLHandler) renderError(w http.ResponseWriter, status int, message string) {
+	h.render(w, status, &graphql.Response{Errors: []*graphql.Error{{Message: message}}})
+}
+
+// serverError logs the error of the store, the client only gets a generic error.
+func (h *GraphQLHandler) serverError(err error) error {
+	h.Logger.Printf("ERROR: graphql: %s", err)
+	return errors.New("Server error")
+}
+
+var dateTime = &graphql.Scalar{
+	Name:        "DateTime",
+	Description: "A RFC 3339 datetime, e.g 2018-06-01T08:00:00+08:00.",
+	Serialize: func(v interface{}) (interface{}, error) {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent %v", v)
+		}
+		if t.IsZero() {
+			return nil, nil
+		}
+		return t.Format(time.RFC3339Nano), nil
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("DateTime cannot represent %v", v)
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("DateTime cannot represent %q, expected a RFC 3339 datetime", s)
+		}
+		return t, nil
+	},
+}
+
+func nonNull(t graphql.Type) graphql.Type {
+	return &graphql.NonNull{Of: t}
+}
+
+func listOf(t graphql.Type) graphql.Type {
+	return &graphql.List{Of: t}
+}
+
+func newsField(name string, t graphql.Type, description string, get func(n *model.News) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Name:        name,
+		Type:        t,
+		Description: description,
+		Resolve: func(p graphql.Params) (interface{}, error) {
+			return get(p.Source.(*model.News)), nil
+		},
+	}
+}
+
+func sourceField(name string, t graphql.Type, get func(s model.NewsSource) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Name: name,
+		Type: t,
+		Resolve: func(p graphql.Params) (interface{}, error) {
+			return get(p.Source.(model.NewsSource)), nil
+		},
+	}
+}
+
+func pictureField(name string, t graphql.Type, get func(p *model.Picture) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Name: name,
+		Type: t,
+		Resolve: func(p graphql.Params) (interface{}, error) {
+			return get(p.Source.(*model.Picture)), nil
+		},
+	}
+}
+
+func entityField(name string, t graphql.Type, get func(e *model.Entity) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Name: name,
+		Type: t,
+		Resolve: func(p graphql.Params) (interface{}, error) {
+			return get(p.Source.(*model.Entity)), nil
+		},
+	}
+}
+
+// newsFields maps the fields of the News type to the fields read by the store, see store.NewsFields.
+var newsFields = map[string]string{
+	"id": "id", "author": "author", "datetime": "datetime", "title": "title", "location": "location",
+	"content": "content", "paragraphs": "paragraphs", "html": "html", "pictures": "pictures", "tags": "tags",
+	"url": "url", "source": "source", "lead": "lead", "summary": "summary", "language": "language",
+	"clusterId": "cluster_id", "entities": "entities", "topics": "topics", "canonicalUrl": "canonical_url",
+	"description": "description", "section": "section", "modified": "modified",
+}
+
+func (h *GraphQLHandler) queryType() *graphql.Object {
+	str := graphql.String
+	stringList := listOf(nonNull(graphql.String))
+
+	source := &graphql.Object{
+		Name:        "NewsSource",
+		Description: "The newspaper and the section of a news.",
+		Fields: []*graphql.Field{
+			sourceField("id", nonNull(graphql.ID), func(s model.NewsSource) interface{} { return s.NewspaperId }),
+			sourceField("name", str, func(s model.NewsSource) interface{} { return s.NewspaperName }),
+			sourceField("category", str, func(s model.NewsSource) interface{} { return s.OriginalCategory }),
+			sourceField("subcategory", str, func(s model.NewsSource) interface{} { return s.OriginalSubcategory }),
+			sourceField("tags", stringList, func(s model.NewsSource) interface{} { return s.Tags }),
+			sourceField("url", str, func(s model.NewsSource) interface{} { return s.Url }),
+		},
+	}
+
+	picture := &graphql.Object{
+		Name:        "Picture",
+		Description: "A picture of a news, the hash, the size and the mime type are set once it is mirrored.",
+		Fields: []*graphql.Field{
+			pictureField("url", str, func(p *model.Picture) interface{} { return p.ImageUrl }),
+			pictureField("caption", str, func(p *model.Picture) interface{} { return p.Caption }),
+			pictureField("hash", str, func(p *model.Picture) interface{} { return optional(p.Hash) }),
+			pictureField("width", graphql.Int, func(p *model.Picture) interface{} { return optionalInt(p.Width) }),
+			pictureField("height", graphql.Int, func(p *model.Picture) interface{} { return optionalInt(p.Height) }),
+			pictureField("mimeType", str, func(p *model.Picture) interface{} { return optional(p.MimeType) }),
+		},
+	}
+
+	entity := &graphql.Object{
+		Name:        "Entity",
+		Description: "A person, an organisation or a place mentioned by a news.",
+		Fields: []*graphql.Field{
+			entityField("id", nonNull(graphql.ID), func(e *model.Entity) interface{} { return e.Id }),
+			entityField("type", str, func(e *model.Entity) interface{} { return e.Type }),
+			entityField("name", str, func(e *model.Entity) interface{} { return e.Name }),
+			entityField("count", graphql.Int, func(e *model.Entity) interface{} { return e.Count }),
+		},
+	}
+
+	news := &graphql.Object{
+		Name: "News",
+		Fields: []*graphql.Field{
+			newsField("id", nonNull(graphql.ID), "", func(n *model.News) interface{} { return n.Id }),
+			newsField("author", str, "", func(n *model.News) interface{} { return n.Author }),
+			newsField("datetime", dateTime, "", func(n *model.News) interface{} { return n.Datetime }),
+			newsField("title", str, "", func(n *model.News) interface{} { return n.Title }),
+			newsField("location", str, "", func(n *model.News) interface{} { return n.Location }),
+			newsField("content", str, "The paragraphs separated by a new line.", func(n *model.News) interface{} { return n.Content }),
+			newsField("paragraphs", stringList, "", func(n *model.News) interface{} { return n.Paragraphs }),
+			newsField("html", str, "The sanitised HTML of the body.", func(n *model.News) interface{} { return optional(n.Html) }),
+			newsField("pictures", listOf(nonNull(picture)), "", func(n *model.News) interface{} { return n.Pictures }),
+			newsField("tags", stringList, "", func(n *model.News) interface{} { return n.Tags }),
+			newsField("url", str, "", func(n *model.News) interface{} { return n.Url }),
+			newsField("source", nonNull(source), "", func(n *model.News) interface{} { return n.Source }),
+			newsField("lead", str, "", func(n *model.News) interface{} { return n.Lead }),
+			newsField("summary", str, "", func(n *model.News) interface{} { return n.Summary }),
+			newsField("language", str, "The ISO 639-1 code.", func(n *model.News) interface{} { return n.Language }),
+			newsField("clusterId", str, "The id of the first news of its near-duplicates.", func(n *model.News) interface{} { return n.ClusterId }),
+			newsField("entities", listOf(nonNull(entity)), "", func(n *model.News) interface{} { return n.Entities }),
+			newsField("topics", stringList, "The ids of the topics of the taxonomy.", func(n *model.News) interface{} { return n.Topics }),
+			newsField("canonicalUrl", str, "The canonical url declared by the page.", func(n *model.News) interface{} { return optional(n.CanonicalUrl) }),
+			newsField("description", str, "The description declared by the page.", func(n *model.News) interface{} { return optional(n.Description) }),
+			newsField("section", str, "The section declared by the page.", func(n *model.News) interface{} { return optional(n.Section) }),
+			newsField("modified", dateTime, "The last modification declared by the page.", func(n *model.News) interface{} {
+				if n.Modified == nil {
+					return nil
+				}
+				return *n.Modified
+			}),
+		},
+	}
+
+	edge := &graphql.Object{
+		Name: "NewsEdge",
+		Fields: []*graphql.Field{
+			{Name: "cursor", Type: nonNull(graphql.String), Resolve: func(p graphql.Params) (interface{}, error) {
+				return encodeCursor(p.Source.(*model.News)), nil
+			}},
+			{Name: "node", Type: nonNull(news), Resolve: func(p graphql.Params) (interface{}, error) {
+				return p.Source, nil
+			}},
+		},
+	}
+
+	pageInfo := &graphql.Object{
+		Name: "PageInfo",
+		Fields: []*graphql.Field{
+			{Name: "hasNextPage", Type: nonNull(graphql.Boolean), Resolve: func(p graphql.Params) (interface{}, error) {
+				return p.Source.(*newsPage).hasNextPage, nil
+			}},
+			{Name: "endCursor", Type: graphql.String, Description: "The cursor of the last news of the page, null if it is empty.", Resolve: func(p graphql.Params) (interface{}, error) {
+				page := p.Source.(*newsPage)
+				if len(page.news) == 0 {
+					return nil, nil
+				}
+				return encodeCursor(page.news[len(page.news)-1]), nil
+			}},
+		},
+	}
+
+	connection := &graphql.Object{
+		Name:        "NewsConnection",
+		Description: "A page of news, latest first.",
+		Fields: []*graphql.Field{
+			{Name: "edges", Type: nonNull(listOf(nonNull(edge))), Resolve: func(p graphql.Params) (interface{}, error) {
+				return p.Source.(*newsPage).news, nil
+			}},
+			{Name: "nodes", Type: nonNull(listOf(nonNull(news))), Description: "The news of the edges.", Resolve: func(p graphql.Params) (interface{}, error) {
+				return p.Source.(*newsPage).news, nil
+			}},
+			{Name: "pageInfo", Type: nonNull(pageInfo), Resolve: func(p graphql.Params) (interface{}, error) {
+				return p.Source, nil
+			}},
+		},
+	}
+
+	filter := &graphql.InputObject{
+		Name:        "NewsFilter",
+		Description: "Selects the news, every filter given must match.",
+		Fields: []*graphql.Argument{
+			{Name: "from", Type: dateTime, Description: "The latest datetime, it defaults to now."},
+			{Name: "until", Type: dateTime, Description: "The earliest datetime, it defaults to a day before now."},
+			{Name: "provider", Type: graphql.String, Description: "The id of the newspaper, e.g bharian."},
+			{Name: "tags", Type: stringList, Description: "Any of the tags of the news or of its source."},
+			{Name: "topic", Type: graphql.String, Description: "The id of a topic of the taxonomy, e.g crime."},
+			{Name: "language", Type: graphql.String, Description: "The ISO 639-1 code."},
+			{Name: "entity", Type: graphql.String, Description: "The id of an entity mentioned."},
+			{Name: "entityType", Type: graphql.String, Description: "The type of any entity mentioned, e.g person."},
+			{Name: "query", Type: graphql.String, Description: "Every word is in the title or the content, stemmed in the language of the news."},
+		},
+	}
+
+	return &graphql.Object{
+		Name: "Query",
+		Fields: []*graphql.Field{
+			{
+				Name: "news",
+				Type: news,
+				Args: []*graphql.Argument{{Name: "id", Type: nonNull(graphql.ID)}},
+				Resolve: func(p graphql.Params) (interface{}, error) {
+					n, err := h.newsStore.Get(p.Args["id"].(string))
+					if err == store.ErrNotFound {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, h.serverError(err)
+					}
+					return n, nil
+				},
+			},
+			{
+				Name:        "newsList",
+				Description: "The news selected by the filter, a page at a time after the cursor.",
+				Type:        nonNull(connection),
+				Args: []*graphql.Argument{
+					{Name: "filter", Type: filter},
+					{Name: "first", Type: graphql.Int, Default: defaultPageSize, Description: fmt.Sprintf("The size of the page, at most %d.", maxPageSize)},
+					{Name: "after", Type: graphql.String, Description: "The endCursor of the previous page."},
+				},
+				Resolve: h.resolveNewsList,
+				Complexity: func(args map[string]interface{}) int {
+					return pageSize(args)
+				},
+			},
+		},
+	}
+}
+
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func optionalInt(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
+
+func pageSize(args map[string]interface{}) int {
+	if first, ok := args["first"].(int); ok {
+		return first
+	}
+	return defaultPageSize
+}
+
+type newsPage struct {
+	news        []*model.News
+	hasNextPage bool
+}
+
+// resolveNewsList reads the news of the filter, latest first and by id for the same datetime, after the cursor.
+func (h *GraphQLHandler) resolveNewsList(p graphql.Params) (interface{}, error) {
+	first := pageSize(p.Args)
+	if first < 0 || first > maxPageSize {
+		return nil, fmt.Errorf("first must be between 0 and %d", maxPageSize)
+	}
+
+	args, _ := p.Args["filter"].(map[string]interface{})
+
+	filter := store.Filter{
+		From:  time.Now(),
+		Until: time.Now().AddDate(0, 0, -1),
+	}
+	if v, ok := args["from"].(time.Time); ok {
+		filter.From = v
+	}
+	if v, ok := args["until"].(time.Time); ok {
+		filter.Until = v
+	}
+	filter.Language, _ = args["language"].(string)
+	filter.Entity, _ = args["entity"].(string)
+	filter.EntityType, _ = args["entityType"].(string)
+	filter.Topic, _ = args["topic"].(string)
+
+	provider, _ := args["provider"].(string)
+	query, _ := args["query"].(string)
+
+	var tags []string
+	if list, ok := args["tags"].([]interface{}); ok {
+		for _, v := range list {
+			tags = append(tags, v.(string))
+		}
+	}
+
+	var after *model.News
+	if cursor, ok := p.Args["after"].(string); ok {
+		var err error
+		if after, err = decodeCursor(cursor); err != nil {
+			return nil, err
+		}
+
+		if after.Datetime.Before(filter.From) {
+			filter.From = after.Datetime
+		}
+	}
+
+	// Only the fields selected, and those needed by the filters and the cursor, are read.
+	filter.Fields = []string{"datetime"}
+	for _, name := range append(p.Selected("edges", "node"), p.Selected("nodes")...) {
+		if field, exist := newsFields[name]; exist {
+			filter.Fields = append(filter.Fields, field)
+		}
+	}
+	if provider != "" {
+		filter.Fields = append(filter.Fields, "source.id")
+	}
+	if len(tags) > 0 {
+		filter.Fields = append(filter.Fields, "tags", "source.tags")
+	}
+	if query != "" {
+		filter.Fields = append(filter.Fields, "title", "content", "language")
+	}
+
+	list, err := h.newsStore.GetAll(filter)
+	if err != nil {
+		return nil, h.serverError(err)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if !list[i].Datetime.Equal(list[j].Datetime) {
+			return list[i].Datetime.After(list[j].Datetime)
+		}
+		return list[i].Id < list[j].Id
+	})
+
+	page := &newsPage{news: make([]*model.News, 0, first)}
+	for _, n := range list {
+		if after != nil && !n.Datetime.Before(after.Datetime) && (!n.Datetime.Equal(after.Datetime) || n.Id <= after.Id) {
+			continue
+		}
+
+		if provider != "" && n.Source.NewspaperId != provider {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(n, tags) {
+			continue
+		}
+		if query != "" && !matches(n, query) {
+			continue
+		}
+
+		if len(page.news) == first {
+			page.hasNextPage = true
+			break
+		}
+		page.news = append(page.news, n)
+	}
+
+	return page, nil
+}
+
+// hasAnyTag returns true if the news, or its source, has any of the tags, ignoring the case.
+func hasAnyTag(n *model.News, tags []string) bool {
+	for _, t := range append(append([]string{}, n.Tags...), n.Source.Tags...) {
+		for _, v := range tags {
+			if strings.EqualFold(strings.TrimSpace(t), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// encodeCursor returns the opaque cursor of the news, its datetime and its id.
+func encodeCursor(n *model.News) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(n.Datetime.Format(time.RFC3339Nano) + " " + n.Id))
+}
+
+func decodeCursor(cursor string) (*model.News, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(data), " ", 2)
+	if len(parts) != 2 {
+		return nil, errInvalidCursor
+	}
+
+	datetime, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	return &model.News{Id: parts[1], Datetime: datetime}, nil
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+)
+
+func newGraphQLTest(t *testing.T) (http.Handler, *storetest.Memory, time.Time) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s := &storetest.Memory{}
+	for i := 0; i < 7; i++ {
+		n := &model.News{
+			Id:       fmt.Sprintf("n%d", i),
+			Title:    fmt.Sprintf("Berita %d", i),
+			Datetime: now.Add(-time.Duration(i/2) * time.Hour),
+			Tags:     []string{fmt.Sprintf("tag%d", i%2)},
+			Url:      fmt.Sprintf("http://example.com/%d", i),
+			Language: "ms",
+			Pictures: []*model.Picture{{ImageUrl: "http://example.com/a.jpg", Caption: "Caption"}},
+		}
+
+		n.Source = model.BhSources[0]
+		if i%3 == 0 {
+			n.Source = model.NstSources[0]
+		}
+
+		s.News = append(s.News, n)
+	}
+
+	h := NewGraphQLHandler(s, 6, 500)
+	h.Logger = log.New(ioutil.Discard, "", 0)
+	return h.Routes(), s, now
+}
+
+type graphQLResult struct {
+	Data struct {
+		NewsList struct {
+			Edges []struct {
+				Cursor string
+				Node   map[string]interface{}
+			}
+			PageInfo struct {
+				HasNextPage bool
+				EndCursor   *string
+			}
+		}
+	}
+	Errors []struct {
+		Message string
+	}
+}
+
+func graphQL(t *testing.T, router http.Handler, query string, variables map[string]interface{}) (int, *graphQLResult) {
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	result := &graphQLResult{}
+	if err := json.Unmarshal(w.Body.Bytes(), result); err != nil {
+		t.Fatalf("%s: %s", err, w.Body.String())
+	}
+	return w.Code, result
+}
+
+const listQuery = `
+query List($filter: NewsFilter, $first: Int, $after: String) {
+	newsList(filter: $filter, first: $first, after: $after) {
+		edges { cursor node { id title source { id name } pictures { url } } }
+		pageInfo { hasNextPage endCursor }
+	}
+}`
+
+func TestGraphQLPagination(t *testing.T) {
+	router, _, _ := newGraphQLTest(t)
+
+	// The news are latest first, by id for the same datetime.
+	expected := []string{"n0", "n1", "n2", "n3", "n4", "n5", "n6"}
+
+	var got []string
+	var after interface{}
+	for pages := 0; ; pages++ {
+		if pages > 4 {
+			t.Fatal("too many pages")
+		}
+
+		status, result := graphQL(t, router, listQuery, map[string]interface{}{"first": 3, "after": after})
+		if status != http.StatusOK || len(result.Errors) > 0 {
+			t.Fatalf("got status %d: %v", status, result.Errors)
+		}
+
+		list := result.Data.NewsList
+		for _, e := range list.Edges {
+			got = append(got, e.Node["id"].(string))
+			if e.Node["source"].(map[string]interface{})["name"] == "" {
+				t.Errorf("%s: the source is not read", e.Node["id"])
+			}
+		}
+
+		if !list.PageInfo.HasNextPage {
+			break
+		}
+		after = *list.PageInfo.EndCursor
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestGraphQLFilter(t *testing.T) {
+	router, mem, now := newGraphQLTest(t)
+
+	tests := []struct {
+		filter   map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{"provider": model.NstId}, []string{"n0", "n3", "n6"}},
+		{map[string]interface{}{"tags": []string{"TAG1"}}, []string{"n1", "n3", "n5"}},
+		{map[string]interface{}{"provider": model.BharianId, "tags": "tag0"}, []string{"n2", "n4"}},
+		{map[string]interface{}{"query": "berita 4"}, []string{"n4"}},
+		{map[string]interface{}{"from": now.Add(-time.Hour).Format(time.RFC3339), "until": now.Add(-2 * time.Hour).Format(time.RFC3339)}, []string{"n2", "n3", "n4", "n5"}},
+	}
+
+	for _, test := range tests {
+		_, result := graphQL(t, router, listQuery, map[string]interface{}{"filter": test.filter})
+		if len(result.Errors) > 0 {
+			t.Errorf("%v: %v", test.filter, result.Errors)
+			continue
+		}
+
+		var got []string
+		for _, e := range result.Data.NewsList.Edges {
+			got = append(got, e.Node["id"].(string))
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%v: got %v, expected %v", test.filter, got, test.expected)
+		}
+	}
+
+	// Only the fields selected, and those of the filters and the cursor, are read.
+	graphQL(t, router, `{ newsList(filter: {provider: "bharian"}) { nodes { title clusterId } } }`, nil)
+
+	fields := append([]string(nil), mem.Filter.Fields...)
+	sort.Strings(fields)
+	if expected := []string{"cluster_id", "datetime", "source.id", "title"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("got fields %v, expected %v", fields, expected)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	router, _, _ := newGraphQLTest(t)
+
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		status    int
+		message   string
+	}{
+		{`{ newsList(first: 101) { pageInfo { hasNextPage } } }`, nil, http.StatusOK, "first must be between 0 and 100"},
+		{listQuery, map[string]interface{}{"after": "invalid"}, http.StatusOK, "Invalid cursor"},
+		{listQuery, map[string]interface{}{"filter": map[string]interface{}{"from": "yesterday"}}, http.StatusBadRequest,
+			`Variable "$filter" got invalid value {"from":"yesterday"}; In field "from": DateTime cannot represent "yesterday", expected a RFC 3339 datetime`},
+		{`{ newsList(first: 100) { nodes { id title url lead summary } } }`, nil, http.StatusBadRequest,
+			"The query has a complexity of 601, more than the maximum of 500."},
+		{`{ newsList { edges { node { source { tags } } } } }`, nil, http.StatusOK, ""},
+		{`{ newsList { edges { node { pictures { url } } } } pageInfo }`, nil, http.StatusBadRequest, `Cannot query field "pageInfo" on type "Query".`},
+		{`{ news(id: "unknown") { id } }`, nil, http.StatusOK, ""},
+		{``, nil, http.StatusBadRequest, "The query is required"},
+	}
+
+	for _, test := range tests {
+		status, result := graphQL(t, router, test.query, test.variables)
+
+		var message string
+		if len(result.Errors) > 0 {
+			message = result.Errors[0].Message
+		}
+
+		if status != test.status || message != test.message {
+			t.Errorf("%s %v: got %d %q, expected %d %q", test.query, test.variables, status, message, test.status, test.message)
+		}
+	}
+}
+
+func TestGraphQLGet(t *testing.T) {
+	router, _, _ := newGraphQLTest(t)
+
+	v := url.Values{}
+	v.Set("query", `query ($id: ID!) { news(id: $id) { id title source { id } } }`)
+	v.Set("variables", `{"id":"n3"}`)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/?"+v.Encode(), nil))
+
+	expected := `{"data":{"news":{"id":"n3","title":"Berita 3","source":{"id":"nst"}}}}`
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("got %d %s, expected %s", w.Code, w.Body.String(), expected)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/schema", nil))
+	if !strings.Contains(w.Body.String(), "newsList(filter: NewsFilter, first: Int = 20, after: String): NewsConnection!") {
+		t.Errorf("unexpected schema %s", w.Body.String())
+	}
+}
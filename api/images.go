@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ahmadmuzakkir/scrapenews/images"
+	"github.com/go-chi/chi"
+)
+
+// ImageHandler serves the pictures mirrored by images.Store, so the clients don't hotlink the newspapers.
+type ImageHandler struct {
+	images *images.Store
+}
+
+func NewImageHandler(images *images.Store) *ImageHandler {
+	return &ImageHandler{images: images}
+}
+
+func (h *ImageHandler) Routes() chi.Router {
+	router := chi.NewRouter()
+
+	router.Get("/{hash}", h.get)
+	router.Get("/{hash}/thumbnail", h.getThumbnail)
+	return router
+}
+
+func (h *ImageHandler) get(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, false)
+}
+
+func (h *ImageHandler) getThumbnail(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, true)
+}
+
+func (h *ImageHandler) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	hash := chi.URLParam(r, "hash")
+
+	f, err := h.images.Open(hash, thumbnail)
+	if err == images.ErrNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// The content never changes for a hash
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	if thumbnail {
+		w.Header().Set("ETag", `"`+hash+`-thumbnail"`)
+	} else {
+		w.Header().Set("ETag", `"`+hash+`"`)
+	}
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/go-chi/chi"
+)
+
+// keyRoutes manages the API keys, the key itself is only returned when it is created or rotated.
+func (n *NewsHandler) keyRoutes(router chi.Router) {
+	router.Get("/", n.getKeys)
+	router.Post("/", n.createKey)
+	router.Get("/{id}", n.getKey)
+	router.Put("/{id}", n.updateKey)
+	router.Post("/{id}/rotate", n.rotateKey)
+	router.Post("/{id}/revoke", n.revokeKey)
+}
+
+// createdKey is an API key along with the key itself.
+type createdKey struct {
+	*model.ApiKey
+	Key string `json:"key"`
+}
+
+func (n *NewsHandler) getKeys(w http.ResponseWriter, r *http.Request) {
+	list, err := n.Keys.List()
+	if err != nil {
+		n.logError("keys: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+func (n *NewsHandler) getKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := n.loadKey(w, r)
+	if !ok {
+		return
+	}
+
+	n.render(w, http.StatusOK, key)
+}
+
+func (n *NewsHandler) createKey(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.decodeKey(w, r)
+	if !ok {
+		return
+	}
+
+	key, token, err := n.Keys.Create(v.Name, v.Scopes, v.RateLimit, v.Expires)
+	if err != nil {
+		n.logError("create key: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusCreated, createdKey{key, token})
+}
+
+// updateKey replaces the name, the scopes, the rate limit and the expiry of the API key.
+func (n *NewsHandler) updateKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := n.loadKey(w, r)
+	if !ok {
+		return
+	}
+
+	v, ok := n.decodeKey(w, r)
+	if !ok {
+		return
+	}
+
+	key.Name = v.Name
+	key.Scopes = v.Scopes
+	key.RateLimit = v.RateLimit
+	key.Expires = v.Expires
+
+	if err := n.Keys.Save(key); err != nil {
+		n.logError("save key: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, key)
+}
+
+func (n *NewsHandler) rotateKey(w http.ResponseWriter, r *http.Request) {
+	key, token, err := n.Keys.Rotate(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "API key not found")
+		return
+	}
+	if err != nil {
+		n.logError("rotate key: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, createdKey{key, token})
+}
+
+func (n *NewsHandler) revokeKey(w http.ResponseWriter, r *http.Request) {
+	key, err := n.Keys.Revoke(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "API key not found")
+		return
+	}
+	if err != nil {
+		n.logError("revoke key: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, key)
+}
+
+func (n *NewsHandler) loadKey(w http.ResponseWriter, r *http.Request) (*model.ApiKey, bool) {
+	key, err := n.Keys.Get(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "API key not found")
+		return nil, false
+	}
+	if err != nil {
+		n.logError("key: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return nil, false
+	}
+
+	return key, true
+}
+
+// decodeKey decodes and validates the API key of the request body, the scopes default to read.
+func (n *NewsHandler) decodeKey(w http.ResponseWriter, r *http.Request) (*model.ApiKey, bool) {
+	var v struct {
+		Name      string     `json:"name"`
+		Scopes    []string   `json:"scopes"`
+		RateLimit int        `json:"rate_limit"`
+		Expires   *time.Time `json:"expires"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "Invalid JSON body")
+		return nil, false
+	}
+
+	if len(v.Scopes) == 0 {
+		v.Scopes = []string{model.ScopeRead}
+	}
+
+	for _, s := range v.Scopes {
+		if !auth.ValidScope(s) {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "The scopes must be read, export or admin")
+			return nil, false
+		}
+	}
+
+	if v.RateLimit < 0 {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The rate limit must not be negative")
+		return nil, false
+	}
+
+	return &model.ApiKey{
+		Name:      v.Name,
+		Scopes:    v.Scopes,
+		RateLimit: v.RateLimit,
+		Expires:   v.Expires,
+	}, true
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestKeyRoutes(t *testing.T) {
+	n, mem := newTestHandler(t)
+	router := n.Routes()
+
+	request := func(method, url, body string, v interface{}) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, url, strings.NewReader(body)))
+		if v != nil && w.Code < 300 {
+			if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Code
+	}
+
+	var created struct {
+		model.ApiKey
+		Key string `json:"key"`
+	}
+	if status := request("POST", "/admin/keys", `{"name":"app","rate_limit":10}`, &created); status != http.StatusCreated {
+		t.Fatalf("got %d", status)
+	}
+	if created.Key == "" || len(created.Scopes) != 1 || created.Scopes[0] != model.ScopeRead || created.RateLimit != 10 {
+		t.Errorf("got %+v", created)
+	}
+	if mem.Keys[0].Hash == created.Key || mem.Keys[0].Hash == "" {
+		t.Errorf("the key is stored as %q", mem.Keys[0].Hash)
+	}
+	if _, err := n.Keys.Authenticate(created.Key); err != nil {
+		t.Error(err)
+	}
+
+	var rotated struct {
+		Key string `json:"key"`
+	}
+	if status := request("POST", "/admin/keys/"+created.Id+"/rotate", "", &rotated); status != http.StatusOK || rotated.Key == created.Key {
+		t.Fatalf("got %d, %+v", status, rotated)
+	}
+	if _, err := n.Keys.Authenticate(created.Key); err != auth.ErrInvalidKey {
+		t.Errorf("got %v, expected %v", err, auth.ErrInvalidKey)
+	}
+
+	for _, body := range []string{`{"rate_limit":-1}`, `{"scopes":["read","unknown"]}`, `{`} {
+		if status := request("PUT", "/admin/keys/"+created.Id, body, nil); status != http.StatusBadRequest {
+			t.Errorf("%s: got %d", body, status)
+		}
+	}
+
+	var revoked model.ApiKey
+	if status := request("POST", "/admin/keys/"+created.Id+"/revoke", "", &revoked); status != http.StatusOK || revoked.Revoked == nil {
+		t.Fatalf("got %d, %+v", status, revoked)
+	}
+	if _, err := n.Keys.Authenticate(rotated.Key); err != auth.ErrInactiveKey {
+		t.Errorf("got %v, expected %v", err, auth.ErrInactiveKey)
+	}
+
+	// The key itself is never listed.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/keys", nil))
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), rotated.Key) || strings.Contains(w.Body.String(), mem.Keys[0].Hash) {
+		t.Errorf("got %d, %s", w.Code, w.Body.String())
+	}
+}
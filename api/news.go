@@ -5,31 +5,188 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
 	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/ahmadmuzakkir/scrapenews/taxonomy"
+	"github.com/ahmadmuzakkir/scrapenews/trend"
 	"github.com/go-chi/chi"
 )
 
 type NewsHandler struct {
-	Logger    *log.Logger
+	Logger *log.Logger
+	// Keys manages the API keys under /admin/keys, the routes are not added if it is nil.
+	Keys *auth.Keys
+	// Taxonomy maps the tags to the topics under /admin/topics, the routes are not added if it is nil.
+	Taxonomy *taxonomy.Taxonomy
+	// Trends serves the /trending route, the route is not added if it is nil.
+	Trends    *trend.Job
 	newsStore store.NewsStore
 }
 
 func NewNewsHandler(newsStore store.NewsStore) *NewsHandler {
-	return &NewsHandler{newsStore: newsStore}
+	return &NewsHandler{
+		Logger:    log.New(os.Stderr, "", log.LstdFlags),
+		newsStore: newsStore,
+	}
 }
 
 func (n *NewsHandler) Routes() chi.Router {
 	router := chi.NewRouter()
 
+	router.Get("/openapi.json", n.getOpenAPI)
 	router.Get("/get", n.get)
+	router.Get("/search", n.search)
+	router.Get("/news/{id}/related", n.getRelated)
+	router.Get("/stories", n.getStories)
+	router.Get("/stories/{id}", n.getStory)
+	router.Route("/stats", n.statsRoutes)
+	if n.Trends != nil {
+		router.Get("/trending", n.getTrending)
+	}
+	router.Route("/webhooks", n.webhookRoutes)
+	router.Route("/searches", n.searchRoutes)
+	if n.Keys != nil {
+		router.Route("/admin/keys", n.keyRoutes)
+	}
+	if n.Taxonomy != nil {
+		router.Route("/admin/topics", n.topicRoutes)
+	}
 	return router
 }
 
 func (n *NewsHandler) get(w http.ResponseWriter, r *http.Request) {
+	fields, err := parseFields(r)
+	if err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	filter := parseFilter(r)
+	filter.Fields = append(fields, "cluster_id")
+
+	list, err := n.newsStore.GetAll(filter)
+	if err != nil {
+		n.logError("latest: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	if r.FormValue("dedupe") == "true" {
+		list = store.Dedupe(list)
+	}
+
+	n.renderNews(w, list, fields)
+}
+
+func (n *NewsHandler) getStories(w http.ResponseWriter, r *http.Request) {
+	fromDatetime, untilDatetime := parseRange(r)
+
+	list, err := n.newsStore.GetStories(fromDatetime, untilDatetime)
+	if err != nil {
+		n.logError("stories: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+// getStory returns the story along with its news.
+func (n *NewsHandler) getStory(w http.ResponseWriter, r *http.Request) {
+	fields, err := parseFields(r)
+	if err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	story, err := n.newsStore.GetStory(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Story not found")
+		return
+	}
+	if err != nil {
+		n.logError("story: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	story.News = make([]*model.News, 0, len(story.NewsIds))
+	for _, id := range story.NewsIds {
+		news, err := n.newsStore.Get(id)
+		if err == store.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			n.logError("get: %s", err)
+			n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+			return
+		}
+
+		story.News = append(story.News, news)
+	}
+
+	news, err := project(story.News, fields)
+	if err != nil {
+		n.logError("project: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, struct {
+		*model.Story
+		News []map[string]json.RawMessage `json:"news"`
+	}{story, news})
+}
+
+// getRelated returns the near-duplicates of the news, e.g the same story published by the other newspapers.
+func (n *NewsHandler) getRelated(w http.ResponseWriter, r *http.Request) {
+	fields, err := parseFields(r)
+	if err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	news, err := n.newsStore.Get(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "News not found")
+		return
+	}
+	if err != nil {
+		n.logError("get: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	var related = make([]*model.News, 0)
+
+	if news.ClusterId != "" {
+		list, err := n.newsStore.GetByCluster(news.ClusterId)
+		if err != nil {
+			n.logError("cluster: %s", err)
+			n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+			return
+		}
+
+		for _, v := range list {
+			if v.Id != news.Id {
+				related = append(related, v)
+			}
+		}
+	}
+
+	n.renderNews(w, related, fields)
+}
+
+// parseRange returns the from and until query parameters. from is the latest datetime and defaults to now,
+// until is the earliest and defaults to a day ago.
+func parseRange(r *http.Request) (time.Time, time.Time) {
 	fromDatetimeStr := r.FormValue("from")
 	untilDatetimeStr := r.FormValue("until")
 
@@ -52,13 +209,44 @@ func (n *NewsHandler) get(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	list, err := n.newsStore.GetAll(fromDatetime, untilDatetime)
+	return fromDatetime, untilDatetime
+}
+
+// parseLimit returns the limit parameter, it defaults to 50 and is at most 500.
+func parseLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.FormValue("limit"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	return limit
+}
+
+// parseFilter returns the filter of the listing query parameters.
+func parseFilter(r *http.Request) store.Filter {
+	fromDatetime, untilDatetime := parseRange(r)
+
+	return store.Filter{
+		From:       fromDatetime,
+		Until:      untilDatetime,
+		Language:   r.FormValue("lang"),
+		Entity:     r.FormValue("entity"),
+		EntityType: r.FormValue("entity_type"),
+		Newspaper:  r.FormValue("newspaper"),
+		Tag:        r.FormValue("tag"),
+		Topic:      r.FormValue("topic"),
+	}
+}
+
+// renderNews renders the news with only the fields, see parseFields.
+func (n *NewsHandler) renderNews(w http.ResponseWriter, list []*model.News, fields []string) {
+	projection, err := project(list, fields)
 	if err != nil {
-		n.logError("latest: %s", err)
+		n.logError("project: %s", err)
 		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
 	}
 
-	n.render(w, http.StatusOK, list)
+	n.render(w, http.StatusOK, projection)
 }
 
 func (n *NewsHandler) render(w http.ResponseWriter, status int, data interface{}) {
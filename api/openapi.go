@@ -0,0 +1,16 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openapi is the OpenAPI 3 document of the routes, the contract tests check the handlers against it.
+//
+//go:embed openapi.json
+var openapi []byte
+
+func (n *NewsHandler) getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapi)
+}
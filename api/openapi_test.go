@@ -0,0 +1,506 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+	"github.com/ahmadmuzakkir/scrapenews/taxonomy"
+	"github.com/ahmadmuzakkir/scrapenews/trend"
+	"github.com/go-chi/chi"
+)
+
+// mountedElsewhere are the paths of the document served by the other handlers, see main.go.
+var mountedElsewhere = map[string]bool{
+	"/stream":                  true,
+	"/graphql":                 true,
+	"/graphql/schema":          true,
+	"/images/{hash}":           true,
+	"/images/{hash}/thumbnail": true,
+}
+
+type spec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas   map[string]*schema   `json:"schemas"`
+		Responses map[string]*response `json:"responses"`
+	} `json:"components"`
+}
+
+type operation struct {
+	Responses map[string]*response `json:"responses"`
+}
+
+type response struct {
+	Ref     string `json:"$ref"`
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Nullable             bool               `json:"nullable"`
+	Enum                 []interface{}      `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+}
+
+func loadSpec(t *testing.T) *spec {
+	s := &spec{}
+	if err := json.Unmarshal(openapi, s); err != nil {
+		t.Fatalf("openapi.json: %s", err)
+	}
+	return s
+}
+
+func (s *spec) operation(path string, method string) (*operation, bool) {
+	raw, exist := s.Paths[path][strings.ToLower(method)]
+	if !exist {
+		return nil, false
+	}
+
+	op := &operation{}
+	if err := json.Unmarshal(raw, op); err != nil {
+		return nil, false
+	}
+	return op, true
+}
+
+// responseSchema returns the JSON schema of the response, nil if the response has no JSON body.
+func (s *spec) responseSchema(path string, method string, status int) (*schema, error) {
+	op, exist := s.operation(path, method)
+	if !exist {
+		return nil, fmt.Errorf("%s %s is not documented", method, path)
+	}
+
+	resp, exist := op.Responses[fmt.Sprint(status)]
+	if !exist {
+		return nil, fmt.Errorf("%s %s: the status %d is not documented", method, path, status)
+	}
+
+	if resp.Ref != "" {
+		resp = s.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+		if resp == nil {
+			return nil, fmt.Errorf("%s %s: unknown response %d", method, path, status)
+		}
+	}
+
+	content, exist := resp.Content["application/json"]
+	if !exist {
+		return nil, nil
+	}
+	return content.Schema, nil
+}
+
+func (s *spec) resolve(sc *schema) *schema {
+	for sc.Ref != "" {
+		sc = s.Components.Schemas[strings.TrimPrefix(sc.Ref, "#/components/schemas/")]
+	}
+	return sc
+}
+
+// properties returns the properties of the schema, along with those of its allOf.
+func (s *spec) properties(sc *schema) map[string]*schema {
+	sc = s.resolve(sc)
+
+	var props = make(map[string]*schema)
+	for k, v := range sc.Properties {
+		props[k] = v
+	}
+	for _, sub := range sc.AllOf {
+		for k, v := range s.properties(sub) {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// validate checks the value against the subset of JSON schema used by the document.
+// The properties not in the schema are errors, unless it has additionalProperties or no properties at all.
+func (s *spec) validate(sc *schema, v interface{}, at string) error {
+	sc = s.resolve(sc)
+
+	if v == nil {
+		if sc.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not nullable", at)
+	}
+
+	for _, sub := range sc.AllOf {
+		if err := s.validateOpen(sub, v, at); err != nil {
+			return err
+		}
+	}
+
+	if len(sc.Enum) > 0 {
+		var found bool
+		for _, e := range sc.Enum {
+			if e == v {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", at, v, sc.Enum)
+		}
+	}
+
+	switch sc.Type {
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", at, v)
+		}
+		if sc.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s: %s", at, err)
+			}
+		}
+
+	case "integer", "number":
+		f, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a number", at, v)
+		}
+		if sc.Type == "integer" && f != float64(int64(f)) {
+			return fmt.Errorf("%s: %v is not an integer", at, v)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", at, v)
+		}
+
+	case "array":
+		list, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", at, v)
+		}
+		for i, item := range list {
+			if err := s.validate(sc.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+
+	case "object", "":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			if sc.Type == "" {
+				return nil
+			}
+			return fmt.Errorf("%s: %v is not an object", at, v)
+		}
+
+		for _, name := range sc.Required {
+			if _, exist := obj[name]; !exist {
+				return fmt.Errorf("%s: the required %s is missing", at, name)
+			}
+		}
+
+		props := s.properties(sc)
+		for name, value := range obj {
+			prop, exist := props[name]
+			if !exist {
+				if len(sc.AdditionalProperties) == 0 || string(sc.AdditionalProperties) == "false" {
+					// A schema without properties is free-form, e.g the document itself.
+					if len(props) == 0 {
+						continue
+					}
+					return fmt.Errorf("%s: %s is not documented", at, name)
+				}
+				if string(sc.AdditionalProperties) == "true" {
+					continue
+				}
+
+				prop = &schema{}
+				if err := json.Unmarshal(sc.AdditionalProperties, prop); err != nil {
+					return err
+				}
+			}
+
+			if err := s.validate(prop, value, at+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateOpen validates an allOf schema, the properties of the other schemas are allowed.
+func (s *spec) validateOpen(sc *schema, v interface{}, at string) error {
+	sc = s.resolve(sc)
+
+	open := *sc
+	open.AdditionalProperties = json.RawMessage("true")
+	return s.validate(&open, v, at)
+}
+
+func newTestHandler(t *testing.T) (*NewsHandler, *storetest.Memory) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	news := &model.News{
+		Id:       "n1",
+		Author:   "Author",
+		Datetime: now,
+		Title:    "Banjir di Kelantan",
+		Location: "Kota Bharu",
+		Pictures: []*model.Picture{
+			{ImageUrl: "http://example.com/a.jpg", Caption: "Caption", Hash: "abc", Width: 10, Height: 10, MimeType: "image/jpeg"},
+		},
+		Tags:        []string{"banjir"},
+		Url:         "http://example.com/n1",
+		Source:      model.BhSources[0],
+		Html:        "<p>Banjir</p>",
+		Lead:        "Banjir",
+		Summary:     "Banjir",
+		Language:    "ms",
+		ClusterId:   "n1",
+		Entities:    []*model.Entity{{Id: "e1", Type: "place", Name: "Kelantan", Count: 1}},
+		Topics:      []string{"nation"},
+		Annotations: map[string]string{"stage": "note"},
+	}
+	news.SetContent("Banjir di Kelantan.\nHujan lebat.")
+
+	related := *news
+	related.Id = "n2"
+	related.Url = "http://example.com/n2"
+
+	s := &storetest.Memory{
+		News: []*model.News{news, &related},
+		Stories: []*model.Story{{
+			Id: "s1", Label: "Banjir", Keywords: []string{"banjir"}, FirstDatetime: now, LastDatetime: now,
+			Newspapers: []string{model.BharianId}, NewsIds: []string{"n1", "n2"},
+		}},
+		Deliveries: []*model.Delivery{{
+			Id: "d1", WebhookId: "w1", NewsIds: []string{"n1"}, Attempt: 1, Datetime: now, StatusCode: 500, Error: "Server error", Duration: 10,
+		}},
+		Alerts: []*model.Alert{{
+			Id: "a1", SearchId: "s1", NewsId: "n1", Title: news.Title, Url: news.Url, Newspaper: model.BharianId,
+			Datetime: now, Created: now, Delivered: &now,
+		}},
+		Topics: []*model.Topic{{Id: "nation", Names: map[string]string{"en": "Nation", "ms": "Nasional"}, Created: now, Updated: now}},
+	}
+
+	keys, err := auth.NewKeys(s, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := NewNewsHandler(s)
+	n.Logger = log.New(ioutil.Discard, "", 0)
+	n.Keys = keys
+	n.Taxonomy = taxonomy.New(s.Topics)
+	n.Trends = trend.NewJob(s)
+	return n, s
+}
+
+// routePath returns the path of the document of the chi route pattern, e.g /webhooks/*/{id} to /webhooks/{id}.
+func routePath(route string) string {
+	route = strings.Replace(route, "/*/", "/", -1)
+	route = strings.TrimSuffix(route, "/*")
+	if route != "/" {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	s := loadSpec(t)
+	n, _ := newTestHandler(t)
+
+	var routes = make(map[string]bool)
+	err := chi.Walk(n.Routes(), func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		path := routePath(route)
+		routes[method+" "+path] = true
+
+		if _, exist := s.operation(path, method); !exist {
+			t.Errorf("%s %s is not documented", method, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, methods := range s.Paths {
+		if mountedElsewhere[path] {
+			continue
+		}
+
+		for method := range methods {
+			if method == "parameters" {
+				continue
+			}
+
+			if !routes[strings.ToUpper(method)+" "+path] {
+				t.Errorf("%s %s is documented but not routed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	s := loadSpec(t)
+	n, mem := newTestHandler(t)
+	router := n.Routes()
+
+	key, _, err := n.Keys.Create("test", []string{model.ScopeRead}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mem.Webhooks = append(mem.Webhooks, &model.Webhook{Id: "w1", Url: "http://example.com/hook", Secret: "secret", Created: time.Now()})
+	mem.Searches = append(mem.Searches, &model.SavedSearch{
+		Id: "s1", Name: "Banjir", Query: "banjir", Frequency: model.Immediate, Notifier: "email", Target: "a@example.com", Created: time.Now(),
+	})
+
+	all := "fields=*,content,paragraphs,html"
+
+	tests := []struct {
+		method string
+		// path is the path of the document, url is requested.
+		path   string
+		url    string
+		body   string
+		status int
+	}{
+		{"GET", "/openapi.json", "/openapi.json", "", 200},
+		{"GET", "/get", "/get?" + all, "", 200},
+		{"GET", "/get", "/get?dedupe=true", "", 200},
+		{"GET", "/get", "/get?fields=unknown", "", 400},
+		{"GET", "/get", "/get?topic=nation&fields=id,topics", "", 200},
+		{"GET", "/search", "/search?q=banjir&" + all, "", 200},
+		{"GET", "/search", "/search", "", 400},
+		{"GET", "/news/{id}/related", "/news/n1/related?" + all, "", 200},
+		{"GET", "/news/{id}/related", "/news/unknown/related", "", 404},
+		{"GET", "/stories", "/stories", "", 200},
+		{"GET", "/stories/{id}", "/stories/s1?" + all, "", 200},
+		{"GET", "/stories/{id}", "/stories/unknown", "", 404},
+		{"GET", "/stats/counts", "/stats/counts?by=tag&newspaper=bharian", "", 200},
+		{"GET", "/stats/counts", "/stats/counts?by=unknown", "", 400},
+		{"GET", "/stats/histogram", "/stats/histogram?interval=hour&by=newspaper&tz=UTC", "", 200},
+		{"GET", "/stats/histogram", "/stats/histogram?tz=Mars/Olympus", "", 400},
+		{"GET", "/stats/top-authors", "/stats/top-authors?limit=5", "", 200},
+		{"GET", "/stats/sources", "/stats/sources?newspaper=bharian", "", 200},
+		{"GET", "/trending", "/trending?window=6h&lang=ms&kind=term", "", 200},
+		{"GET", "/trending", "/trending?window=5m", "", 400},
+
+		{"GET", "/webhooks", "/webhooks", "", 200},
+		{"POST", "/webhooks", "/webhooks", `{"url":"http://example.com/new","tags":["banjir"]}`, 201},
+		{"POST", "/webhooks", "/webhooks", `{"url":"example"}`, 400},
+		{"GET", "/webhooks/{id}", "/webhooks/w1", "", 200},
+		{"GET", "/webhooks/{id}", "/webhooks/unknown", "", 404},
+		{"PUT", "/webhooks/{id}", "/webhooks/w1", `{"url":"http://example.com/updated"}`, 200},
+		{"PUT", "/webhooks/{id}", "/webhooks/w1", `{`, 400},
+		{"GET", "/webhooks/{id}/deliveries", "/webhooks/w1/deliveries", "", 200},
+		{"DELETE", "/webhooks/{id}", "/webhooks/w1", "", 204},
+		{"DELETE", "/webhooks/{id}", "/webhooks/w1", "", 404},
+
+		{"GET", "/searches", "/searches", "", 200},
+		{"POST", "/searches", "/searches", `{"query":"banjir","notifier":"email","target":"a@example.com"}`, 201},
+		{"POST", "/searches", "/searches", `{"notifier":"email","target":"a@example.com"}`, 400},
+		{"POST", "/searches", "/searches", `{"query":"banjir","notifier":"slack","target":"hooks.slack.com/x"}`, 400},
+		{"POST", "/searches", "/searches", `{"query":"banjir","notifier":"slack","target":"ftp://hooks.slack.com/x"}`, 400},
+		{"POST", "/searches", "/searches", `{"query":"banjir","notifier":"slack","target":"https:///x"}`, 400},
+		{"GET", "/searches/{id}", "/searches/s1", "", 200},
+		{"PUT", "/searches/{id}", "/searches/s1", `{"query":"banjir kilat","frequency":"daily","notifier":"slack","target":"https://hooks.slack.com/x"}`, 200},
+		{"GET", "/searches/{id}/alerts", "/searches/s1/alerts", "", 200},
+		{"DELETE", "/searches/{id}", "/searches/s1", "", 204},
+		{"GET", "/searches/{id}", "/searches/s1", "", 404},
+
+		{"GET", "/admin/keys", "/admin/keys", "", 200},
+		{"POST", "/admin/keys", "/admin/keys", `{"name":"new","scopes":["read","export"],"rate_limit":10}`, 201},
+		{"POST", "/admin/keys", "/admin/keys", `{"scopes":["unknown"]}`, 400},
+		{"GET", "/admin/keys/{id}", "/admin/keys/" + key.Id, "", 200},
+		{"GET", "/admin/keys/{id}", "/admin/keys/unknown", "", 404},
+		{"PUT", "/admin/keys/{id}", "/admin/keys/" + key.Id, `{"name":"renamed","scopes":["read"]}`, 200},
+		{"POST", "/admin/keys/{id}/rotate", "/admin/keys/" + key.Id + "/rotate", "", 200},
+		{"POST", "/admin/keys/{id}/revoke", "/admin/keys/" + key.Id + "/revoke", "", 200},
+		{"POST", "/admin/keys/{id}/revoke", "/admin/keys/unknown/revoke", "", 404},
+
+		{"GET", "/admin/topics", "/admin/topics", "", 200},
+		{"POST", "/admin/topics", "/admin/topics", `{"id":"flood","names":{"en":"Flood","ms":"Banjir"},"aliases":["Bencana"]}`, 201},
+		{"POST", "/admin/topics", "/admin/topics", `{"id":"flood"}`, 409},
+		{"POST", "/admin/topics", "/admin/topics", `{"id":"Flood Relief"}`, 400},
+		{"GET", "/admin/topics/{id}", "/admin/topics/flood", "", 200},
+		{"GET", "/admin/topics/{id}", "/admin/topics/unknown", "", 404},
+		{"PUT", "/admin/topics/{id}", "/admin/topics/flood", `{"names":{"en":"Floods"},"aliases":["Banjir Kilat"," "]}`, 200},
+		{"PUT", "/admin/topics/{id}", "/admin/topics/flood", `{"aliases":["a,b"]}`, 400},
+		{"POST", "/admin/topics/retag", "/admin/topics/retag", "", 200},
+		{"DELETE", "/admin/topics/{id}", "/admin/topics/flood", "", 204},
+		{"DELETE", "/admin/topics/{id}", "/admin/topics/flood", "", 404},
+	}
+
+	var covered = make(map[string]bool)
+
+	for _, test := range tests {
+		name := test.method + " " + test.url
+
+		r := httptest.NewRequest(test.method, test.url, strings.NewReader(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != test.status {
+			t.Errorf("%s: got status %d, expected %d: %s", name, w.Code, test.status, w.Body.String())
+			continue
+		}
+
+		sc, err := s.responseSchema(test.path, test.method, w.Code)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		covered[test.method+" "+test.path] = true
+
+		if sc == nil {
+			if w.Body.Len() > 0 {
+				t.Errorf("%s: unexpected body %s", name, w.Body.String())
+			}
+			continue
+		}
+
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s: got content type %s", name, ct)
+		}
+
+		var body interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+
+		if err := s.validate(sc, body, "body"); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+	}
+
+	// Every route of the handler is exercised at least once.
+	var missing []string
+	chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if !covered[method+" "+routePath(route)] {
+			missing = append(missing, method+" "+routePath(route))
+		}
+		return nil
+	})
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("not exercised: %s", strings.Join(missing, ", "))
+	}
+}
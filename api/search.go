@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// search returns the news, selected by the listing query parameters, containing every word of the q parameter.
+// The words are stemmed in the language of each news, so banjir matches kebanjiran and flood matches flooding.
+func (n *NewsHandler) search(w http.ResponseWriter, r *http.Request) {
+	query := r.FormValue("q")
+	if query == "" {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The q parameter is required")
+		return
+	}
+
+	fields, err := parseFields(r)
+	if err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	// The words are matched against the title and the content.
+	filter := parseFilter(r)
+	filter.Fields = append(fields, "cluster_id", "title", "content", "language")
+
+	list, err := n.newsStore.GetAll(filter)
+	if err != nil {
+		n.logError("search: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	var result = make([]*model.News, 0)
+	for _, v := range list {
+		if matches(v, query) {
+			result = append(result, v)
+		}
+	}
+
+	if r.FormValue("dedupe") == "true" {
+		result = store.Dedupe(result)
+	}
+
+	n.renderNews(w, result, fields)
+}
+
+func matches(n *model.News, query string) bool {
+	return language.Contains(n.Title+"\n"+n.Content, query, n.Language)
+}
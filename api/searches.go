@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/ahmadmuzakkir/scrapenews/webhook"
+	"github.com/go-chi/chi"
+)
+
+// searchRoutes manages the saved searches of the client, their alerts are delivered by alert.Alerter.
+func (n *NewsHandler) searchRoutes(router chi.Router) {
+	router.Get("/", n.getSearches)
+	router.Post("/", n.createSearch)
+	router.Get("/{id}", n.getSearch)
+	router.Put("/{id}", n.updateSearch)
+	router.Delete("/{id}", n.deleteSearch)
+	router.Get("/{id}/alerts", n.getAlerts)
+}
+
+// owner returns the id of the client of the request, the owner of its saved searches. It is empty while the API is open.
+func owner(r *http.Request) string {
+	if p := auth.FromContext(r.Context()); p != nil {
+		return p.Id
+	}
+	return ""
+}
+
+func (n *NewsHandler) getSearches(w http.ResponseWriter, r *http.Request) {
+	list, err := n.newsStore.GetSearches(owner(r))
+	if err != nil {
+		n.logError("searches: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+func (n *NewsHandler) getSearch(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.loadSearch(w, r)
+	if !ok {
+		return
+	}
+
+	n.render(w, http.StatusOK, v)
+}
+
+func (n *NewsHandler) createSearch(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.decodeSearch(w, r)
+	if !ok {
+		return
+	}
+
+	v.Id = webhook.NewId()
+	v.Owner = owner(r)
+	v.Created = time.Now()
+
+	if err := n.newsStore.SaveSearch(v); err != nil {
+		n.logError("save search: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusCreated, v)
+}
+
+func (n *NewsHandler) updateSearch(w http.ResponseWriter, r *http.Request) {
+	existing, ok := n.loadSearch(w, r)
+	if !ok {
+		return
+	}
+
+	v, ok := n.decodeSearch(w, r)
+	if !ok {
+		return
+	}
+
+	v.Id = existing.Id
+	v.Owner = existing.Owner
+	v.Created = existing.Created
+
+	if err := n.newsStore.SaveSearch(v); err != nil {
+		n.logError("save search: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, v)
+}
+
+func (n *NewsHandler) deleteSearch(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.loadSearch(w, r)
+	if !ok {
+		return
+	}
+
+	err := n.newsStore.DeleteSearch(v.Id)
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Search not found")
+		return
+	}
+	if err != nil {
+		n.logError("delete search: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAlerts returns the latest alerts of the saved search, the limit parameter defaults to 50.
+func (n *NewsHandler) getAlerts(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.loadSearch(w, r)
+	if !ok {
+		return
+	}
+
+	limit := parseLimit(r)
+
+	list, err := n.newsStore.GetAlerts(v.Id, limit)
+	if err != nil {
+		n.logError("alerts: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+// loadSearch returns the saved search of the id, the saved searches of the other clients are not found.
+func (n *NewsHandler) loadSearch(w http.ResponseWriter, r *http.Request) (*model.SavedSearch, bool) {
+	v, err := n.newsStore.GetSearch(chi.URLParam(r, "id"))
+	if o := owner(r); err == nil && o != "" && v.Owner != o {
+		err = store.ErrNotFound
+	}
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Search not found")
+		return nil, false
+	}
+	if err != nil {
+		n.logError("search: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return nil, false
+	}
+
+	return v, true
+}
+
+// decodeSearch decodes and validates the saved search of the request body, the frequency defaults to immediate.
+func (n *NewsHandler) decodeSearch(w http.ResponseWriter, r *http.Request) (*model.SavedSearch, bool) {
+	var v struct {
+		Name       string   `json:"name"`
+		Query      string   `json:"query"`
+		Newspapers []string `json:"newspapers"`
+		Language   string   `json:"language"`
+		Frequency  string   `json:"frequency"`
+		Notifier   string   `json:"notifier"`
+		Target     string   `json:"target"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "Invalid JSON body")
+		return nil, false
+	}
+
+	if strings.TrimSpace(v.Query) == "" {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The query must not be empty")
+		return nil, false
+	}
+
+	// The newspapers are stored comma separated.
+	for _, item := range v.Newspapers {
+		if strings.TrimSpace(item) == "" || strings.Contains(item, ",") {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "The newspapers must not be empty nor contain a comma")
+			return nil, false
+		}
+	}
+
+	switch v.Frequency {
+	case "":
+		v.Frequency = model.Immediate
+	case model.Immediate, model.Hourly, model.Daily:
+	default:
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The frequency must be immediate, hourly or daily")
+		return nil, false
+	}
+
+	switch v.Notifier {
+	case "email":
+		if _, err := mail.ParseAddress(v.Target); err != nil {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "The target must be an email address")
+			return nil, false
+		}
+	case "slack":
+		if !isHttpUrl(v.Target) {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "The target must be an absolute http or https url")
+			return nil, false
+		}
+	default:
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The notifier must be email or slack")
+		return nil, false
+	}
+
+	return &model.SavedSearch{
+		Name:       v.Name,
+		Query:      v.Query,
+		Newspapers: v.Newspapers,
+		Language:   v.Language,
+		Frequency:  v.Frequency,
+		Notifier:   v.Notifier,
+		Target:     v.Target,
+	}, true
+}
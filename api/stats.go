@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/go-chi/chi"
+)
+
+// The time zone of the histograms without the tz parameter, the newspapers are Malaysian.
+const defaultTimeZone = "Asia/Kuala_Lumpur"
+
+// statsRoutes aggregates the news selected by the listing query parameters, e.g the news of a tag per newspaper per day.
+func (n *NewsHandler) statsRoutes(router chi.Router) {
+	router.Get("/counts", n.getCounts)
+	router.Get("/histogram", n.getHistogram)
+	router.Get("/top-authors", n.getTopAuthors)
+	router.Get("/sources", n.getSourceHealth)
+}
+
+// getCounts returns the number of news by the values of the by parameter, e.g newspaper or tag, most first.
+// The limit parameter defaults to 50.
+func (n *NewsHandler) getCounts(w http.ResponseWriter, r *http.Request) {
+	group := r.FormValue("by")
+	if !store.ValidGroup(group) {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The by parameter must be newspaper, category, subcategory, tag, author or location")
+		return
+	}
+
+	list, err := n.newsStore.CountBy(parseFilter(r), group, parseLimit(r))
+	if err != nil {
+		n.logError("counts: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+// getHistogram returns the number of news per hour, day or week of the interval parameter, oldest first,
+// in the time zone of the tz parameter. The news are counted by the values of the by parameter if it is given.
+func (n *NewsHandler) getHistogram(w http.ResponseWriter, r *http.Request) {
+	interval := r.FormValue("interval")
+	if interval == "" {
+		interval = store.Day
+	}
+	if !store.ValidInterval(interval) {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The interval parameter must be hour, day or week")
+		return
+	}
+
+	group := r.FormValue("by")
+	if group != "" && !store.ValidGroup(group) {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The by parameter must be newspaper, category, subcategory, tag, author or location")
+		return
+	}
+
+	tz := r.FormValue("tz")
+	if tz == "" {
+		tz = defaultTimeZone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "Unknown time zone "+tz)
+		return
+	}
+
+	list, err := n.newsStore.Histogram(parseFilter(r), interval, group, loc)
+	if err != nil {
+		n.logError("histogram: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+// getTopAuthors returns the authors with the most news, along with their newspaper. The limit parameter defaults to 50.
+func (n *NewsHandler) getTopAuthors(w http.ResponseWriter, r *http.Request) {
+	list, err := n.newsStore.TopAuthors(parseFilter(r), parseLimit(r))
+	if err != nil {
+		n.logError("top authors: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+// getSourceHealth returns how the fields of the news of every newspaper were extracted, by the structured data or by the selectors,
+// and the fields the newspaper is failing to provide.
+func (n *NewsHandler) getSourceHealth(w http.ResponseWriter, r *http.Request) {
+	filter := parseFilter(r)
+	filter.Fields = store.HealthFields
+
+	list, err := n.newsStore.GetAll(filter)
+	if err != nil {
+		n.logError("source health: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, store.Health(list))
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/pubsub"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/go-chi/chi"
+)
+
+// The interval of the comments keeping the idle connections open.
+const heartbeatInterval = 15 * time.Second
+
+// StreamHandler pushes the inserted news as Server-Sent Events.
+type StreamHandler struct {
+	Logger *log.Logger
+	broker *pubsub.Broker
+}
+
+func NewStreamHandler(broker *pubsub.Broker) *StreamHandler {
+	return &StreamHandler{
+		Logger: log.New(os.Stderr, "", log.LstdFlags),
+		broker: broker,
+	}
+}
+
+func (h *StreamHandler) Routes() chi.Router {
+	router := chi.NewRouter()
+
+	router.Get("/", h.stream)
+	return router
+}
+
+// streamFilter selects the news pushed, from the query parameters newspaper, lang, entity, entity_type and q.
+type streamFilter struct {
+	store.Filter
+	newspapers []string
+	query      string
+}
+
+func (f *streamFilter) match(n *model.News) bool {
+	if len(f.newspapers) > 0 {
+		var found bool
+		for _, v := range f.newspapers {
+			if v == n.Source.NewspaperId {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if f.query != "" && !language.Contains(n.Title+"\n"+n.Content, f.query, n.Language) {
+		return false
+	}
+
+	return f.Match(n)
+}
+
+// stream resumes after the Last-Event-ID header, or the last_event_id parameter, if the events are still kept.
+// The fields parameter selects the fields of the news, see parseFields.
+func (h *StreamHandler) stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	fields, err := parseFields(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := &streamFilter{
+		Filter: store.Filter{
+			Language:   r.FormValue("lang"),
+			Entity:     r.FormValue("entity"),
+			EntityType: r.FormValue("entity_type"),
+		},
+		query: r.FormValue("q"),
+	}
+	if v := r.FormValue("newspaper"); v != "" {
+		filter.newspapers = strings.Split(v, ",")
+	}
+
+	lastEventId := r.Header.Get("Last-Event-ID")
+	if lastEventId == "" {
+		lastEventId = r.FormValue("last_event_id")
+	}
+	lastId, _ := strconv.ParseUint(lastEventId, 10, 64)
+
+	missed, sub := h.broker.Subscribe(lastId)
+	defer h.broker.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Tell the clients to wait before reconnecting.
+	fmt.Fprint(w, "retry: 5000\n\n")
+
+	for _, e := range missed {
+		if err := h.write(w, e, filter, fields); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := h.write(w, e, filter, fields); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StreamHandler) write(w http.ResponseWriter, e pubsub.Event, filter *streamFilter, fields []string) error {
+	if !filter.match(e.News) {
+		return nil
+	}
+
+	projection, err := project([]*model.News{e.News}, fields)
+	if err != nil {
+		h.Logger.Printf("ERROR: stream: %s", err)
+		return nil
+	}
+
+	data, err := json.Marshal(projection[0])
+	if err != nil {
+		h.Logger.Printf("ERROR: stream: %s", err)
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: news\ndata: %s\n\n", e.Id, data)
+	return err
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/pubsub"
+)
+
+// readEvent returns the id and the data of the next event, skipping the retry and the comments.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	var id, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && id != "":
+			return id, data
+		}
+	}
+}
+
+func TestStream(t *testing.T) {
+	broker := pubsub.NewBroker(10)
+	broker.Inserted([]*model.News{
+		{Id: "a", Source: model.NewsSource{NewspaperId: "bh"}},
+		{Id: "b", Source: model.NewsSource{NewspaperId: "nst"}},
+		{Id: "c", Source: model.NewsSource{NewspaperId: "bh"}},
+	})
+
+	server := httptest.NewServer(NewStreamHandler(broker).Routes())
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/?newspaper=bh&fields=id", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("got %d, %v", resp.StatusCode, resp.Header)
+	}
+	r := bufio.NewReader(resp.Body)
+
+	// The events after the last one are replayed, b is filtered out.
+	if id, data := readEvent(t, r); id != "3" || data != `{"id":"c"}` {
+		t.Errorf("got %s %s", id, data)
+	}
+
+	// Then the inserted news are pushed.
+	broker.Inserted([]*model.News{
+		{Id: "d", Source: model.NewsSource{NewspaperId: "nst"}},
+		{Id: "e", Source: model.NewsSource{NewspaperId: "bh"}},
+	})
+	if id, data := readEvent(t, r); id != "5" || data != `{"id":"e"}` {
+		t.Errorf("got %s %s", id, data)
+	}
+}
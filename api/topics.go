@@ -0,0 +1,213 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/ahmadmuzakkir/scrapenews/taxonomy"
+	"github.com/go-chi/chi"
+)
+
+// topicRoutes manages the topics of the taxonomy. The taxonomy maps the news ingested after a change,
+// the news already stored are mapped again by retag.
+func (n *NewsHandler) topicRoutes(router chi.Router) {
+	router.Get("/", n.getTopics)
+	router.Post("/", n.createTopic)
+	router.Post("/retag", n.retag)
+	router.Get("/{id}", n.getTopic)
+	router.Put("/{id}", n.updateTopic)
+	router.Delete("/{id}", n.deleteTopic)
+}
+
+func (n *NewsHandler) getTopics(w http.ResponseWriter, r *http.Request) {
+	list, err := n.newsStore.GetTopics()
+	if err != nil {
+		n.logError("topics: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+func (n *NewsHandler) getTopic(w http.ResponseWriter, r *http.Request) {
+	topic, ok := n.loadTopic(w, r)
+	if !ok {
+		return
+	}
+
+	n.render(w, http.StatusOK, topic)
+}
+
+func (n *NewsHandler) createTopic(w http.ResponseWriter, r *http.Request) {
+	topic, ok := n.decodeTopic(w, r)
+	if !ok {
+		return
+	}
+
+	if topic.Id == "" || taxonomy.Slug(topic.Id) != topic.Id {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The id must be lower case words separated by hyphens, e.g foreign-affairs")
+		return
+	}
+
+	_, err := n.newsStore.GetTopic(topic.Id)
+	if err == nil {
+		n.renderError(w, http.StatusConflict, "Conflict", "Topic "+topic.Id+" already exists")
+		return
+	}
+	if err != store.ErrNotFound {
+		n.logError("topic: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	topic.Created = time.Now()
+	topic.Updated = topic.Created
+
+	if !n.saveTopic(w, topic) {
+		return
+	}
+
+	n.render(w, http.StatusCreated, topic)
+}
+
+// updateTopic replaces the names and the aliases of the topic, its id does not change.
+func (n *NewsHandler) updateTopic(w http.ResponseWriter, r *http.Request) {
+	topic, ok := n.loadTopic(w, r)
+	if !ok {
+		return
+	}
+
+	v, ok := n.decodeTopic(w, r)
+	if !ok {
+		return
+	}
+
+	topic.Names = v.Names
+	topic.Aliases = v.Aliases
+	topic.Updated = time.Now()
+
+	if !n.saveTopic(w, topic) {
+		return
+	}
+
+	n.render(w, http.StatusOK, topic)
+}
+
+func (n *NewsHandler) deleteTopic(w http.ResponseWriter, r *http.Request) {
+	err := n.newsStore.DeleteTopic(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Topic not found")
+		return
+	}
+	if err != nil {
+		n.logError("delete topic: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	if !n.reloadTaxonomy(w) {
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// retag maps every news stored to the topics of the taxonomy, it returns the number of news and of those whose topics changed.
+func (n *NewsHandler) retag(w http.ResponseWriter, r *http.Request) {
+	list, err := n.newsStore.GetAll(store.Filter{Fields: []string{"tags", "source", "topics"}})
+	if err != nil {
+		n.logError("retag: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	changed := n.Taxonomy.Retag(list)
+	if err := n.newsStore.SetTopics(changed); err != nil {
+		n.logError("set topics: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, struct {
+		News     int `json:"news"`
+		Retagged int `json:"retagged"`
+	}{len(list), len(changed)})
+}
+
+func (n *NewsHandler) loadTopic(w http.ResponseWriter, r *http.Request) (*model.Topic, bool) {
+	topic, err := n.newsStore.GetTopic(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Topic not found")
+		return nil, false
+	}
+	if err != nil {
+		n.logError("topic: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return nil, false
+	}
+
+	return topic, true
+}
+
+// saveTopic saves the topic, then reloads the taxonomy.
+func (n *NewsHandler) saveTopic(w http.ResponseWriter, topic *model.Topic) bool {
+	if err := n.newsStore.SaveTopic(topic); err != nil {
+		n.logError("save topic: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return false
+	}
+
+	return n.reloadTaxonomy(w)
+}
+
+func (n *NewsHandler) reloadTaxonomy(w http.ResponseWriter) bool {
+	topics, err := n.newsStore.GetTopics()
+	if err != nil {
+		n.logError("topics: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return false
+	}
+
+	n.Taxonomy.Set(topics)
+	return true
+}
+
+// decodeTopic decodes and validates the topic of the request body. The empty names and aliases are removed,
+// the aliases are stored separated by commas so they can not contain one.
+func (n *NewsHandler) decodeTopic(w http.ResponseWriter, r *http.Request) (*model.Topic, bool) {
+	var v struct {
+		Id      string            `json:"id"`
+		Names   map[string]string `json:"names"`
+		Aliases []string          `json:"aliases"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "Invalid JSON body")
+		return nil, false
+	}
+
+	topic := &model.Topic{Id: v.Id, Names: make(map[string]string), Aliases: []string{}}
+	for lang, name := range v.Names {
+		if name = strings.TrimSpace(name); name != "" {
+			topic.Names[lang] = name
+		}
+	}
+
+	for _, alias := range v.Aliases {
+		alias = strings.TrimSpace(alias)
+		if strings.Contains(alias, ",") {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "An alias can not contain a comma")
+			return nil, false
+		}
+		if alias != "" {
+			topic.Aliases = append(topic.Aliases, alias)
+		}
+	}
+
+	return topic, true
+}
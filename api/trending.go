@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/trend"
+)
+
+// The window of the trends without the window parameter.
+const defaultTrendWindow = 6 * time.Hour
+
+// The windows are at least an hour, and at most a day so the week of baseline has several windows.
+const (
+	minTrendWindow = time.Hour
+	maxTrendWindow = 24 * time.Hour
+)
+
+// getTrending returns the terms, tags and entities mentioned by more news within the window parameter, e.g 6h, than
+// usual over the week before it. The lang and kind parameters select the trends, the limit parameter defaults to 50.
+func (n *NewsHandler) getTrending(w http.ResponseWriter, r *http.Request) {
+	window := defaultTrendWindow
+	if v := r.FormValue("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < minTrendWindow || d > maxTrendWindow {
+			n.renderError(w, http.StatusBadRequest, "BadRequest", "The window parameter must be a duration between 1h and 24h")
+			return
+		}
+		window = d
+	}
+
+	kind := r.FormValue("kind")
+	if kind != "" && kind != trend.Term && kind != trend.Tag && kind != trend.Entity {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The kind parameter must be term, tag or entity")
+		return
+	}
+
+	report, err := n.Trends.Report(window)
+	if err != nil {
+		n.logError("trending: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	lang := r.FormValue("lang")
+	limit := parseLimit(r)
+
+	trends := make([]*trend.Trend, 0, limit)
+	for _, t := range report.Trends {
+		if len(trends) == limit {
+			break
+		}
+		if (lang == "" || t.Language == lang) && (kind == "" || t.Kind == kind) {
+			trends = append(trends, t)
+		}
+	}
+
+	result := *report
+	result.Trends = trends
+	n.render(w, http.StatusOK, result)
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/ahmadmuzakkir/scrapenews/webhook"
+	"github.com/go-chi/chi"
+)
+
+// webhookRoutes manages the webhooks delivered by webhook.Dispatcher.
+func (n *NewsHandler) webhookRoutes(router chi.Router) {
+	router.Get("/", n.getWebhooks)
+	router.Post("/", n.createWebhook)
+	router.Get("/{id}", n.getWebhook)
+	router.Put("/{id}", n.updateWebhook)
+	router.Delete("/{id}", n.deleteWebhook)
+	router.Get("/{id}/deliveries", n.getDeliveries)
+}
+
+func (n *NewsHandler) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	list, err := n.newsStore.GetWebhooks()
+	if err != nil {
+		n.logError("webhooks: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	for _, v := range list {
+		v.Secret = ""
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+func (n *NewsHandler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	v.Secret = ""
+	n.render(w, http.StatusOK, v)
+}
+
+// createWebhook returns the webhook along with its secret, generated if it is not given.
+func (n *NewsHandler) createWebhook(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	v.Id = webhook.NewId()
+	v.Created = time.Now()
+	if v.Secret == "" {
+		v.Secret = webhook.NewId()
+	}
+
+	if err := n.newsStore.SaveWebhook(v); err != nil {
+		n.logError("save webhook: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusCreated, v)
+}
+
+// updateWebhook replaces the url and the filters of the webhook, the secret is kept if it is not given.
+func (n *NewsHandler) updateWebhook(w http.ResponseWriter, r *http.Request) {
+	existing, ok := n.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	v, ok := n.decodeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	v.Id = existing.Id
+	v.Created = existing.Created
+	if v.Secret == "" {
+		v.Secret = existing.Secret
+	}
+
+	if err := n.newsStore.SaveWebhook(v); err != nil {
+		n.logError("save webhook: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	v.Secret = ""
+	n.render(w, http.StatusOK, v)
+}
+
+func (n *NewsHandler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	err := n.newsStore.DeleteWebhook(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Webhook not found")
+		return
+	}
+	if err != nil {
+		n.logError("delete webhook: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getDeliveries returns the latest delivery attempts of the webhook, the limit parameter defaults to 50.
+func (n *NewsHandler) getDeliveries(w http.ResponseWriter, r *http.Request) {
+	v, ok := n.loadWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	limit := parseLimit(r)
+
+	list, err := n.newsStore.GetDeliveries(v.Id, limit)
+	if err != nil {
+		n.logError("deliveries: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return
+	}
+
+	n.render(w, http.StatusOK, list)
+}
+
+func (n *NewsHandler) loadWebhook(w http.ResponseWriter, r *http.Request) (*model.Webhook, bool) {
+	v, err := n.newsStore.GetWebhook(chi.URLParam(r, "id"))
+	if err == store.ErrNotFound {
+		n.renderError(w, http.StatusNotFound, "NotFound", "Webhook not found")
+		return nil, false
+	}
+	if err != nil {
+		n.logError("webhook: %s", err)
+		n.renderError(w, http.StatusInternalServerError, "ServerError", "Server error")
+		return nil, false
+	}
+
+	return v, true
+}
+
+// decodeWebhook decodes and validates the url and the filters of the request body.
+func (n *NewsHandler) decodeWebhook(w http.ResponseWriter, r *http.Request) (*model.Webhook, bool) {
+	var v struct {
+		Url        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		Newspapers []string `json:"newspapers"`
+		Tags       []string `json:"tags"`
+		Keywords   []string `json:"keywords"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "Invalid JSON body")
+		return nil, false
+	}
+
+	if !isHttpUrl(v.Url) {
+		n.renderError(w, http.StatusBadRequest, "BadRequest", "The url must be an absolute http or https url")
+		return nil, false
+	}
+
+	// The lists are stored comma separated.
+	for _, list := range [][]string{v.Newspapers, v.Tags, v.Keywords} {
+		for _, item := range list {
+			if strings.TrimSpace(item) == "" || strings.Contains(item, ",") {
+				n.renderError(w, http.StatusBadRequest, "BadRequest", "The filters must not be empty nor contain a comma")
+				return nil, false
+			}
+		}
+	}
+
+	return &model.Webhook{
+		Url:        v.Url,
+		Secret:     v.Secret,
+		Newspapers: v.Newspapers,
+		Tags:       v.Tags,
+		Keywords:   v.Keywords,
+	}, true
+}
+
+// isHttpUrl returns true if the url is an absolute http or https url, the targets of the webhooks and of the Slack alerts.
+func isHttpUrl(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Hostname() != ""
+}
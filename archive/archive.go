@@ -0,0 +1,127 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const ext = ".html.gz"
+
+var ErrNotFound = errors.New("The page is not in the archive")
+
+// Archive keeps the gzip compressed raw detail page of every scraped article on the local disk,
+// so the articles can be re-parsed when an extractor is fixed.
+// The pages are stored as <dir>/<id[:2]>/<id>.html.gz
+type Archive struct {
+	dir string
+}
+
+func New(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "[archive] mkdir error")
+	}
+
+	return &Archive{dir: dir}, nil
+}
+
+func (a *Archive) path(id string) string {
+	prefix := id
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+
+	return filepath.Join(a.dir, prefix, id+ext)
+}
+
+// Put stores the page of the article id fetched at the given time, replacing the previous one.
+// The time is kept in the gzip header.
+func (a *Archive) Put(id string, html []byte, fetched time.Time) error {
+	path := a.path(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "[archive] mkdir error")
+	}
+
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	w.ModTime = fetched
+	if _, err := w.Write(html); err != nil {
+		return errors.Wrap(err, "[archive] gzip error")
+	}
+	if err := w.Close(); err != nil {
+		return errors.Wrap(err, "[archive] gzip error")
+	}
+
+	// Write to a temporary file first, so a crash never leaves a truncated page behind.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return errors.Wrap(err, "[archive] write error")
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Get returns the page of the article id and the time it was archived.
+// The pages archived without the time in their header have the modification time of their file.
+func (a *Archive) Get(id string) ([]byte, time.Time, error) {
+	f, err := os.Open(a.path(id))
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "[archive] open error")
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "[archive] gzip error")
+	}
+	defer r.Close()
+
+	html, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "[archive] gzip error")
+	}
+
+	archived := r.ModTime
+	if archived.IsZero() {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, time.Time{}, errors.Wrap(err, "[archive] stat error")
+		}
+		archived = info.ModTime()
+	}
+
+	return html, archived, nil
+}
+
+// Ids returns the id of every archived article.
+func (a *Archive) Ids() ([]string, error) {
+	var ids []string
+
+	err := filepath.Walk(a.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ext) {
+			return nil
+		}
+
+		ids = append(ids, strings.TrimSuffix(info.Name(), ext))
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "[archive] walk error")
+	}
+
+	return ids, nil
+}
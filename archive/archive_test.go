@@ -0,0 +1,47 @@
+package archive
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	a, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetched := time.Date(2018, 6, 11, 8, 0, 0, 0, time.UTC)
+	if err := a.Put("abcdef", []byte("<html></html>"), fetched); err != nil {
+		t.Fatal(err)
+	}
+
+	html, archived, err := a.Get("abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(html) != "<html></html>" || !archived.Equal(fetched) {
+		t.Errorf("got %q, %v, expected %v", html, archived, fetched)
+	}
+
+	// The pages archived without the time have the time of their file.
+	if err := a.Put("abcdef", []byte("<html></html>"), time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2018, 6, 12, 8, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(a.path("abcdef"), modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if _, archived, err := a.Get("abcdef"); err != nil || !archived.Equal(modified) {
+		t.Errorf("got %v, %v, expected %v", archived, err, modified)
+	}
+
+	if _, _, err := a.Get("missing"); err != ErrNotFound {
+		t.Errorf("got %v, expected %v", err, ErrNotFound)
+	}
+
+	if ids, err := a.Ids(); err != nil || len(ids) != 1 || ids[0] != "abcdef" {
+		t.Errorf("got %v, %v", ids, err)
+	}
+}
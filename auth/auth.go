@@ -0,0 +1,81 @@
+// Package auth authenticates the requests to the API, and enforces the scopes and the rate limits of the clients.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// Principal is the authenticated client of a request.
+type Principal struct {
+	// Id identifies the client, e.g the id of its API key.
+	Id     string
+	Scopes []string
+}
+
+// HasScope returns true if the client has the scope, or the admin scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, v := range p.Scopes {
+		if v == scope || v == model.ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the client of the request, nil if the API is open.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
+
+// Token returns the credentials of the Authorization header, with or without the Bearer scheme.
+func Token(r *http.Request) string {
+	return BearerToken(r.Header.Get("Authorization"))
+}
+
+// BearerToken returns the credentials of an authorization value, e.g the authorization metadata of a gRPC call.
+func BearerToken(auth string) string {
+	auth = strings.TrimSpace(auth)
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return auth
+}
+
+// ValidScope returns true if the scope is known.
+func ValidScope(scope string) bool {
+	switch scope {
+	case model.ScopeRead, model.ScopeExport, model.ScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// renderError renders the error the same as the API handlers.
+func renderError(w http.ResponseWriter, status int, code, message string) {
+	response := struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	response.Error.Code = code
+	response.Error.Message = message
+
+	jsonData, _ := json.Marshal(response)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonData)
+}
@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("Invalid token")
+var ErrExpiredToken = errors.New("The token is expired")
+
+// JWT verifies the HS256 and RS256 JSON Web Tokens, e.g issued by a gateway.
+type JWT struct {
+	// The keys by key id, the HMAC secrets for HS256 and the RSA public keys for RS256.
+	// The key of the empty id verifies the tokens without a key id.
+	secrets    map[string][]byte
+	publicKeys map[string]*rsa.PublicKey
+
+	// Audience must be in the aud claim, every token is rejected if it is empty.
+	Audience string
+	// ScopeClaim is the claim of the scopes, a space separated string or a list of strings.
+	ScopeClaim string
+	// Scopes maps the values of the scope claim to the scopes, e.g news.read to read.
+	// The values are the scopes themselves if it is empty.
+	Scopes map[string]string
+	// Leeway is the clock skew allowed for the exp and nbf claims.
+	Leeway time.Duration
+}
+
+// NewJWT reads the keys of the JWKS file, the RSA keys and the symmetric (oct) keys.
+// The secret verifies the HS256 tokens without a key id, it is not used if empty.
+func NewJWT(jwksFile string, secret string) (*JWT, error) {
+	j := &JWT{
+		secrets:    make(map[string][]byte),
+		publicKeys: make(map[string]*rsa.PublicKey),
+		ScopeClaim: "scope",
+		Leeway:     time.Minute,
+	}
+
+	if secret != "" {
+		j.secrets[""] = []byte(secret)
+	}
+
+	if jwksFile != "" {
+		if err := j.readJWKS(jwksFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(j.secrets) == 0 && len(j.publicKeys) == 0 {
+		return nil, errors.New("jwt: no key")
+	}
+
+	return j, nil
+}
+
+// readJWKS reads the JSON Web Key Set, the keys that are not RSA nor oct are skipped.
+func (j *JWT) readJWKS(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return fmt.Errorf("jwks: %s", err)
+	}
+
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		switch k.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return fmt.Errorf("jwks: key %s: %s", k.Kid, err)
+			}
+
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return fmt.Errorf("jwks: key %s: invalid exponent", k.Kid)
+			}
+
+			j.publicKeys[k.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil {
+				return fmt.Errorf("jwks: key %s: %s", k.Kid, err)
+			}
+
+			j.secrets[k.Kid] = secret
+		}
+	}
+
+	return nil
+}
+
+// IsJWT returns true if the token looks like a JWT, rather than an API key.
+func IsJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// Verify returns the client of the token, its id is the sub claim prefixed by jwt:.
+func (j *JWT) Verify(token string, now time.Time) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	switch header.Alg {
+	case "HS256":
+		secret, exist := j.secrets[header.Kid]
+		if !exist {
+			return nil, ErrInvalidToken
+		}
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return nil, ErrInvalidToken
+		}
+
+	case "RS256":
+		key, exist := j.publicKeys[header.Kid]
+		if !exist {
+			return nil, ErrInvalidToken
+		}
+
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, ErrInvalidToken
+		}
+
+	default:
+		return nil, ErrInvalidToken
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// The expiry is required, so a leaked token does not work forever.
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(int64(exp), 0).Add(j.Leeway)) {
+		return nil, ErrExpiredToken
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(j.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, ErrInvalidToken
+	}
+
+	if j.Audience == "" || !contains(stringList(claims["aud"]), j.Audience) {
+		return nil, ErrInvalidToken
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &Principal{Id: "jwt:" + sub, Scopes: j.scopes(claims[j.ScopeClaim])}, nil
+}
+
+// scopes maps the values of the scope claim to the scopes, the unknown values are ignored.
+func (j *JWT) scopes(claim interface{}) []string {
+	var scopes []string
+	for _, v := range stringList(claim) {
+		for _, s := range strings.Fields(v) {
+			if len(j.Scopes) > 0 {
+				s = j.Scopes[s]
+			}
+
+			if ValidScope(s) && !contains(scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	return scopes
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringList returns the claim of a string or a list of strings.
+func stringList(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret   = "secret"
+	testAudience = "scrapenews"
+)
+
+func encodeSegment(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// signHS256 returns the token of the claims signed with the secret.
+func signHS256(header map[string]string, claims map[string]interface{}, secret []byte) string {
+	token := encodeSegment(header) + "." + encodeSegment(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(token))
+	return token + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(header map[string]string, claims map[string]interface{}, key *rsa.PrivateKey) string {
+	token := encodeSegment(header) + "." + encodeSegment(claims)
+	digest := sha256.Sum256([]byte(token))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return token + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// newTestJWT returns the JWT verifying the HS256 tokens of the secret, and the RS256 tokens of the key rsa-1 of the JWKS.
+func newTestJWT(t *testing.T) (*JWT, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "EC", "kid": "ec-1"},
+	}}
+	data, _ := json.Marshal(jwks)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := NewJWT(path, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.Audience = testAudience
+	return j, key
+}
+
+func TestVerify(t *testing.T) {
+	j, key := newTestJWT(t)
+	now := time.Now()
+
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"sub": "client", "aud": testAudience, "scope": "read export", "exp": now.Add(time.Hour).Unix()}
+		for k, v := range changes {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+	hs256 := map[string]string{"alg": "HS256"}
+	rs256 := map[string]string{"alg": "RS256", "kid": "rsa-1"}
+
+	p, err := j.Verify(signHS256(hs256, claims(nil), []byte(testSecret)), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Id != "jwt:client" || !reflect.DeepEqual(p.Scopes, []string{"read", "export"}) {
+		t.Errorf("got %+v", p)
+	}
+
+	if p, err := j.Verify(signRS256(rs256, claims(map[string]interface{}{"aud": []string{"other", testAudience}}), key), now); err != nil || p.Id != "jwt:client" {
+		t.Errorf("got %+v, %v", p, err)
+	}
+
+	// The public key of the RSA key, as an HMAC secret.
+	public, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicPem := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+
+	invalid := map[string]string{
+		"wrong secret":    signHS256(hs256, claims(nil), []byte("other")),
+		"unknown kid":     signRS256(map[string]string{"alg": "RS256", "kid": "rsa-2"}, claims(nil), key),
+		"encryption key":  signRS256(map[string]string{"alg": "RS256", "kid": "enc-1"}, claims(nil), key),
+		"alg confusion":   signHS256(map[string]string{"alg": "HS256", "kid": "rsa-1"}, claims(nil), publicPem),
+		"alg confusion n": signHS256(map[string]string{"alg": "HS256", "kid": "rsa-1"}, claims(nil), key.N.Bytes()),
+		"alg none":        encodeSegment(map[string]string{"alg": "none"}) + "." + encodeSegment(claims(nil)) + ".",
+		"alg HS512":       signHS256(map[string]string{"alg": "HS512"}, claims(nil), []byte(testSecret)),
+		"alg RS384":       signRS256(map[string]string{"alg": "RS384", "kid": "rsa-1"}, claims(nil), key),
+		"not before":      signHS256(hs256, claims(map[string]interface{}{"nbf": now.Add(2 * time.Minute).Unix()}), []byte(testSecret)),
+		"wrong aud":       signHS256(hs256, claims(map[string]interface{}{"aud": "other"}), []byte(testSecret)),
+		"missing aud":     signHS256(hs256, claims(map[string]interface{}{"aud": nil}), []byte(testSecret)),
+		"missing sub":     signHS256(hs256, claims(map[string]interface{}{"sub": nil}), []byte(testSecret)),
+		"missing exp":     signHS256(hs256, claims(map[string]interface{}{"exp": nil}), []byte(testSecret)),
+		"two segments":    encodeSegment(hs256) + "." + encodeSegment(claims(nil)),
+	}
+
+	// The claims of a valid token are replaced.
+	valid := signHS256(hs256, claims(nil), []byte(testSecret))
+	invalid["tampered"] = encodeSegment(hs256) + "." + encodeSegment(claims(map[string]interface{}{"scope": "admin"})) + valid[len(valid)-44:]
+
+	for name, token := range invalid {
+		if p, err := j.Verify(token, now); err != ErrInvalidToken {
+			t.Errorf("%s: got %+v, %v, expected %v", name, p, err, ErrInvalidToken)
+		}
+	}
+
+	// The clock skew is allowed.
+	if _, err := j.Verify(signHS256(hs256, claims(map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()}), []byte(testSecret)), now); err != ErrExpiredToken {
+		t.Errorf("got %v, expected %v", err, ErrExpiredToken)
+	}
+	if _, err := j.Verify(signHS256(hs256, claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()}), []byte(testSecret)), now); err != nil {
+		t.Error(err)
+	}
+	if _, err := j.Verify(signHS256(hs256, claims(map[string]interface{}{"nbf": now.Add(30 * time.Second).Unix()}), []byte(testSecret)), now); err != nil {
+		t.Error(err)
+	}
+
+	// Every token is rejected without an audience.
+	j.Audience = ""
+	if _, err := j.Verify(valid, now); err != ErrInvalidToken {
+		t.Errorf("got %v, expected %v", err, ErrInvalidToken)
+	}
+}
+
+func TestScopes(t *testing.T) {
+	j, _ := newTestJWT(t)
+	j.ScopeClaim = "permissions"
+	j.Scopes = map[string]string{"news.read": "read", "news.admin": "admin", "news.all": "unknown"}
+
+	tests := []struct {
+		claim    interface{}
+		expected []string
+	}{
+		{"news.read news.admin", []string{"read", "admin"}},
+		{[]interface{}{"news.read", "news.read", "other", 1}, []string{"read"}},
+		// The values are mapped, only the known scopes are kept.
+		{"read news.all", nil},
+		{nil, nil},
+	}
+
+	for _, test := range tests {
+		c := map[string]interface{}{"sub": "client", "aud": testAudience, "exp": time.Now().Add(time.Hour).Unix(), "permissions": test.claim}
+		p, err := j.Verify(signHS256(map[string]string{"alg": "HS256"}, c, []byte(testSecret)), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(p.Scopes, test.expected) {
+			t.Errorf("%v: got %q, expected %q", test.claim, p.Scopes, test.expected)
+		}
+	}
+
+	// Without the map, the values are the scopes.
+	j.Scopes = nil
+	if got := j.scopes("read news.read admin"); !reflect.DeepEqual(got, []string{"read", "admin"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNewJWT(t *testing.T) {
+	if _, err := NewJWT("", ""); err == nil {
+		t.Error("got a JWT without a key")
+	}
+	if _, err := NewJWT(filepath.Join(t.TempDir(), "missing.json"), testSecret); err == nil {
+		t.Error("got a JWT without its JWKS file")
+	}
+}
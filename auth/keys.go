@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+const (
+	// The keys start with the prefix, so they are recognised when leaked.
+	keyPrefix = "sn_"
+	// The characters of the key kept to tell the keys apart.
+	keyPrefixLength = 10
+)
+
+var ErrInvalidKey = errors.New("Invalid API key")
+var ErrInactiveKey = errors.New("The API key is revoked or expired")
+
+// Keys manages the API keys, only their hashes are stored.
+// The keys are cached, the changes made by another process are seen after the next Sync.
+type Keys struct {
+	store store.NewsStore
+	// DefaultRateLimit is the number of requests per minute of the keys without their own rate limit.
+	DefaultRateLimit int
+
+	limiter *limiter
+
+	mu       sync.Mutex
+	byHash   map[string]*model.ApiKey
+	usage    map[string]int64
+	lastUsed map[string]time.Time
+}
+
+func NewKeys(store store.NewsStore, defaultRateLimit int) (*Keys, error) {
+	k := &Keys{
+		store:            store,
+		DefaultRateLimit: defaultRateLimit,
+		limiter:          newLimiter(),
+		usage:            make(map[string]int64),
+		lastUsed:         make(map[string]time.Time),
+	}
+
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+func (k *Keys) reload() error {
+	list, err := k.store.GetApiKeys()
+	if err != nil {
+		return err
+	}
+
+	byHash := make(map[string]*model.ApiKey, len(list))
+	for _, v := range list {
+		byHash[v.Hash] = v
+	}
+
+	k.mu.Lock()
+	k.byHash = byHash
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Sync saves the usage counters, then reloads the keys.
+func (k *Keys) Sync() error {
+	k.mu.Lock()
+	usage, lastUsed := k.usage, k.lastUsed
+	k.usage = make(map[string]int64)
+	k.lastUsed = make(map[string]time.Time)
+	k.mu.Unlock()
+
+	for id, requests := range usage {
+		if err := k.store.AddApiKeyUsage(id, requests, lastUsed[id]); err != nil {
+			return err
+		}
+	}
+
+	return k.reload()
+}
+
+// Enabled returns true if there is an API key, the API is open otherwise.
+func (k *Keys) Enabled() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return len(k.byHash) > 0
+}
+
+// Authenticate returns the active API key of the token.
+func (k *Keys) Authenticate(token string) (*model.ApiKey, error) {
+	k.mu.Lock()
+	key := k.byHash[hash(token)]
+	k.mu.Unlock()
+
+	if token == "" || key == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if !key.Active(time.Now()) {
+		return nil, ErrInactiveKey
+	}
+
+	return key, nil
+}
+
+// allow counts the request of the key, and returns its quota within the rate limit of the key.
+func (k *Keys) allow(key *model.ApiKey) quota {
+	limit := key.RateLimit
+	if limit <= 0 {
+		limit = k.DefaultRateLimit
+	}
+
+	now := time.Now()
+
+	k.mu.Lock()
+	k.usage[key.Id]++
+	k.lastUsed[key.Id] = now
+	k.mu.Unlock()
+
+	return k.limiter.allow(key.Id, limit, now)
+}
+
+// List returns the API keys, along with the usage not saved yet.
+func (k *Keys) List() ([]*model.ApiKey, error) {
+	list, err := k.store.GetApiKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range list {
+		k.addUsage(v)
+	}
+
+	return list, nil
+}
+
+func (k *Keys) Get(id string) (*model.ApiKey, error) {
+	key, err := k.store.GetApiKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	k.addUsage(key)
+	return key, nil
+}
+
+func (k *Keys) addUsage(key *model.ApiKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if requests, exist := k.usage[key.Id]; exist {
+		lastUsed := k.lastUsed[key.Id]
+		key.Requests += requests
+		key.LastUsed = &lastUsed
+	}
+}
+
+// Create returns the new API key along with the key itself, which is not stored.
+func (k *Keys) Create(name string, scopes []string, rateLimit int, expires *time.Time) (*model.ApiKey, string, error) {
+	token := keyPrefix + randomHex(20)
+
+	key := &model.ApiKey{
+		Id:        randomHex(8),
+		Name:      name,
+		Hash:      hash(token),
+		Prefix:    token[:keyPrefixLength],
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		Expires:   expires,
+		Created:   time.Now(),
+	}
+
+	if err := k.Save(key); err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+// Rotate replaces the key of the API key, the previous key stops working. It returns the new key.
+func (k *Keys) Rotate(id string) (*model.ApiKey, string, error) {
+	key, err := k.store.GetApiKey(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := keyPrefix + randomHex(20)
+	key.Hash = hash(token)
+	key.Prefix = token[:keyPrefixLength]
+
+	if err := k.Save(key); err != nil {
+		return nil, "", err
+	}
+
+	return key, token, nil
+}
+
+func (k *Keys) Revoke(id string) (*model.ApiKey, error) {
+	key, err := k.store.GetApiKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.Revoked == nil {
+		now := time.Now()
+		key.Revoked = &now
+	}
+
+	if err := k.Save(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// Save saves the changes of the API key, e.g its expiry.
+func (k *Keys) Save(key *model.ApiKey) error {
+	if err := k.store.SaveApiKey(key); err != nil {
+		return err
+	}
+
+	return k.reload()
+}
+
+// Import stores the raw keys that are not stored yet, with every scope, and returns the number of keys imported.
+// The saved searches owned by the hash of a key, as they were before the keys were stored, are given to its API key.
+func (k *Keys) Import(tokens []string) (int, error) {
+	var count int
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+
+		k.mu.Lock()
+		_, exist := k.byHash[hash(token)]
+		k.mu.Unlock()
+		if exist {
+			continue
+		}
+
+		// Less of the key is shown, it may be short.
+		prefix := token
+		if len(prefix) > 4 {
+			prefix = prefix[:4]
+		}
+
+		key := &model.ApiKey{
+			Id:      randomHex(8),
+			Name:    "API_KEYS",
+			Hash:    hash(token),
+			Prefix:  prefix,
+			Scopes:  []string{model.ScopeRead, model.ScopeExport, model.ScopeAdmin},
+			Created: time.Now(),
+		}
+
+		if err := k.Save(key); err != nil {
+			return count, err
+		}
+		count++
+
+		searches, err := k.store.GetSearches(key.Hash)
+		if err != nil {
+			return count, err
+		}
+
+		for _, s := range searches {
+			s.Owner = key.Id
+			if err := k.store.SaveSearch(s); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// hash returns the SHA-256 of the key, as stored.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
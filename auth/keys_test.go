@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+)
+
+func newTestKeys(t *testing.T) (*Keys, *storetest.Memory) {
+	s := &storetest.Memory{}
+	keys, err := NewKeys(s, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys, s
+}
+
+func TestAuthenticate(t *testing.T) {
+	keys, _ := newTestKeys(t)
+	if keys.Enabled() {
+		t.Error("the API is not open without a key")
+	}
+
+	key, token, err := keys.Create("test", []string{model.ScopeRead}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keys.Enabled() || !strings.HasPrefix(token, keyPrefix) || key.Prefix != token[:keyPrefixLength] || key.Hash == token {
+		t.Errorf("got the key %+v and the token %q", key, token)
+	}
+
+	if got, err := keys.Authenticate(token); err != nil || got.Id != key.Id {
+		t.Errorf("got %+v, %v", got, err)
+	}
+
+	for _, token := range []string{"", "sn_unknown", key.Hash} {
+		if _, err := keys.Authenticate(token); err != ErrInvalidKey {
+			t.Errorf("%q: got %v, expected %v", token, err, ErrInvalidKey)
+		}
+	}
+
+	// The rotated key rejects the previous token.
+	_, rotated, err := keys.Rotate(key.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(token); err != ErrInvalidKey {
+		t.Errorf("got %v, expected %v", err, ErrInvalidKey)
+	}
+	if got, err := keys.Authenticate(rotated); err != nil || got.Id != key.Id {
+		t.Errorf("got %+v, %v", got, err)
+	}
+
+	if _, err := keys.Revoke(key.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(rotated); err != ErrInactiveKey {
+		t.Errorf("got %v, expected %v", err, ErrInactiveKey)
+	}
+
+	expired := time.Now().Add(-time.Minute)
+	_, token, err = keys.Create("expired", []string{model.ScopeRead}, 0, &expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Authenticate(token); err != ErrInactiveKey {
+		t.Errorf("got %v, expected %v", err, ErrInactiveKey)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	keys, s := newTestKeys(t)
+
+	key, _, err := keys.Create("test", []string{model.ScopeRead}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys.allow(key)
+	keys.allow(key)
+
+	// The usage not saved yet is listed.
+	if got, err := keys.Get(key.Id); err != nil || got.Requests != 2 || got.LastUsed == nil {
+		t.Errorf("got %+v, %v", got, err)
+	}
+
+	if err := keys.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Keys[0].Requests != 2 {
+		t.Errorf("got %d saved requests, expected 2", s.Keys[0].Requests)
+	}
+	if list, err := keys.List(); err != nil || list[0].Requests != 2 {
+		t.Errorf("got %+v, %v", list, err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	keys, s := newTestKeys(t)
+
+	// The saved searches were owned by the hash of the key before the keys were stored.
+	s.Searches = []*model.SavedSearch{
+		{Id: "s1", Owner: hash("legacy")},
+		{Id: "s2", Owner: hash("other")},
+	}
+
+	count, err := keys.Import([]string{"legacy", "", "legacy"})
+	if err != nil || count != 1 {
+		t.Fatalf("got %d, %v", count, err)
+	}
+
+	key, err := keys.Authenticate("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Prefix != "lega" || !(&Principal{Scopes: key.Scopes}).HasScope(model.ScopeAdmin) {
+		t.Errorf("got %+v", key)
+	}
+	if s.Searches[0].Owner != key.Id || s.Searches[1].Owner != hash("other") {
+		t.Errorf("got the owners %q and %q", s.Searches[0].Owner, s.Searches[1].Owner)
+	}
+
+	// The keys are only imported once.
+	count, err = keys.Import([]string{"legacy"})
+	if err != nil || count != 0 || len(s.Keys) != 1 {
+		t.Errorf("got %d, %v, %d keys", count, err, len(s.Keys))
+	}
+}
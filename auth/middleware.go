@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+// ErrNoKey is the error of the admin requests while the API is open, the first key is created with the keys command.
+var ErrNoKey = errors.New("There is no API key, create the first one with the keys command")
+
+// ScopeError is the error of a client lacking the scope of the request.
+type ScopeError struct {
+	Scope string
+}
+
+func (e *ScopeError) Error() string {
+	return "The client lacks the " + e.Scope + " scope"
+}
+
+// Authenticator authenticates the requests with the API keys, the JWTs, or both.
+type Authenticator struct {
+	// Keys is nil if the API keys are not accepted.
+	Keys *Keys
+	// JWT is nil if the JWTs are not accepted.
+	JWT *JWT
+	// RateLimit is the number of requests per minute of a JWT subject, they are not limited if it is 0.
+	RateLimit int
+
+	limiter *limiter
+}
+
+func NewAuthenticator(keys *Keys, jwt *JWT, rateLimit int) *Authenticator {
+	return &Authenticator{
+		Keys:      keys,
+		JWT:       jwt,
+		RateLimit: rateLimit,
+		limiter:   newLimiter(),
+	}
+}
+
+// open returns true if the requests are not authenticated, while only the API keys are accepted and there is none.
+// The admin scope is never granted while the API is open.
+func (a *Authenticator) open() bool {
+	return a.JWT == nil && (a.Keys == nil || !a.Keys.Enabled())
+}
+
+// authenticate returns the client of the token along with its quota.
+// The tokens that look like a JWT are verified as a JWT if they are accepted.
+func (a *Authenticator) authenticate(token string) (*Principal, quota, error) {
+	if a.JWT != nil && (a.Keys == nil || IsJWT(token)) {
+		p, err := a.JWT.Verify(token, time.Now())
+		if err != nil {
+			return nil, quota{}, err
+		}
+
+		return p, a.limiter.allow(p.Id, a.RateLimit, time.Now()), nil
+	}
+
+	if a.Keys == nil {
+		return nil, quota{}, ErrInvalidKey
+	}
+
+	key, err := a.Keys.Authenticate(token)
+	if err != nil {
+		return nil, quota{}, err
+	}
+
+	return &Principal{Id: key.Id, Scopes: key.Scopes}, a.Keys.allow(key), nil
+}
+
+// authorize authenticates the token, then enforces the rate limit of the client and the scope.
+// The quota is returned along with ErrRateLimited and a *ScopeError, the other errors fail the authentication.
+func (a *Authenticator) authorize(token string, scope string) (*Principal, quota, error) {
+	p, q, err := a.authenticate(token)
+	if err != nil {
+		return nil, q, err
+	}
+
+	if !q.allowed {
+		return nil, q, ErrRateLimited
+	}
+
+	if !p.HasScope(scope) {
+		return nil, q, &ScopeError{Scope: scope}
+	}
+
+	return p, q, nil
+}
+
+// Authorize authorizes the calls of the APIs that are not HTTP, e.g gRPC, as the Middleware does.
+// It returns nil without an error if the API is open, except ErrNoKey for the admin scope.
+// The errors are ErrRateLimited, a *ScopeError, or the error of the authentication.
+func (a *Authenticator) Authorize(token string, scope string) (*Principal, error) {
+	if a.open() {
+		if scope == model.ScopeAdmin {
+			return nil, ErrNoKey
+		}
+		return nil, nil
+	}
+
+	p, _, err := a.authorize(token, scope)
+	return p, err
+}
+
+// Middleware authenticates the requests, then enforces the rate limit of the client and the scope returned by scope.
+func (a *Authenticator) Middleware(scope func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			required := scope(r)
+			if a.open() {
+				if required == model.ScopeAdmin {
+					renderError(w, http.StatusUnauthorized, "Unauthorized", ErrNoKey.Error())
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, q, err := a.authorize(Token(r), required)
+			if q.limit > 0 {
+				w.Header().Set("X-RateLimit-Limit", strconv.Itoa(q.limit))
+				w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(q.remaining))
+			}
+
+			switch err.(type) {
+			case nil:
+				next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+			case *ScopeError:
+				renderError(w, http.StatusForbidden, "Forbidden", err.Error())
+			default:
+				if err == ErrRateLimited {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(q.wait.Seconds()))))
+					renderError(w, http.StatusTooManyRequests, "TooManyRequests", err.Error())
+					return
+				}
+
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				renderError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
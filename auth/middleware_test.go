@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestMiddleware(t *testing.T) {
+	keys, _ := newTestKeys(t)
+	a := NewAuthenticator(keys, nil, 0)
+
+	var principal *Principal
+	handler := a.Middleware(func(r *http.Request) string {
+		if r.URL.Path == "/admin" {
+			return model.ScopeAdmin
+		}
+		return model.ScopeRead
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = FromContext(r.Context())
+	}))
+
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		principal = nil
+		r := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// The API is open without a key.
+	if w := serve("/get", ""); w.Code != http.StatusOK || principal != nil {
+		t.Errorf("got %d, %+v", w.Code, principal)
+	}
+
+	// Except the admin requests, until the first key is created.
+	if w := serve("/admin", ""); w.Code != http.StatusUnauthorized || principal != nil {
+		t.Errorf("got %d, %+v", w.Code, principal)
+	}
+	if _, err := a.Authorize("", model.ScopeAdmin); err != ErrNoKey {
+		t.Errorf("got %v, expected %v", err, ErrNoKey)
+	}
+	if p, err := a.Authorize("", model.ScopeRead); p != nil || err != nil {
+		t.Errorf("got %+v, %v", p, err)
+	}
+
+	key, token, err := keys.Create("test", []string{model.ScopeRead}, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{"", "sn_unknown"} {
+		w := serve("/get", token)
+		if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "Bearer" {
+			t.Errorf("%q: got %d, %v", token, w.Code, w.Header())
+		}
+	}
+
+	w := serve("/get", token)
+	if w.Code != http.StatusOK || principal == nil || principal.Id != key.Id {
+		t.Errorf("got %d, %+v", w.Code, principal)
+	}
+	if w.Header().Get("X-RateLimit-Limit") != "2" || w.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("got %v", w.Header())
+	}
+
+	// The request lacking the scope is forbidden, it is counted.
+	w = serve("/admin", token)
+	if w.Code != http.StatusForbidden || principal != nil || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("got %d, %v", w.Code, w.Header())
+	}
+
+	// 2 requests per minute, a token every 30 seconds.
+	w = serve("/get", token)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "30" {
+		t.Errorf("got %d, %v", w.Code, w.Header())
+	}
+
+	// The gRPC calls are authorized the same.
+	if _, err := a.Authorize(token, model.ScopeRead); err != ErrRateLimited {
+		t.Errorf("got %v, expected %v", err, ErrRateLimited)
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket refilled at the rate limit, a request takes a token.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take returns true if the request is allowed, along with the requests left and the wait until the next token.
+// The limit is the number of requests per minute, the bucket holds at most a minute of requests.
+func (b *bucket) take(limit int, now time.Time) (bool, int, time.Duration) {
+	perSecond := float64(limit) / 60
+
+	if b.last.IsZero() {
+		b.tokens = float64(limit)
+	} else {
+		b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+		return false, 0, wait
+	}
+
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// quota is the rate limit of a request.
+type quota struct {
+	allowed bool
+	// The requests per minute.
+	limit     int
+	remaining int
+	// The wait until the next request is allowed.
+	wait time.Duration
+}
+
+// limiter keeps a bucket per client.
+type limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newLimiter() *limiter {
+	return &limiter{buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token of the bucket of the client, see bucket.take. A limit of 0 or less is unlimited.
+func (l *limiter) allow(id string, limit int, now time.Time) quota {
+	if limit <= 0 {
+		return quota{allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.buckets[id]
+	if b == nil {
+		b = &bucket{}
+		l.buckets[id] = b
+	}
+
+	allowed, remaining, wait := b.take(limit, now)
+	return quota{allowed: allowed, limit: limit, remaining: remaining, wait: wait}
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	b := &bucket{}
+
+	// The bucket starts full, with a minute of requests.
+	for i := 0; i < 60; i++ {
+		allowed, remaining, _ := b.take(60, now)
+		if !allowed || remaining != 59-i {
+			t.Fatalf("request %d: got %v, %d remaining", i, allowed, remaining)
+		}
+	}
+
+	allowed, remaining, wait := b.take(60, now)
+	if allowed || remaining != 0 || wait != time.Second {
+		t.Errorf("got %v, %d remaining, wait %v", allowed, remaining, wait)
+	}
+
+	// A token is added every second at 60 requests per minute.
+	if allowed, _, _ := b.take(60, now.Add(500*time.Millisecond)); allowed {
+		t.Error("got a request allowed before the refill")
+	}
+	if allowed, remaining, _ := b.take(60, now.Add(time.Second)); !allowed || remaining != 0 {
+		t.Errorf("got %v, %d remaining", allowed, remaining)
+	}
+
+	// The bucket holds at most the limit.
+	if _, remaining, _ := b.take(60, now.Add(time.Hour)); remaining != 59 {
+		t.Errorf("got %d remaining, expected 59", remaining)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter()
+
+	if q := l.allow("a", 1, now); !q.allowed || q.limit != 1 || q.remaining != 0 {
+		t.Errorf("got %+v", q)
+	}
+
+	q := l.allow("a", 1, now)
+	if q.allowed || q.wait != time.Minute {
+		t.Errorf("got %+v", q)
+	}
+
+	// The clients have their own bucket.
+	if q := l.allow("b", 1, now); !q.allowed {
+		t.Errorf("got %+v", q)
+	}
+
+	// A limit of 0 or less is unlimited.
+	for _, limit := range []int{0, -1} {
+		for i := 0; i < 100; i++ {
+			if q := l.allow("c", limit, now); !q.allowed || q.limit != 0 {
+				t.Fatalf("%d: got %+v", limit, q)
+			}
+		}
+	}
+}
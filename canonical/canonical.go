@@ -0,0 +1,115 @@
+// Package canonical normalises the urls of the news, so the variants of the url of an article share its id,
+// e.g http://www.utusan.com.my//berita/a/?utm_source=facebook and https://www.utusan.com.my/berita/a.
+package canonical
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams are the query parameters added by the social networks, the newsletters and the ad campaigns.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "igshid": true, "mc_cid": true, "mc_eid": true,
+	"_ga": true, "ref": true, "ref_src": true, "cmpid": true, "share": true, "amp": true, "outputtype": true,
+}
+
+// Url returns the canonical form of the absolute url: https, the host in lower case without the default port and the amp
+// subdomain, the path without the repeated slashes, the trailing slash and the /amp suffix, the query without the tracking
+// parameters and sorted, and no fragment. An invalid or relative url is returned trimmed.
+func Url(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = "https"
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	host = strings.TrimPrefix(host, "amp.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	p := u.EscapedPath()
+	for strings.Contains(p, "//") {
+		p = strings.Replace(p, "//", "/", -1)
+	}
+	p = strings.TrimSuffix(p, "/")
+	if path.Base(p) == "amp" {
+		p = path.Dir(p)
+	}
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		p = "/"
+	}
+	if decoded, err := url.PathUnescape(p); err == nil {
+		u.Path = decoded
+		u.RawPath = p
+		if u.EscapedPath() == decoded {
+			u.RawPath = ""
+		}
+	}
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if trackingParams[lower] || strings.HasPrefix(lower, "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String()
+}
+
+// SameSite returns true if the urls have the same host, ignoring the case, the www and the amp subdomains.
+func SameSite(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	site := func(u *url.URL) string {
+		host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+		host = strings.TrimPrefix(host, "amp.")
+		return strings.TrimPrefix(host, "www.")
+	}
+	return site(ua) != "" && site(ua) == site(ub)
+}
+
+// Declared returns the canonical url of the page: the one it declares, e.g by its link rel="canonical", if it is
+// an article of the same site, else the url of the page. A declared url ending with a slash, or with a path shallower
+// than the one of the page, is the home or a section of the site, not the article.
+func Declared(page, declared string) string {
+	if raw, err := url.Parse(strings.TrimSpace(declared)); err != nil || raw.Path == "" || strings.HasSuffix(raw.Path, "/") {
+		return Url(page)
+	}
+	if declared = Url(declared); SameSite(declared, page) && depth(declared) >= depth(Url(page)) {
+		return declared
+	}
+	return Url(page)
+}
+
+// depth returns the number of segments of the path of the url.
+func depth(raw string) int {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return 0
+	}
+	p := strings.Trim(u.Path, "/")
+	if p == "" {
+		return 0
+	}
+	return strings.Count(p, "/") + 1
+}
@@ -0,0 +1,61 @@
+package canonical
+
+import "testing"
+
+func TestUrl(t *testing.T) {
+	tests := map[string]string{
+		"http://www.utusan.com.my//berita/nasional/a-1.123/":                         "https://www.utusan.com.my/berita/nasional/a-1.123",
+		" https://WWW.NST.com.my:443/news/2018/06/1?utm_source=fb&utm_medium=x#top ": "https://www.nst.com.my/news/2018/06/1",
+		"https://www.bharian.com.my/berita/kes/2018/06/1/amp":                        "https://www.bharian.com.my/berita/kes/2018/06/1",
+		"https://amp.example.com/a?amp=1&page=2&fbclid=abc&id=1":                     "https://example.com/a?id=1&page=2",
+		"https://www.nst.com.my":                                                     "https://www.nst.com.my/",
+		"https://example.com:8080/a%20b/":                                            "https://example.com:8080/a%20b",
+		"/relative/path":                                                             "/relative/path",
+	}
+
+	for raw, expected := range tests {
+		if got := Url(raw); got != expected {
+			t.Errorf("%q: got %q, expected %q", raw, got, expected)
+		}
+		// The canonical url is its own canonical url.
+		if got := Url(expected); got != expected {
+			t.Errorf("%q: got %q", expected, got)
+		}
+	}
+}
+
+func TestSameSite(t *testing.T) {
+	if !SameSite("http://www.utusan.com.my/a", "https://utusan.com.my/b") || !SameSite("https://amp.nst.com.my/a", "https://www.nst.com.my") {
+		t.Error("got different sites")
+	}
+	if SameSite("https://www.nst.com.my/a", "https://www.bharian.com.my/a") || SameSite("/a", "/b") {
+		t.Error("got the same site")
+	}
+}
+
+func TestDeclared(t *testing.T) {
+	const page = "http://www.nst.com.my/news/crime-courts/2018/06/1/?utm_source=facebook"
+
+	tests := []struct {
+		declared string
+		expected string
+	}{
+		{"https://www.nst.com.my/news/crime-courts/2018/06/man-arrested-1", "https://www.nst.com.my/news/crime-courts/2018/06/man-arrested-1"},
+		{"https://www.nst.com.my/news/crime-courts/2018/06/1/amp", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		{"", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		// A section of the site.
+		{"https://www.nst.com.my/news/", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		{"https://www.nst.com.my/news/crime-courts", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		// The root of the site.
+		{"https://www.nst.com.my/", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		{"https://www.nst.com.my", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+		// Another site.
+		{"https://www.facebook.com/news/crime-courts/2018/06/man-arrested-1", "https://www.nst.com.my/news/crime-courts/2018/06/1"},
+	}
+
+	for _, test := range tests {
+		if got := Declared(page, test.declared); got != test.expected {
+			t.Errorf("%q: got %q, expected %q", test.declared, got, test.expected)
+		}
+	}
+}
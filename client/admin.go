@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// WebhookInput is the url and the filters of a webhook.
+type WebhookInput struct {
+	Url string `json:"url"`
+	// Secret is generated if empty on create, and kept if empty on update.
+	Secret     string   `json:"secret,omitempty"`
+	Newspapers []string `json:"newspapers,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+}
+
+func (c *NewsClient) GetWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	var list []*model.Webhook
+	err := c.do(ctx, "GET", "/webhooks", nil, nil, &list)
+	return list, err
+}
+
+func (c *NewsClient) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	v := &model.Webhook{}
+	if err := c.do(ctx, "GET", "/webhooks/"+url.PathEscape(id), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// CreateWebhook returns the webhook along with its secret.
+func (c *NewsClient) CreateWebhook(ctx context.Context, in WebhookInput) (*model.Webhook, error) {
+	v := &model.Webhook{}
+	if err := c.do(ctx, "POST", "/webhooks", nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) UpdateWebhook(ctx context.Context, id string, in WebhookInput) (*model.Webhook, error) {
+	v := &model.Webhook{}
+	if err := c.do(ctx, "PUT", "/webhooks/"+url.PathEscape(id), nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) DeleteWebhook(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/webhooks/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetDeliveries returns the latest deliveries of the webhook, a limit of 0 uses the default of the API.
+func (c *NewsClient) GetDeliveries(ctx context.Context, id string, limit int) ([]*model.Delivery, error) {
+	var list []*model.Delivery
+	err := c.do(ctx, "GET", "/webhooks/"+url.PathEscape(id)+"/deliveries", limitValues(limit), nil, &list)
+	return list, err
+}
+
+// SearchInput is a saved search, see model.SavedSearch.
+type SearchInput struct {
+	Name       string   `json:"name,omitempty"`
+	Query      string   `json:"query"`
+	Newspapers []string `json:"newspapers,omitempty"`
+	Language   string   `json:"language,omitempty"`
+	// Frequency defaults to immediate.
+	Frequency string `json:"frequency,omitempty"`
+	Notifier  string `json:"notifier"`
+	Target    string `json:"target"`
+}
+
+// GetSearches returns the saved searches of the client.
+func (c *NewsClient) GetSearches(ctx context.Context) ([]*model.SavedSearch, error) {
+	var list []*model.SavedSearch
+	err := c.do(ctx, "GET", "/searches", nil, nil, &list)
+	return list, err
+}
+
+func (c *NewsClient) GetSearch(ctx context.Context, id string) (*model.SavedSearch, error) {
+	v := &model.SavedSearch{}
+	if err := c.do(ctx, "GET", "/searches/"+url.PathEscape(id), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) CreateSearch(ctx context.Context, in SearchInput) (*model.SavedSearch, error) {
+	v := &model.SavedSearch{}
+	if err := c.do(ctx, "POST", "/searches", nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) UpdateSearch(ctx context.Context, id string, in SearchInput) (*model.SavedSearch, error) {
+	v := &model.SavedSearch{}
+	if err := c.do(ctx, "PUT", "/searches/"+url.PathEscape(id), nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) DeleteSearch(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/searches/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// GetAlerts returns the latest alerts of the saved search, a limit of 0 uses the default of the API.
+func (c *NewsClient) GetAlerts(ctx context.Context, id string, limit int) ([]*model.Alert, error) {
+	var list []*model.Alert
+	err := c.do(ctx, "GET", "/searches/"+url.PathEscape(id)+"/alerts", limitValues(limit), nil, &list)
+	return list, err
+}
+
+// KeyInput is the name, the scopes, the rate limit and the expiry of an API key.
+type KeyInput struct {
+	Name string `json:"name,omitempty"`
+	// Scopes default to read.
+	Scopes []string `json:"scopes,omitempty"`
+	// RateLimit is the number of requests per minute, 0 uses the default.
+	RateLimit int `json:"rate_limit,omitempty"`
+	// Expires is nil if the key does not expire.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// CreatedKey is an API key along with the key itself.
+type CreatedKey struct {
+	model.ApiKey
+	Key string `json:"key"`
+}
+
+func (c *NewsClient) GetKeys(ctx context.Context) ([]*model.ApiKey, error) {
+	var list []*model.ApiKey
+	err := c.do(ctx, "GET", "/admin/keys", nil, nil, &list)
+	return list, err
+}
+
+func (c *NewsClient) GetKey(ctx context.Context, id string) (*model.ApiKey, error) {
+	v := &model.ApiKey{}
+	if err := c.do(ctx, "GET", "/admin/keys/"+url.PathEscape(id), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) CreateKey(ctx context.Context, in KeyInput) (*CreatedKey, error) {
+	v := &CreatedKey{}
+	if err := c.do(ctx, "POST", "/admin/keys", nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) UpdateKey(ctx context.Context, id string, in KeyInput) (*model.ApiKey, error) {
+	v := &model.ApiKey{}
+	if err := c.do(ctx, "PUT", "/admin/keys/"+url.PathEscape(id), nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RotateKey replaces the key of the API key, the previous key stops working.
+func (c *NewsClient) RotateKey(ctx context.Context, id string) (*CreatedKey, error) {
+	v := &CreatedKey{}
+	if err := c.do(ctx, "POST", "/admin/keys/"+url.PathEscape(id)+"/rotate", nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) RevokeKey(ctx context.Context, id string) (*model.ApiKey, error) {
+	v := &model.ApiKey{}
+	if err := c.do(ctx, "POST", "/admin/keys/"+url.PathEscape(id)+"/revoke", nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// TopicInput is the names and the aliases of a topic, the id is only read on create.
+type TopicInput struct {
+	Id      string            `json:"id,omitempty"`
+	Names   map[string]string `json:"names"`
+	Aliases []string          `json:"aliases"`
+}
+
+func (c *NewsClient) GetTopics(ctx context.Context) ([]*model.Topic, error) {
+	var list []*model.Topic
+	err := c.do(ctx, "GET", "/admin/topics", nil, nil, &list)
+	return list, err
+}
+
+func (c *NewsClient) GetTopic(ctx context.Context, id string) (*model.Topic, error) {
+	v := &model.Topic{}
+	if err := c.do(ctx, "GET", "/admin/topics/"+url.PathEscape(id), nil, nil, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) CreateTopic(ctx context.Context, in TopicInput) (*model.Topic, error) {
+	v := &model.Topic{}
+	if err := c.do(ctx, "POST", "/admin/topics", nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) UpdateTopic(ctx context.Context, id string, in TopicInput) (*model.Topic, error) {
+	v := &model.Topic{}
+	if err := c.do(ctx, "PUT", "/admin/topics/"+url.PathEscape(id), nil, in, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (c *NewsClient) DeleteTopic(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/admin/topics/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// Retag maps every news stored to the topics, it returns the number of news and of those whose topics changed.
+func (c *NewsClient) Retag(ctx context.Context) (int, int, error) {
+	var v struct {
+		News     int `json:"news"`
+		Retagged int `json:"retagged"`
+	}
+	err := c.do(ctx, "POST", "/admin/topics/retag", nil, nil, &v)
+	return v.News, v.Retagged, err
+}
+
+func limitValues(limit int) url.Values {
+	v := url.Values{}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	return v
+}
@@ -0,0 +1,113 @@
+// Package client is the Go client of the API, see api/openapi.json for the contract.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Error is the error returned by the API.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("scrapenews: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// NewsClient calls the API, it is safe for concurrent use.
+type NewsClient struct {
+	// BaseURL is the url of the API, e.g https://news.example.com
+	BaseURL string
+	// Token is the API key or the JWT, sent as the bearer credentials. It is not sent if empty.
+	Token      string
+	HttpClient *http.Client
+}
+
+func NewNewsClient(baseURL string, token string) *NewsClient {
+	return &NewsClient{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Token:      token,
+		HttpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends the request, with the JSON of in as the body if it is not nil, and decodes the response into out if it is not nil.
+func (c *NewsClient) do(ctx context.Context, method string, path string, query url.Values, in interface{}, out interface{}) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+
+	if out == nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// decodeError returns the error of the response, the responses without an error body keep their status text.
+func decodeError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode, Code: http.StatusText(resp.StatusCode)}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	data, _ := ioutil.ReadAll(resp.Body)
+	if err := json.Unmarshal(data, &body); err == nil && body.Error.Code != "" {
+		e.Code = body.Error.Code
+		e.Message = body.Error.Message
+	} else {
+		e.Message = strings.TrimSpace(string(data))
+	}
+
+	return e
+}
@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// ListOptions are the parameters of the listings. The zero values are not sent, the API defaults to the last day.
+type ListOptions struct {
+	// From is the latest datetime, Until the earliest.
+	From       time.Time
+	Until      time.Time
+	Language   string
+	Entity     string
+	EntityType string
+	// Newspaper is the id of a newspaper, Tag a tag of the news or of its source.
+	Newspaper string
+	Tag       string
+	// Topic is the id of a topic of the taxonomy, e.g crime.
+	Topic string
+	// Fields are the fields of the news returned, e.g id and title. The API defaults to every field but the bodies.
+	Fields []string
+	// Dedupe keeps the first news of every cluster.
+	Dedupe bool
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if !o.From.IsZero() {
+		v.Set("from", o.From.Format(time.RFC3339Nano))
+	}
+	if !o.Until.IsZero() {
+		v.Set("until", o.Until.Format(time.RFC3339Nano))
+	}
+	if o.Language != "" {
+		v.Set("lang", o.Language)
+	}
+	if o.Entity != "" {
+		v.Set("entity", o.Entity)
+	}
+	if o.EntityType != "" {
+		v.Set("entity_type", o.EntityType)
+	}
+	if o.Newspaper != "" {
+		v.Set("newspaper", o.Newspaper)
+	}
+	if o.Tag != "" {
+		v.Set("tag", o.Tag)
+	}
+	if o.Topic != "" {
+		v.Set("topic", o.Topic)
+	}
+	if len(o.Fields) > 0 {
+		v.Set("fields", strings.Join(o.Fields, ","))
+	}
+	if o.Dedupe {
+		v.Set("dedupe", "true")
+	}
+	return v
+}
+
+// GetNews returns the news between From and Until, latest first.
+func (c *NewsClient) GetNews(ctx context.Context, opts ListOptions) ([]*model.News, error) {
+	var list []*model.News
+	err := c.do(ctx, "GET", "/get", opts.values(), nil, &list)
+	return list, err
+}
+
+// Search returns the news between From and Until containing every word of the query, latest first.
+func (c *NewsClient) Search(ctx context.Context, query string, opts ListOptions) ([]*model.News, error) {
+	v := opts.values()
+	v.Set("q", query)
+
+	var list []*model.News
+	err := c.do(ctx, "GET", "/search", v, nil, &list)
+	return list, err
+}
+
+// GetRelated returns the near-duplicates of the news, the fields are optional.
+func (c *NewsClient) GetRelated(ctx context.Context, id string, fields ...string) ([]*model.News, error) {
+	v := url.Values{}
+	if len(fields) > 0 {
+		v.Set("fields", strings.Join(fields, ","))
+	}
+
+	var list []*model.News
+	err := c.do(ctx, "GET", "/news/"+url.PathEscape(id)+"/related", v, nil, &list)
+	return list, err
+}
+
+// GetStories returns the stories running between until and from, latest first. The news are not filled.
+func (c *NewsClient) GetStories(ctx context.Context, from time.Time, until time.Time) ([]*model.Story, error) {
+	v := url.Values{}
+	if !from.IsZero() {
+		v.Set("from", from.Format(time.RFC3339Nano))
+	}
+	if !until.IsZero() {
+		v.Set("until", until.Format(time.RFC3339Nano))
+	}
+
+	var list []*model.Story
+	err := c.do(ctx, "GET", "/stories", v, nil, &list)
+	return list, err
+}
+
+// GetStory returns the story along with its news, the fields are optional.
+func (c *NewsClient) GetStory(ctx context.Context, id string, fields ...string) (*model.Story, error) {
+	v := url.Values{}
+	if len(fields) > 0 {
+		v.Set("fields", strings.Join(fields, ","))
+	}
+
+	story := &model.Story{}
+	if err := c.do(ctx, "GET", "/stories/"+url.PathEscape(id), v, nil, story); err != nil {
+		return nil, err
+	}
+	return story, nil
+}
+
+// Pager pages the news of a listing by windows of time, from the latest to the earliest.
+// A news at the boundary of two windows is only returned once.
+type Pager struct {
+	client *NewsClient
+	query  string
+	opts   ListOptions
+	window time.Duration
+
+	from     time.Time
+	previous map[string]struct{}
+}
+
+// PageNews pages GetNews between opts.From and opts.Until a window at a time, e.g a day.
+// From defaults to now and Until to a day before From, a window of 0 reads them at once.
+func (c *NewsClient) PageNews(opts ListOptions, window time.Duration) *Pager {
+	return c.newPager("", opts, window)
+}
+
+// PageSearch pages Search between opts.From and opts.Until a window at a time, see PageNews.
+func (c *NewsClient) PageSearch(query string, opts ListOptions, window time.Duration) *Pager {
+	return c.newPager(query, opts, window)
+}
+
+func (c *NewsClient) newPager(query string, opts ListOptions, window time.Duration) *Pager {
+	if opts.From.IsZero() {
+		opts.From = time.Now()
+	}
+	if opts.Until.IsZero() {
+		opts.Until = opts.From.AddDate(0, 0, -1)
+	}
+	if window <= 0 {
+		window = opts.From.Sub(opts.Until)
+	}
+
+	return &Pager{
+		client: c,
+		query:  query,
+		opts:   opts,
+		window: window,
+		from:   opts.From,
+	}
+}
+
+// More returns true until every window is read.
+func (p *Pager) More() bool {
+	return !p.from.Before(p.opts.Until)
+}
+
+// Next returns the news of the next window, latest first. It may be empty.
+func (p *Pager) Next(ctx context.Context) ([]*model.News, error) {
+	opts := p.opts
+	opts.From = p.from
+	opts.Until = p.from.Add(-p.window)
+	if opts.Until.Before(p.opts.Until) {
+		opts.Until = p.opts.Until
+	}
+
+	var list []*model.News
+	var err error
+	if p.query == "" {
+		list, err = p.client.GetNews(ctx, opts)
+	} else {
+		list, err = p.client.Search(ctx, p.query, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The windows include both their ends.
+	var result = make([]*model.News, 0, len(list))
+	var ids = make(map[string]struct{}, len(list))
+	for _, n := range list {
+		ids[n.Id] = struct{}{}
+		if _, exist := p.previous[n.Id]; !exist {
+			result = append(result, n)
+		}
+	}
+
+	p.previous = ids
+	if opts.Until.Equal(p.opts.Until) {
+		p.from = p.opts.Until.Add(-time.Nanosecond)
+	} else {
+		p.from = opts.Until
+	}
+
+	return result, nil
+}
+
+// Each calls fn with every news of the pager, latest first, until fn returns an error.
+func (p *Pager) Each(ctx context.Context, fn func(n *model.News) error) error {
+	for p.More() {
+		list, err := p.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, n := range list {
+			if err := fn(n); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// newsServer serves /get of the news, between from and until both included like the API.
+func newsServer(t *testing.T, news []*model.News) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"Unauthorized","message":"Invalid API key"}}`))
+			return
+		}
+
+		from, err := time.Parse(time.RFC3339, r.FormValue("from"))
+		if err != nil {
+			t.Errorf("from: %s", err)
+		}
+		until, err := time.Parse(time.RFC3339, r.FormValue("until"))
+		if err != nil {
+			t.Errorf("until: %s", err)
+		}
+
+		var list = make([]*model.News, 0)
+		for _, n := range news {
+			if !n.Datetime.After(from) && !n.Datetime.Before(until) {
+				list = append(list, n)
+			}
+		}
+
+		json.NewEncoder(w).Encode(list)
+	}))
+}
+
+func TestPager(t *testing.T) {
+	until := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	from := until.AddDate(0, 0, 1)
+
+	// A news every hour, so the windows share their boundary news.
+	var news []*model.News
+	for d := from; !d.Before(until); d = d.Add(-time.Hour) {
+		news = append(news, &model.News{Id: d.Format("2006-01-02T15"), Datetime: d})
+	}
+
+	server := newsServer(t, news)
+	defer server.Close()
+
+	for _, window := range []time.Duration{0, time.Hour, 6 * time.Hour, 7 * time.Hour, 48 * time.Hour} {
+		c := NewNewsClient(server.URL, "token")
+		pager := c.PageNews(ListOptions{From: from, Until: until}, window)
+
+		var got []string
+		err := pager.Each(context.Background(), func(n *model.News) error {
+			got = append(got, n.Id)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != len(news) {
+			t.Fatalf("window %s: got %d news, expected %d: %v", window, len(got), len(news), got)
+		}
+		for i, n := range news {
+			if got[i] != n.Id {
+				t.Fatalf("window %s: got %s at %d, expected %s", window, got[i], i, n.Id)
+			}
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	server := newsServer(t, nil)
+	defer server.Close()
+
+	c := NewNewsClient(server.URL+"/", "wrong")
+	_, err := c.GetNews(context.Background(), ListOptions{})
+
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("got %v, expected an *Error", err)
+	}
+	if e.StatusCode != http.StatusUnauthorized || e.Code != "Unauthorized" || e.Message != "Invalid API key" {
+		t.Errorf("got %+v", e)
+	}
+}
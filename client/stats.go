@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/ahmadmuzakkir/scrapenews/trend"
+)
+
+// CountNews returns the number of news by the values of the group, e.g store.GroupNewspaper, most first.
+// Fields and Dedupe of the options are ignored, the limit defaults to 50.
+func (c *NewsClient) CountNews(ctx context.Context, group string, limit int, opts ListOptions) ([]*store.Count, error) {
+	v := opts.values()
+	v.Del("fields")
+	v.Del("dedupe")
+	v.Set("by", group)
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	var list []*store.Count
+	err := c.do(ctx, "GET", "/stats/counts", v, nil, &list)
+	return list, err
+}
+
+// Histogram returns the number of news per interval, e.g store.Day, oldest first, by the values of the group if it is not empty.
+// The time zone is an IANA name, the API defaults to Asia/Kuala_Lumpur if it is empty.
+func (c *NewsClient) Histogram(ctx context.Context, interval string, group string, tz string, opts ListOptions) ([]*store.Bucket, error) {
+	v := opts.values()
+	v.Del("fields")
+	v.Del("dedupe")
+	v.Set("interval", interval)
+	if group != "" {
+		v.Set("by", group)
+	}
+	if tz != "" {
+		v.Set("tz", tz)
+	}
+
+	var list []*store.Bucket
+	err := c.do(ctx, "GET", "/stats/histogram", v, nil, &list)
+	return list, err
+}
+
+// TopAuthors returns the authors with the most news, most first, the limit defaults to 50.
+func (c *NewsClient) TopAuthors(ctx context.Context, limit int, opts ListOptions) ([]*store.AuthorCount, error) {
+	v := opts.values()
+	v.Del("fields")
+	v.Del("dedupe")
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	var list []*store.AuthorCount
+	err := c.do(ctx, "GET", "/stats/top-authors", v, nil, &list)
+	return list, err
+}
+
+// SourceHealth returns how the fields of the news of every newspaper were extracted, sorted by newspaper.
+// Fields and Dedupe of the options are ignored.
+func (c *NewsClient) SourceHealth(ctx context.Context, opts ListOptions) ([]*store.SourceHealth, error) {
+	v := opts.values()
+	v.Del("fields")
+	v.Del("dedupe")
+
+	var list []*store.SourceHealth
+	err := c.do(ctx, "GET", "/stats/sources", v, nil, &list)
+	return list, err
+}
+
+// Trending returns the trends of the window ending now, e.g 6 hours, in the language and of the kind, e.g trend.Tag,
+// if they are not empty. The API defaults the window to 6 hours if it is zero, and the limit to 50.
+func (c *NewsClient) Trending(ctx context.Context, window time.Duration, lang string, kind string, limit int) (*trend.Report, error) {
+	v := url.Values{}
+	if window > 0 {
+		v.Set("window", window.String())
+	}
+	if lang != "" {
+		v.Set("lang", lang)
+	}
+	if kind != "" {
+		v.Set("kind", kind)
+	}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+
+	report := &trend.Report{}
+	err := c.do(ctx, "GET", "/trending", v, nil, report)
+	return report, err
+}
@@ -0,0 +1,203 @@
+package content
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// The boilerplate paragraphs are short, a longer paragraph matching a pattern is kept.
+const maxBoilerplateLength = 300
+
+// Rules decide which parts of the article body are boilerplate.
+type Rules struct {
+	// The elements removed before the paragraphs are collected, e.g the social embeds.
+	Selectors []string
+
+	// The paragraphs dropped, e.g the BACA: ... cross-links.
+	Patterns []*regexp.Regexp
+}
+
+// Shared are the rules applied to every newspaper.
+var Shared = Rules{
+	Selectors: []string{
+		"script", "style", "iframe", "noscript", "form",
+		"blockquote.twitter-tweet", "blockquote.instagram-media", "div.fb-post", "div.fb-video",
+	},
+	Patterns: []*regexp.Regexp{
+		// Read also
+		regexp.MustCompile(`(?i)^(baca|baca juga|artikel berkaitan|berita berkaitan|read|read more|read also|also read|related)\s*[:\-–]`),
+		// Click here
+		regexp.MustCompile(`(?i)\b(klik di ?sini|click here)\b`),
+		// Photo credits
+		regexp.MustCompile(`(?i)^[\-–(]*\s*(foto|gambar|photo|pic|pix|video)\s*(:|/|by|oleh|fail|hiasan|file)`),
+		// Embedded posts
+		regexp.MustCompile(`(?i)^(https?://\S+|pic\.twitter\.com/\S+)$`),
+		regexp.MustCompile(`— .*\(@\w+\) \w+ \d+, \d{4}$`),
+	},
+}
+
+// Body is the clean article body.
+type Body struct {
+	Paragraphs []string
+
+	// The sanitised HTML of the paragraphs, only the text formatting and the links are kept.
+	Html string
+}
+
+// Extract returns the paragraphs under the selector without the boilerplate matched by the shared and the given rules.
+// If the selector finds no paragraph, e.g after a redesign, the body is found by scoring the blocks of the document.
+// The document is not modified.
+func Extract(doc *goquery.Document, selector string, rules ...Rules) *Body {
+	rules = append([]Rules{Shared}, rules...)
+	root := doc.Selection.Clone()
+
+	for _, r := range rules {
+		for _, s := range r.Selectors {
+			root.Find(s).Remove()
+		}
+	}
+
+	body := collect(root.Find(selector).Find("p"), rules)
+	if len(body.Paragraphs) > 0 {
+		return body
+	}
+
+	if candidate := readability(root); candidate != nil {
+		return collect(candidate.ChildrenFiltered("p"), rules)
+	}
+
+	return body
+}
+
+func collect(paragraphs *goquery.Selection, rules []Rules) *Body {
+	var body = &Body{}
+	var buf bytes.Buffer
+
+	paragraphs.Each(func(i int, s *goquery.Selection) {
+		text := Normalise(s.Text())
+		if text == "" || isBoilerplate(text, rules) {
+			return
+		}
+
+		body.Paragraphs = append(body.Paragraphs, text)
+
+		buf.WriteString("<p>")
+		for _, node := range s.Nodes {
+			for c := node.FirstChild; c != nil; c = c.NextSibling {
+				sanitise(&buf, c)
+			}
+		}
+		buf.WriteString("</p>\n")
+	})
+
+	body.Html = buf.String()
+	return body
+}
+
+// Html returns the HTML of the paragraphs, e.g after a stage removed some of them or cut the dateline of the first one.
+// The paragraphs found in the source HTML keep their formatting, the others are escaped.
+func Html(source string, paragraphs []string) string {
+	formatted := make(map[string]string)
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(source)); err == nil {
+		doc.Find("p").Each(func(i int, s *goquery.Selection) {
+			if inner, err := s.Html(); err == nil {
+				formatted[Normalise(s.Text())] = inner
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	for _, p := range paragraphs {
+		inner, exist := formatted[Normalise(p)]
+		if !exist {
+			inner = html.EscapeString(p)
+		}
+		buf.WriteString("<p>" + inner + "</p>\n")
+	}
+	return buf.String()
+}
+
+func isBoilerplate(text string, rules []Rules) bool {
+	if len(text) > maxBoilerplateLength {
+		return false
+	}
+
+	for _, r := range rules {
+		for _, p := range r.Patterns {
+			if p.MatchString(text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var spaces = regexp.MustCompile(`[\s\x{00a0}]+`)
+
+// Normalise collapses the whitespaces of the text, the new lines included.
+func Normalise(text string) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(text, " "))
+}
+
+// The elements kept by sanitise, the others are replaced by their content.
+var allowed = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "br": true, "a": true,
+}
+
+func sanitise(buf *bytes.Buffer, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	tag := n.Data
+	if tag == "a" && !hasHttpHref(n) {
+		tag = ""
+	}
+
+	if !allowed[tag] {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			sanitise(buf, c)
+		}
+		return
+	}
+
+	if tag == "br" {
+		buf.WriteString("<br>")
+		return
+	}
+
+	buf.WriteString("<" + tag)
+	if tag == "a" {
+		buf.WriteString(` href="` + html.EscapeString(attr(n, "href")) + `"`)
+	}
+	buf.WriteString(">")
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sanitise(buf, c)
+	}
+
+	buf.WriteString("</" + tag + ">")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasHttpHref(n *html.Node) bool {
+	href := strings.ToLower(attr(n, "href"))
+	return strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://")
+}
@@ -0,0 +1,111 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func readFixture(t *testing.T, name string) *goquery.Document {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestExtract(t *testing.T) {
+	doc := readFixture(t, "article.html")
+	body := Extract(doc, "div.article-body")
+
+	expected := []string{
+		"KLANG: Sebuah kilang terbakar awal pagi tadi, menyebabkan kerugian.",
+		"Menurut Bomba, tiada kecederaan dilaporkan.",
+		"Siasatan lanjut sedang dijalankan & punca belum diketahui.",
+	}
+	if !reflect.DeepEqual(body.Paragraphs, expected) {
+		t.Errorf("got %q, expected %q", body.Paragraphs, expected)
+	}
+
+	// Only the text formatting and the http links are kept.
+	expectedHtml := "<p>KLANG: Sebuah kilang <b>terbakar</b> awal pagi tadi, menyebabkan kerugian.</p>\n" +
+		"<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada  kecederaan\n\tdilaporkan.</p>\n" +
+		"<p>Siasatan lanjut sedang dijalankan &amp; punca belum diketahui.</p>\n"
+	if body.Html != expectedHtml {
+		t.Errorf("got %q, expected %q", body.Html, expectedHtml)
+	}
+
+	// The document is not modified.
+	if doc.Find("script").Length() != 1 {
+		t.Error("got the document modified")
+	}
+
+	// The rules of a newspaper are added to the shared rules.
+	body = Extract(doc, "div.article-body", Rules{Patterns: []*regexp.Regexp{regexp.MustCompile(`^Menurut`)}})
+	if len(body.Paragraphs) != 2 || body.Paragraphs[1] != expected[2] {
+		t.Errorf("got %q", body.Paragraphs)
+	}
+}
+
+func TestExtractReadability(t *testing.T) {
+	doc := readFixture(t, "redesign.html")
+
+	// The selector of the previous design finds nothing.
+	body := Extract(doc, "div.article-body")
+
+	expected := []string{
+		"KUALA LUMPUR: Kerajaan mengumumkan peruntukan tambahan untuk pendidikan, kesihatan dan perumahan.",
+		"Menteri berkata, peruntukan itu akan diagihkan kepada semua negeri, terutama di kawasan luar bandar.",
+		"Pengumuman itu disambut baik oleh pelbagai pihak, termasuk kesatuan guru dan doktor.",
+	}
+	if !reflect.DeepEqual(body.Paragraphs, expected) {
+		t.Errorf("got %q, expected %q", body.Paragraphs, expected)
+	}
+
+	if doc := readFixture(t, "article.html"); len(Extract(doc, "div.missing").Paragraphs) == 0 {
+		t.Error("got no paragraph from the readability of the article")
+	}
+}
+
+func TestHtml(t *testing.T) {
+	source := "<p>KLANG: Sebuah kilang <b>terbakar</b>.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada  kecederaan.</p>\n<p>Baca juga: Bomba</p>\n"
+
+	tests := []struct {
+		paragraphs []string
+		expected   string
+	}{
+		{
+			[]string{"KLANG: Sebuah kilang terbakar.", "Menurut Bomba, tiada kecederaan."},
+			"<p>KLANG: Sebuah kilang <b>terbakar</b>.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada  kecederaan.</p>\n",
+		},
+		// The dateline is cut, the paragraph is escaped.
+		{
+			[]string{"Sebuah kilang terbakar.", "Menurut Bomba, tiada kecederaan."},
+			"<p>Sebuah kilang terbakar.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada  kecederaan.</p>\n",
+		},
+		{[]string{"A & <b>"}, "<p>A &amp; &lt;b&gt;</p>\n"},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		if got := Html(source, test.paragraphs); got != test.expected {
+			t.Errorf("%q: got %q, expected %q", test.paragraphs, got, test.expected)
+		}
+	}
+}
+
+func TestNormalise(t *testing.T) {
+	if got := Normalise(" a \n\t b c "); got != "a b c" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var (
+	positiveClass = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+	negativeClass = regexp.MustCompile(`(?i)ad-|ads|comment|footer|header|menu|nav|promo|related|share|sidebar|social|sponsor|widget`)
+)
+
+// readability returns the block with the most text, the way the readability bookmarklet does.
+// Each paragraph scores its parent, and half to its grandparent, by its length and its commas.
+func readability(root *goquery.Selection) *goquery.Selection {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	add := func(n *html.Node, score float64) {
+		if _, exist := scores[n]; !exist {
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	root.Find("p").Each(func(i int, s *goquery.Selection) {
+		text := Normalise(s.Text())
+		if len(text) < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + float64(min(len(text)/100, 3))
+
+		parent := s.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		add(parent.Get(0), score)
+
+		if grandparent := parent.Parent(); grandparent.Length() > 0 {
+			add(grandparent.Get(0), score/2)
+		}
+	})
+
+	var best *html.Node
+	var bestScore float64
+	for _, node := range candidates {
+		score := scores[node] + classWeight(node)
+		if best == nil || score > bestScore {
+			best = node
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	return root.FindNodes(best)
+}
+
+func classWeight(n *html.Node) float64 {
+	var weight float64
+	for _, key := range []string{"class", "id"} {
+		value := attr(n, key)
+		if value == "" {
+			continue
+		}
+		if negativeClass.MatchString(value) {
+			weight -= 25
+		}
+		if positiveClass.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
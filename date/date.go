@@ -0,0 +1,208 @@
+package date
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Malaysia is the timezone of the dates published by the newspapers.
+// Malaysia has been at UTC+8 without daylight saving since 1982, a fixed zone does not depend on the tzdata of the host.
+var Malaysia = time.FixedZone("Asia/Kuala_Lumpur", 8*60*60)
+
+var months = map[string]time.Month{
+	"january": time.January, "januari": time.January, "jan": time.January,
+	"february": time.February, "februari": time.February, "feb": time.February,
+	"march": time.March, "mac": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may": time.May, "mei": time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "julai": time.July, "jul": time.July,
+	"august": time.August, "ogos": time.August, "aug": time.August, "ogo": time.August, "ogs": time.August,
+	"september": time.September, "sept": time.September, "sep": time.September,
+	"october": time.October, "oktober": time.October, "oct": time.October, "okt": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "disember": time.December, "dec": time.December, "dis": time.December,
+}
+
+// The words ignored in an absolute date.
+var ignored = map[string]bool{
+	"monday": true, "tuesday": true, "wednesday": true, "thursday": true, "friday": true, "saturday": true, "sunday": true,
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+	"isnin": true, "selasa": true, "rabu": true, "khamis": true, "jumaat": true, "sabtu": true, "ahad": true,
+	"at": true, "pada": true, "jam": true, "pukul": true, "published": true, "updated": true, "diterbitkan": true, "dikemaskini": true,
+	"myt": true,
+}
+
+// The periods of the 12-hour clock.
+var (
+	morning   = map[string]bool{"am": true, "a.m.": true, "pagi": true}
+	afternoon = map[string]bool{"pm": true, "p.m.": true, "petang": true, "malam": true}
+)
+
+var units = map[string]time.Duration{
+	"saat": time.Second, "second": time.Second, "seconds": time.Second, "sec": time.Second, "secs": time.Second,
+	"minit": time.Minute, "minute": time.Minute, "minutes": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"jam": time.Hour, "hour": time.Hour, "hours": time.Hour, "hr": time.Hour, "hrs": time.Hour,
+	"hari": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"minggu": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+var (
+	relative  = regexp.MustCompile(`^(\d+|satu|se|an?)\s*(\S+)\s+(yang lalu|lalu|ago)$`)
+	separator = regexp.MustCompile(`[\s,@|]+`)
+	clock     = regexp.MustCompile(`^(\d{1,2})[:.](\d{2})(?:[:.](\d{2}))?(am|pm|a\.m\.|p\.m\.)?$`)
+	numeric   = regexp.MustCompile(`^(\d{1,2})[/\-.](\d{1,2})[/\-.](\d{4})$`)
+)
+
+// Parse parses a date published by the newspapers, in Malay or English. For example:
+//
+//	Ahad, 5 November 2017 @ 7:54 PM
+//	November 14, 2017 @ 8:05pm
+//	14/11/2017 20:05
+//	2017-11-14T20:05:00+08:00
+//	2 jam lalu
+//
+// A date without a timezone is in Malaysia time, a relative date is relative to now. The result is in UTC.
+func Parse(s string, now time.Time) (time.Time, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	if str == "" {
+		return time.Time{}, fmt.Errorf("could not parse the empty date")
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05.000Z0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, Malaysia); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	if t, ok, err := parseRelative(str, now); ok {
+		return t, err
+	}
+
+	return parseAbsolute(s, str)
+}
+
+func parseRelative(str string, now time.Time) (time.Time, bool, error) {
+	switch str {
+	case "baru sahaja", "baru saja", "just now":
+		return now.UTC(), true, nil
+	case "semalam", "yesterday":
+		return now.AddDate(0, 0, -1).UTC(), true, nil
+	}
+
+	m := relative.FindStringSubmatch(strings.Join(strings.Fields(str), " "))
+	if m == nil {
+		return time.Time{}, false, nil
+	}
+
+	unit, exist := units[m[2]]
+	if !exist {
+		return time.Time{}, true, fmt.Errorf("could not parse the unit %s of %q", m[2], str)
+	}
+
+	count := 1
+	if n, err := strconv.Atoi(m[1]); err == nil {
+		count = n
+	}
+
+	return now.Add(-time.Duration(count) * unit).UTC(), true, nil
+}
+
+func parseAbsolute(s, str string) (time.Time, error) {
+	var year, day, hour, minute, second int
+	var month time.Month
+	var hasClock bool
+	var period string
+
+	fields := separator.Split(str, -1)
+	for i := 0; i < len(fields); i++ {
+		f := strings.Trim(fields[i], "()")
+
+		switch {
+		case f == "" || ignored[f]:
+		case months[f] != 0:
+			month = months[f]
+		case morning[f] || afternoon[f]:
+			period = f
+		case f == "tengah" && i+1 < len(fields):
+			// tengah hari is noon, tengah malam is midnight
+			i++
+			if fields[i] == "malam" {
+				period = "am"
+			} else {
+				period = "pm"
+			}
+		case clock.MatchString(f):
+			m := clock.FindStringSubmatch(f)
+			hour, _ = strconv.Atoi(m[1])
+			minute, _ = strconv.Atoi(m[2])
+			second, _ = strconv.Atoi(m[3])
+			if m[4] != "" {
+				period = m[4]
+			}
+			hasClock = true
+		case numeric.MatchString(f):
+			m := numeric.FindStringSubmatch(f)
+			day, _ = strconv.Atoi(m[1])
+			n, _ := strconv.Atoi(m[2])
+			month = time.Month(n)
+			year, _ = strconv.Atoi(m[3])
+		default:
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("could not parse %q of the date %q", f, s)
+			}
+
+			if len(f) == 4 {
+				year = n
+			} else {
+				day = n
+			}
+		}
+	}
+
+	if month < time.January || month > time.December {
+		return time.Time{}, fmt.Errorf("could not parse the month of the date %q", s)
+	}
+
+	if year == 0 {
+		return time.Time{}, fmt.Errorf("could not parse the year of the date %q", s)
+	}
+
+	if day < 1 || day > daysIn(month, year) {
+		return time.Time{}, fmt.Errorf("could not parse the day of the date %q", s)
+	}
+
+	if period != "" {
+		if !hasClock || hour < 1 || hour > 12 {
+			return time.Time{}, fmt.Errorf("could not parse the time of the date %q", s)
+		}
+
+		// 12:30am is 00:30, 12:30pm is 12:30
+		if hour == 12 {
+			hour = 0
+		}
+		if afternoon[period] {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, fmt.Errorf("could not parse the time of the date %q", s)
+	}
+
+	return time.Date(year, month, day, hour, minute, second, 0, Malaysia).UTC(), nil
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,88 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2017, time.November, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"nst", "November 14, 2017 @ 8:05pm", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"nst morning", "November 14, 2017 @ 8:05am", time.Date(2017, 11, 14, 0, 5, 0, 0, time.UTC)},
+		{"nst noon", "November 14, 2017 @ 12:15pm", time.Date(2017, 11, 14, 4, 15, 0, 0, time.UTC)},
+		{"nst midnight", "November 14, 2017 @ 12:15am", time.Date(2017, 11, 13, 16, 15, 0, 0, time.UTC)},
+		{"bharian", "Ahad , 5 November 2017 @ 7:54 PM", time.Date(2017, 11, 5, 11, 54, 0, 0, time.UTC)},
+		{"bharian uppercase", "AHAD, 5 NOVEMBER 2017 @ 7:54PM", time.Date(2017, 11, 5, 11, 54, 0, 0, time.UTC)},
+		{"malay abbreviation", "5 OGO 2017 7:54 PM", time.Date(2017, 8, 5, 11, 54, 0, 0, time.UTC)},
+		{"malay month", "Khamis, 1 Disember 2016 10:30 pagi", time.Date(2016, 12, 1, 2, 30, 0, 0, time.UTC)},
+		{"malay evening", "1 Mac 2018, 8.15 malam", time.Date(2018, 3, 1, 12, 15, 0, 0, time.UTC)},
+		{"malay noon", "1 Mei 2018 12.30 tengah hari", time.Date(2018, 5, 1, 4, 30, 0, 0, time.UTC)},
+		{"malay midnight", "1 Mei 2018 12.30 tengah malam", time.Date(2018, 4, 30, 16, 30, 0, 0, time.UTC)},
+		{"english abbreviation", "Tue, Jan 2, 2018 09:00", time.Date(2018, 1, 2, 1, 0, 0, 0, time.UTC)},
+		{"24-hour clock", "14 November 2017 20:05", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"without time", "14 November 2017", time.Date(2017, 11, 13, 16, 0, 0, 0, time.UTC)},
+		{"numeric", "14/11/2017 20:05", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"rfc3339", "2017-11-14T20:05:00+08:00", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"rfc3339 utc", "2017-11-14T12:05:00Z", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"iso without zone", "2017-11-14 20:05:00", time.Date(2017, 11, 14, 12, 5, 0, 0, time.UTC)},
+		{"hours ago malay", "2 jam lalu", now.Add(-2 * time.Hour)},
+		{"minutes ago malay", "15 minit yang lalu", now.Add(-15 * time.Minute)},
+		{"hour ago malay", "sejam lalu", now.Add(-time.Hour)},
+		{"days ago english", "3 days ago", now.AddDate(0, 0, -3)},
+		{"hour ago english", "an hour ago", now.Add(-time.Hour)},
+		{"yesterday", "Semalam", now.AddDate(0, 0, -1)},
+		{"just now", "baru sahaja", now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %s", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("Parse(%q) location = %s, want UTC", tt.in, got.Location())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	now := time.Date(2017, time.November, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"spaces", "   "},
+		{"short", "pm"},
+		{"unknown month", "14 Brumaire 2017 8:05pm"},
+		{"missing year", "November 14 @ 8:05pm"},
+		{"missing day", "November 2017 @ 8:05pm"},
+		{"invalid day", "31 November 2017"},
+		{"invalid hour", "14 November 2017 13:05pm"},
+		{"invalid minute", "14 November 2017 8:65"},
+		{"24 hour", "14 November 2017 24:00"},
+		{"period without time", "14 November 2017 pm"},
+		{"unknown unit", "2 purnama lalu"},
+		{"garbage", "@@@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Parse(tt.in, now); err == nil {
+				t.Errorf("Parse(%q) = %s, want an error", tt.in, got)
+			}
+		})
+	}
+}
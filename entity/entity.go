@@ -0,0 +1,168 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+type entry struct {
+	id   string
+	typ  string
+	name string
+}
+
+type alias struct {
+	words         []string
+	caseSensitive bool
+	entry         *entry
+}
+
+// aliases are indexed by their first word in lower case.
+var aliases = make(map[string][]*alias)
+
+var places = make(map[string]*entry)
+
+func init() {
+	for _, g := range gazetteer {
+		e := &entry{id: slug(g.Names[0]), typ: g.Type, name: g.Names[0]}
+
+		for _, name := range g.Names {
+			words := split(name)
+			a := &alias{words: words, caseSensitive: name == strings.ToUpper(name), entry: e}
+
+			first := strings.ToLower(words[0])
+			aliases[first] = append(aliases[first], a)
+
+			if e.typ == Place {
+				places[strings.ToLower(strings.Join(words, " "))] = e
+			}
+		}
+	}
+}
+
+func slug(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), "-")
+}
+
+// split returns the words of the text, keeping the apostrophes and hyphens within the words.
+func split(text string) []string {
+	text = strings.Replace(text, "’", "'", -1)
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '-'
+	})
+}
+
+func (a *alias) match(words []string) bool {
+	if len(words) < len(a.words) {
+		return false
+	}
+
+	// The names that are matched case-insensitively must still be capitalised, e.g perak the metal is not Perak.
+	if !a.caseSensitive && !unicode.IsUpper([]rune(words[0])[0]) {
+		return false
+	}
+
+	for i, w := range a.words {
+		if a.caseSensitive && words[i] != w {
+			return false
+		}
+		if !a.caseSensitive && !strings.EqualFold(words[i], w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Extract returns the entities of the gazetteer mentioned in the text, in the order they are first mentioned.
+// The longest alias wins, so Johor Bahru is not also counted as Johor.
+func Extract(text string) []*model.Entity {
+	words := split(text)
+
+	var list []*model.Entity
+	var found = make(map[string]*model.Entity)
+
+	for i := 0; i < len(words); {
+		var best *alias
+		for _, a := range aliases[strings.ToLower(words[i])] {
+			if (best == nil || len(a.words) > len(best.words)) && a.match(words[i:]) {
+				best = a
+			}
+		}
+
+		if best == nil {
+			i++
+			continue
+		}
+
+		if e, exist := found[best.entry.id]; exist {
+			e.Count++
+		} else {
+			e := &model.Entity{Id: best.entry.id, Type: best.entry.typ, Name: best.entry.name, Count: 1}
+			found[e.Id] = e
+			list = append(list, e)
+		}
+
+		i += len(best.words)
+	}
+
+	return list
+}
+
+// A dateline is the place in upper case at the start of the content, e.g KUALA LUMPUR: ...
+var dateline = regexp.MustCompile(`^[A-Z][A-Z .'’-]{1,40}$`)
+var contentDateline = regexp.MustCompile(`^\s*([A-Z][A-Z .'’-]{1,40}):\s*`)
+
+// Tag extracts the entities of the news and normalises its location.
+func Tag(n *model.News) {
+	NormaliseLocation(n)
+	n.Entities = Extract(n.Title + "\n" + n.Location + "\n" + n.Content)
+}
+
+// NormaliseLocation replaces the location with its name in the gazetteer, e.g KUALA LUMPUR to Kuala Lumpur.
+// The providers take the text before the first colon as the location, if it is not a dateline it is put back in the content.
+func NormaliseLocation(n *model.News) {
+	location := strings.TrimSpace(n.Location)
+
+	if location != "" && !isLocation(location) {
+		n.SetContent(n.Location + ": " + n.Content)
+		location = ""
+	}
+
+	if location == "" {
+		if m := contentDateline.FindStringSubmatch(n.Content); m != nil {
+			location = strings.TrimSpace(m[1])
+			n.SetContent(n.Content[len(m[0]):])
+		}
+	}
+
+	if location == "" {
+		n.Location = ""
+		return
+	}
+
+	if e, exist := places[strings.ToLower(strings.Join(split(location), " "))]; exist {
+		n.Location = e.name
+		return
+	}
+
+	n.Location = strings.Title(strings.ToLower(location))
+}
+
+// isLocation returns true for a dateline, or a location that is already normalised.
+func isLocation(location string) bool {
+	if dateline.MatchString(location) {
+		return true
+	}
+
+	if _, exist := places[strings.ToLower(strings.Join(split(location), " "))]; exist {
+		return true
+	}
+
+	return len(split(location)) <= 4 && location == strings.Title(strings.ToLower(location))
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		// The names of several words, and their aliases.
+		{"Floods in Kota Bharu and Pasir Mas.", []string{"kota-bharu:1", "pasir-mas:1"}},
+		{"He flew from Malacca to Pulau Pinang.", []string{"melaka:1", "penang:1"}},
+		{"The Prime Minister's Department said", []string{"prime-minister-s-department:1"}},
+		{"The Prime Minister’s Department said", []string{"prime-minister-s-department:1"}},
+		{"Parti Islam Se-Malaysia won in Kelantan", []string{"pas:1", "kelantan:1"}},
+		// The longest alias wins.
+		{"Johor Bahru is the capital of Johor.", []string{"johor-bahru:1", "johor:1"}},
+		{"Tun Dr Mahathir, or Dr Mahathir, or Mahathir.", []string{"mahathir-mohamad:3"}},
+		{"Bank Negara Malaysia, or Bank Negara, or BNM.", []string{"bank-negara-malaysia:3"}},
+		{"Nurul Izzah Anwar and Anwar Ibrahim", []string{"nurul-izzah-anwar:1", "anwar-ibrahim:1"}},
+		// The acronyms are case-sensitive, the other names must be capitalised.
+		{"UMNO and Umno, not umno", []string{"umno:2"}},
+		{"PAS leaders pas the ball in KL, not kl", []string{"pas:1", "kuala-lumpur:1"}},
+		{"perak, the metal, is not Perak", []string{"perak:1"}},
+		{"KUALA LUMPUR: kuala lumpur", []string{"kuala-lumpur:1"}},
+		{"Nothing to see here.", nil},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, e := range Extract(test.text) {
+			got = append(got, fmt.Sprintf("%s:%d", e.Id, e.Count))
+		}
+
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%q: got %v, expected %v", test.text, got, test.expected)
+		}
+	}
+
+	// The entity has the canonical name and the type.
+	if list := Extract("Kerajaan Pulau Pinang"); len(list) != 1 || list[0].Name != "Penang" || list[0].Type != Place {
+		t.Errorf("got %+v", list)
+	}
+}
+
+func TestNormaliseLocation(t *testing.T) {
+	tests := []struct {
+		location, content string
+		expected          string
+		expectedContent   string
+	}{
+		{"KUALA LUMPUR", "Hujan lebat.", "Kuala Lumpur", "Hujan lebat."},
+		{"JOHOR BARU", "Hujan lebat.", "Johor Bahru", "Hujan lebat."},
+		{"", "ALOR STAR: Hujan lebat.", "Alor Setar", "Hujan lebat."},
+		{"Kampung Baru", "Hujan lebat.", "Kampung Baru", "Hujan lebat."},
+		// Not a dateline, the text is put back in the content.
+		{"Menurut beliau yang juga menteri", "hujan lebat.", "", "Menurut beliau yang juga menteri: hujan lebat."},
+	}
+
+	for _, test := range tests {
+		n := &model.News{Location: test.location}
+		n.SetContent(test.content)
+		NormaliseLocation(n)
+
+		if n.Location != test.expected || n.Content != test.expectedContent {
+			t.Errorf("%q: got %q, %q, expected %q, %q", test.location, n.Location, n.Content, test.expected, test.expectedContent)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package entity
+
+const (
+	Person       = "person"
+	Organisation = "organisation"
+	Place        = "place"
+)
+
+// gazetteer lists the known entities. The first name is the canonical one, the others are its aliases in English and Malay.
+// The names in upper case, usually acronyms, are matched case-sensitively.
+var gazetteer = []struct {
+	Type  string
+	Names []string
+}{
+	// States and federal territories
+	{Place, []string{"Johor"}},
+	{Place, []string{"Kedah"}},
+	{Place, []string{"Kelantan"}},
+	{Place, []string{"Melaka", "Malacca"}},
+	{Place, []string{"Negeri Sembilan"}},
+	{Place, []string{"Pahang"}},
+	{Place, []string{"Perak"}},
+	{Place, []string{"Perlis"}},
+	{Place, []string{"Penang", "Pulau Pinang"}},
+	{Place, []string{"Sabah"}},
+	{Place, []string{"Sarawak"}},
+	{Place, []string{"Selangor"}},
+	{Place, []string{"Terengganu"}},
+	{Place, []string{"Kuala Lumpur", "KL"}},
+	{Place, []string{"Putrajaya"}},
+	{Place, []string{"Labuan"}},
+
+	// Districts and towns
+	{Place, []string{"Johor Bahru", "Johor Baru", "JB"}},
+	{Place, []string{"Batu Pahat"}},
+	{Place, []string{"Muar"}},
+	{Place, []string{"Kluang"}},
+	{Place, []string{"Segamat"}},
+	{Place, []string{"Kota Tinggi"}},
+	{Place, []string{"Pontian"}},
+	{Place, []string{"Mersing"}},
+	{Place, []string{"Iskandar Puteri"}},
+	{Place, []string{"Alor Setar", "Alor Star"}},
+	{Place, []string{"Sungai Petani"}},
+	{Place, []string{"Kulim"}},
+	{Place, []string{"Langkawi"}},
+	{Place, []string{"Kota Bharu"}},
+	{Place, []string{"Pasir Mas"}},
+	{Place, []string{"Tumpat"}},
+	{Place, []string{"Tanah Merah"}},
+	{Place, []string{"Gua Musang"}},
+	{Place, []string{"Alor Gajah"}},
+	{Place, []string{"Jasin"}},
+	{Place, []string{"Seremban"}},
+	{Place, []string{"Port Dickson"}},
+	{Place, []string{"Kuantan"}},
+	{Place, []string{"Temerloh"}},
+	{Place, []string{"Bentong"}},
+	{Place, []string{"Cameron Highlands"}},
+	{Place, []string{"Ipoh"}},
+	{Place, []string{"Taiping"}},
+	{Place, []string{"Teluk Intan"}},
+	{Place, []string{"Kuala Kangsar"}},
+	{Place, []string{"Manjung"}},
+	{Place, []string{"Kangar"}},
+	{Place, []string{"George Town", "Georgetown"}},
+	{Place, []string{"Seberang Perai"}},
+	{Place, []string{"Butterworth"}},
+	{Place, []string{"Bukit Mertajam"}},
+	{Place, []string{"Kota Kinabalu", "KK"}},
+	{Place, []string{"Sandakan"}},
+	{Place, []string{"Tawau"}},
+	{Place, []string{"Lahad Datu"}},
+	{Place, []string{"Keningau"}},
+	{Place, []string{"Semporna"}},
+	{Place, []string{"Kuching"}},
+	{Place, []string{"Miri"}},
+	{Place, []string{"Sibu"}},
+	{Place, []string{"Bintulu"}},
+	{Place, []string{"Shah Alam"}},
+	{Place, []string{"Petaling Jaya", "PJ"}},
+	{Place, []string{"Subang Jaya"}},
+	{Place, []string{"Klang"}},
+	{Place, []string{"Kajang"}},
+	{Place, []string{"Bangi"}},
+	{Place, []string{"Cyberjaya"}},
+	{Place, []string{"Sepang"}},
+	{Place, []string{"Rawang"}},
+	{Place, []string{"Gombak"}},
+	{Place, []string{"Hulu Langat"}},
+	{Place, []string{"Kuala Selangor"}},
+	{Place, []string{"Kuala Terengganu"}},
+	{Place, []string{"Kemaman"}},
+	{Place, []string{"Dungun"}},
+	{Place, []string{"Besut"}},
+
+	// Political parties and coalitions
+	{Organisation, []string{"UMNO", "Umno", "United Malays National Organisation", "Pertubuhan Kebangsaan Melayu Bersatu"}},
+	{Organisation, []string{"PKR", "Parti Keadilan Rakyat", "People's Justice Party"}},
+	{Organisation, []string{"DAP", "Democratic Action Party", "Parti Tindakan Demokratik"}},
+	{Organisation, []string{"PAS", "Parti Islam Se-Malaysia", "Pan-Malaysian Islamic Party"}},
+	{Organisation, []string{"Bersatu", "PPBM", "Parti Pribumi Bersatu Malaysia"}},
+	{Organisation, []string{"Amanah", "Parti Amanah Negara"}},
+	{Organisation, []string{"MCA", "Malaysian Chinese Association"}},
+	{Organisation, []string{"MIC", "Malaysian Indian Congress"}},
+	{Organisation, []string{"Gerakan", "Parti Gerakan Rakyat Malaysia"}},
+	{Organisation, []string{"Warisan", "Parti Warisan Sabah"}},
+	{Organisation, []string{"Barisan Nasional", "BN"}},
+	{Organisation, []string{"Pakatan Harapan", "PH"}},
+	{Organisation, []string{"Perikatan Nasional", "PN"}},
+	{Organisation, []string{"Gabungan Parti Sarawak", "GPS"}},
+
+	// Ministries and agencies
+	{Organisation, []string{"Prime Minister's Department", "Jabatan Perdana Menteri", "JPM"}},
+	{Organisation, []string{"Finance Ministry", "Ministry of Finance", "Kementerian Kewangan"}},
+	{Organisation, []string{"Health Ministry", "Ministry of Health", "Kementerian Kesihatan", "KKM"}},
+	{Organisation, []string{"Education Ministry", "Ministry of Education", "Kementerian Pendidikan", "KPM"}},
+	{Organisation, []string{"Home Ministry", "Ministry of Home Affairs", "Kementerian Dalam Negeri", "KDN"}},
+	{Organisation, []string{"Defence Ministry", "Ministry of Defence", "Kementerian Pertahanan"}},
+	{Organisation, []string{"Foreign Ministry", "Ministry of Foreign Affairs", "Wisma Putra", "Kementerian Luar Negeri"}},
+	{Organisation, []string{"Transport Ministry", "Ministry of Transport", "Kementerian Pengangkutan"}},
+	{Organisation, []string{"Works Ministry", "Ministry of Works", "Kementerian Kerja Raya"}},
+	{Organisation, []string{"Agriculture Ministry", "Ministry of Agriculture", "Kementerian Pertanian"}},
+	{Organisation, []string{"Domestic Trade Ministry", "Ministry of Domestic Trade", "Kementerian Perdagangan Dalam Negeri"}},
+	{Organisation, []string{"Youth and Sports Ministry", "Ministry of Youth and Sports", "Kementerian Belia dan Sukan"}},
+	{Organisation, []string{"Malaysian Anti-Corruption Commission", "MACC", "Suruhanjaya Pencegahan Rasuah Malaysia", "SPRM"}},
+	{Organisation, []string{"Royal Malaysia Police", "Polis Diraja Malaysia", "PDRM"}},
+	{Organisation, []string{"Election Commission", "Suruhanjaya Pilihan Raya", "SPR", "EC"}},
+	{Organisation, []string{"Bank Negara Malaysia", "Bank Negara", "BNM"}},
+	{Organisation, []string{"Attorney-General's Chambers", "Jabatan Peguam Negara", "AGC"}},
+	{Organisation, []string{"Dewan Rakyat"}},
+	{Organisation, []string{"Dewan Negara"}},
+	{Organisation, []string{"1Malaysia Development Berhad", "1MDB"}},
+
+	// Public figures
+	{Person, []string{"Mahathir Mohamad", "Dr Mahathir", "Tun Dr Mahathir", "Mahathir"}},
+	{Person, []string{"Najib Razak", "Najib Tun Razak", "Datuk Seri Najib", "Najib"}},
+	{Person, []string{"Anwar Ibrahim", "Datuk Seri Anwar", "Anwar"}},
+	{Person, []string{"Muhyiddin Yassin", "Tan Sri Muhyiddin", "Muhyiddin"}},
+	{Person, []string{"Ismail Sabri Yaakob", "Ismail Sabri"}},
+	{Person, []string{"Ahmad Zahid Hamidi", "Zahid Hamidi", "Ahmad Zahid"}},
+	{Person, []string{"Wan Azizah Wan Ismail", "Wan Azizah"}},
+	{Person, []string{"Lim Guan Eng", "Guan Eng"}},
+	{Person, []string{"Lim Kit Siang", "Kit Siang"}},
+	{Person, []string{"Mohamed Azmin Ali", "Azmin Ali", "Azmin"}},
+	{Person, []string{"Abdul Hadi Awang", "Hadi Awang"}},
+	{Person, []string{"Mohamad Sabu", "Mat Sabu"}},
+	{Person, []string{"Khairy Jamaluddin", "Khairy"}},
+	{Person, []string{"Hishammuddin Hussein", "Hishammuddin"}},
+	{Person, []string{"Syed Saddiq Syed Abdul Rahman", "Syed Saddiq"}},
+	{Person, []string{"Rafizi Ramli", "Rafizi"}},
+	{Person, []string{"Nurul Izzah Anwar", "Nurul Izzah"}},
+	{Person, []string{"Anthony Loke Siew Fook", "Anthony Loke"}},
+	{Person, []string{"Gobind Singh Deo", "Gobind"}},
+	{Person, []string{"Hannah Yeoh"}},
+	{Person, []string{"Rosmah Mansor", "Rosmah"}},
+	{Person, []string{"Abdullah Ahmad Badawi", "Pak Lah"}},
+	{Person, []string{"Tommy Thomas"}},
+	{Person, []string{"Low Taek Jho", "Jho Low"}},
+	{Person, []string{"Shafie Apdal", "Shafie"}},
+	{Person, []string{"Abang Johari Openg", "Abang Johari"}},
+}
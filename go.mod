@@ -1,5 +1,7 @@
 module github.com/ahmadmuzakkir/scrapenews
 
+go 1.23
+
 require (
 	github.com/PuerkitoBio/goquery v1.4.1
 	github.com/andybalholm/cascadia v0.0.0-20161224141413-349dd0209470
@@ -12,7 +14,14 @@ require (
 	github.com/mattn/go-sqlite3 v1.8.0
 	github.com/pkg/errors v0.8.0
 	github.com/robfig/cron v0.0.0-20180505203441-b41be1df6967
-	golang.org/x/net v0.0.0-20170605033737-59a0b19b5533
-	golang.org/x/sys v0.0.0-20170927054621-314a259e304f
-	google.golang.org/appengine v1.1.0
+	golang.org/x/net v0.26.0
+	golang.org/x/sys v0.21.0
+	google.golang.org/appengine v1.6.8
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
 )
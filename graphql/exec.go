@@ -0,0 +1,700 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Request is a GraphQL request, e.g the JSON body of a POST.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response is the result of a request. The data is nil if the request failed before the execution,
+// e.g a syntax error or a limit exceeded.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Do validates and executes the query of the request.
+func (s *Schema) Do(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+
+	e := &executor{ctx: ctx, schema: s, doc: doc}
+
+	if err := e.setVariables(op, req.Variables); err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+
+	complexity, err := e.analyze(s.Query, op.selections, 1, make(map[string]bool))
+	if err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+	if s.MaxComplexity > 0 && complexity > s.MaxComplexity {
+		return &Response{Errors: []*Error{{
+			Message:   fmt.Sprintf("The query has a complexity of %d, more than the maximum of %d.", complexity, s.MaxComplexity),
+			Locations: []Location{op.loc},
+		}}}
+	}
+
+	data, _ := e.execute(s.Query, nil, op.selections, nil)
+	if data == nil {
+		// The data is null rather than absent, as the execution started.
+		return &Response{Data: json.RawMessage("null"), Errors: e.errors}
+	}
+
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func toError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Message: err.Error()}
+}
+
+func errorAt(loc Location, format string, a ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...), Locations: []Location{loc}}
+}
+
+// operation returns the operation to execute, the name is required if the document has several operations.
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return d.check(d.operations[0])
+	}
+
+	for _, op := range d.operations {
+		if op.name == name {
+			return d.check(op)
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+func (d *document) check(op *operation) (*operation, error) {
+	if op.kind != "query" {
+		return nil, errorAt(op.loc, "The %s operations are not supported.", op.kind)
+	}
+	return op, nil
+}
+
+type executor struct {
+	ctx       context.Context
+	schema    *Schema
+	doc       *document
+	variables map[string]interface{}
+	declared  map[string]bool
+	errors    []*Error
+
+	// visited counts the selections analyzed, the fragments spread several times are counted each time.
+	visited int
+}
+
+// maxVisited bounds the analysis of the queries expanding exponentially, e.g fragments spreading another fragment twice.
+const maxVisited = 100000
+
+// setVariables checks the variables against their definitions, and applies their default values.
+func (e *executor) setVariables(op *operation, values map[string]interface{}) error {
+	e.variables = make(map[string]interface{})
+	e.declared = make(map[string]bool)
+
+	for _, def := range op.variables {
+		if e.declared[def.name] {
+			return errorAt(def.loc, "There can be only one variable named \"$%s\".", def.name)
+		}
+		e.declared[def.name] = true
+
+		t, err := e.typeOf(def.typ)
+		if err != nil {
+			return errorAt(def.loc, "Variable \"$%s\": %s", def.name, err)
+		}
+
+		if _, ok := named(t).(*Object); ok {
+			return errorAt(def.loc, "Variable \"$%s\" cannot be non-input type %q.", def.name, def.typ)
+		}
+
+		v, exist := values[def.name]
+		if !exist && def.def != nil {
+			v, exist, err = e.literal(def.def)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !exist {
+			if _, ok := t.(*NonNull); ok {
+				return errorAt(def.loc, "Variable \"$%s\" of required type %q was not provided.", def.name, def.typ)
+			}
+			continue
+		}
+
+		if _, err := e.coerce(t, v); err != nil {
+			return errorAt(def.loc, "Variable \"$%s\" got invalid value %s; %s", def.name, inspect(v), err)
+		}
+
+		e.variables[def.name] = v
+	}
+
+	return nil
+}
+
+func (e *executor) typeOf(ref *typeRef) (Type, error) {
+	var t Type
+	if ref.elem != nil {
+		elem, err := e.typeOf(ref.elem)
+		if err != nil {
+			return nil, err
+		}
+		t = &List{Of: elem}
+	} else {
+		var exist bool
+		if t, exist = e.schema.named[ref.name]; !exist {
+			return nil, fmt.Errorf("Unknown type %q.", ref.name)
+		}
+	}
+
+	if ref.nonNull {
+		t = &NonNull{Of: t}
+	}
+	return t, nil
+}
+
+// literal returns the JSON value of a literal, exist is false for a variable that is not given.
+func (e *executor) literal(v *value) (interface{}, bool, error) {
+	switch v.kind {
+	case variableValue:
+		if !e.declared[v.raw] {
+			return nil, false, errorAt(v.loc, "Variable \"$%s\" is not defined.", v.raw)
+		}
+		value, exist := e.variables[v.raw]
+		return value, exist, nil
+
+	case intValue, floatValue:
+		f, err := strconv.ParseFloat(v.raw, 64)
+		if err != nil {
+			return nil, false, errorAt(v.loc, "Invalid number %s.", v.raw)
+		}
+		return f, true, nil
+
+	case stringValue:
+		return v.raw, true, nil
+
+	case booleanValue:
+		return v.raw == "true", true, nil
+
+	case nullValue:
+		return nil, true, nil
+
+	case enumValue:
+		return nil, false, errorAt(v.loc, "Unexpected enum value %s, the schema has no enum.", v.raw)
+
+	case listValue:
+		list := make([]interface{}, 0, len(v.list))
+		for _, item := range v.list {
+			value, exist, err := e.literal(item)
+			if err != nil {
+				return nil, false, err
+			}
+			if !exist {
+				value = nil
+			}
+			list = append(list, value)
+		}
+		return list, true, nil
+
+	default:
+		obj := make(map[string]interface{})
+		for _, f := range v.fields {
+			if _, exist := obj[f.name]; exist {
+				return nil, false, errorAt(v.loc, "There can be only one input field named %q.", f.name)
+			}
+
+			value, exist, err := e.literal(f.value)
+			if err != nil {
+				return nil, false, err
+			}
+			if exist {
+				obj[f.name] = value
+			}
+		}
+		return obj, true, nil
+	}
+}
+
+// coerce parses the JSON value into the input type.
+func (e *executor) coerce(t Type, v interface{}) (interface{}, error) {
+	if nonNull, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("Expected non-nullable type %q not to be null.", t)
+		}
+		return e.coerce(nonNull.Of, v)
+	}
+
+	if v == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]interface{})
+		if !ok {
+			// A single value is a list of one item.
+			items = []interface{}{v}
+		}
+
+		var list = make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := e.coerce(t.Of, item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return list, nil
+
+	case *Scalar:
+		return t.Parse(v)
+
+	case *InputObject:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Expected type %q to be an object.", t.Name)
+		}
+
+		for name := range obj {
+			if inputField(t, name) == nil {
+				return nil, fmt.Errorf("Field %q is not defined by type %q.", name, t.Name)
+			}
+		}
+
+		var result = make(map[string]interface{})
+		for _, f := range t.Fields {
+			value, exist := obj[f.Name]
+			if !exist {
+				if f.Default != nil {
+					result[f.Name] = f.Default
+				} else if _, ok := f.Type.(*NonNull); ok {
+					return nil, fmt.Errorf("Field \"%s.%s\" of required type %q was not provided.", t.Name, f.Name, f.Type)
+				}
+				continue
+			}
+
+			parsed, err := e.coerce(f.Type, value)
+			if err != nil {
+				return nil, fmt.Errorf("In field %q: %s", f.Name, err)
+			}
+			result[f.Name] = parsed
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%s is not an input type.", t)
+}
+
+func inputField(t *InputObject, name string) *Argument {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// arguments returns the arguments of the field, parsed and with their default values.
+func (e *executor) arguments(defs []*Argument, args []*argument, loc Location) (map[string]interface{}, error) {
+	for _, arg := range args {
+		var found bool
+		for _, def := range defs {
+			found = found || def.Name == arg.name
+		}
+		if !found {
+			return nil, errorAt(arg.loc, "Unknown argument %q.", arg.name)
+		}
+	}
+
+	var result = make(map[string]interface{})
+	for _, def := range defs {
+		var v interface{}
+		var exist bool
+		var err error
+
+		for _, arg := range args {
+			if arg.name == def.Name {
+				if v, exist, err = e.literal(arg.value); err != nil {
+					return nil, err
+				}
+				loc = arg.loc
+			}
+		}
+
+		if !exist {
+			if def.Default != nil {
+				result[def.Name] = def.Default
+			} else if _, ok := def.Type.(*NonNull); ok {
+				return nil, errorAt(loc, "Argument %q of required type %q was not provided.", def.Name, def.Type)
+			}
+			continue
+		}
+
+		parsed, err := e.coerce(def.Type, v)
+		if err != nil {
+			return nil, errorAt(loc, "Argument %q has an invalid value: %s", def.Name, err)
+		}
+		result[def.Name] = parsed
+	}
+
+	return result, nil
+}
+
+var directiveArgs = []*Argument{{Name: "if", Type: &NonNull{Of: Boolean}}}
+
+// included returns false if the @skip or the @include directives exclude the selection.
+func (e *executor) included(directives []*directive) (bool, error) {
+	for _, d := range directives {
+		if d.name != "skip" && d.name != "include" {
+			return false, errorAt(d.loc, "Unknown directive \"@%s\".", d.name)
+		}
+
+		args, err := e.arguments(directiveArgs, d.args, d.loc)
+		if err != nil {
+			return false, err
+		}
+
+		if args["if"] == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// analyze validates the selections of the object, and returns their complexity.
+// The fragments being visited are tracked to reject the cycles.
+func (e *executor) analyze(t *Object, sels []selection, depth int, visiting map[string]bool) (int, error) {
+	var complexity int
+
+	for _, sel := range sels {
+		e.visited++
+		if e.visited > maxVisited {
+			return 0, errorAt(sel.(locator).location(), "The query is too large.")
+		}
+
+		switch sel := sel.(type) {
+		case *field:
+			if _, err := e.included(sel.directives); err != nil {
+				return 0, err
+			}
+
+			if e.schema.MaxDepth > 0 && depth > e.schema.MaxDepth {
+				return 0, errorAt(sel.loc, "The query exceeds the maximum depth of %d.", e.schema.MaxDepth)
+			}
+
+			if sel.name == "__typename" {
+				if len(sel.selections) > 0 {
+					return 0, errorAt(sel.loc, "Field \"__typename\" must not have a selection since type \"String!\" has no subfields.")
+				}
+				continue
+			}
+
+			def := t.field(sel.name)
+			if def == nil {
+				return 0, errorAt(sel.loc, "Cannot query field %q on type %q.", sel.name, t.Name)
+			}
+
+			args, err := e.arguments(def.Args, sel.args, sel.loc)
+			if err != nil {
+				return 0, err
+			}
+
+			obj, isObject := named(def.Type).(*Object)
+			if !isObject {
+				if len(sel.selections) > 0 {
+					return 0, errorAt(sel.loc, "Field %q must not have a selection since type %q has no subfields.", sel.name, def.Type)
+				}
+				complexity++
+				continue
+			}
+
+			if len(sel.selections) == 0 {
+				return 0, errorAt(sel.loc, "Field %q of type %q must have a selection of subfields.", sel.name, def.Type)
+			}
+
+			children, err := e.analyze(obj, sel.selections, depth+1, visiting)
+			if err != nil {
+				return 0, err
+			}
+
+			multiplier := 1
+			if def.Complexity != nil {
+				multiplier = def.Complexity(args)
+			}
+			complexity += 1 + multiplier*children
+
+		case *fragmentSpread:
+			if _, err := e.included(sel.directives); err != nil {
+				return 0, err
+			}
+
+			f, exist := e.doc.fragments[sel.name]
+			if !exist {
+				return 0, errorAt(sel.loc, "Unknown fragment %q.", sel.name)
+			}
+			if visiting[sel.name] {
+				return 0, errorAt(sel.loc, "Cannot spread fragment %q within itself.", sel.name)
+			}
+			if f.on != t.Name {
+				return 0, errorAt(sel.loc, "Fragment %q cannot be spread here as objects of type %q can never be of type %q.", sel.name, t.Name, f.on)
+			}
+
+			visiting[sel.name] = true
+			children, err := e.analyze(t, f.selections, depth, visiting)
+			delete(visiting, sel.name)
+			if err != nil {
+				return 0, err
+			}
+			complexity += children
+
+		case *inlineFragment:
+			if _, err := e.included(sel.directives); err != nil {
+				return 0, err
+			}
+			if sel.on != "" && sel.on != t.Name {
+				return 0, errorAt(sel.loc, "Fragment cannot be spread here as objects of type %q can never be of type %q.", t.Name, sel.on)
+			}
+
+			children, err := e.analyze(t, sel.selections, depth, visiting)
+			if err != nil {
+				return 0, err
+			}
+			complexity += children
+		}
+	}
+
+	return complexity, nil
+}
+
+// collect returns the fields of the selections, following the fragments and skipping the excluded selections.
+// The selections are validated by analyze first.
+func (e *executor) collect(sels []selection) []*field {
+	var fields []*field
+
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *field:
+			if ok, _ := e.included(sel.directives); ok {
+				fields = append(fields, sel)
+			}
+
+		case *fragmentSpread:
+			if ok, _ := e.included(sel.directives); ok {
+				fields = append(fields, e.collect(e.doc.fragments[sel.name].selections)...)
+			}
+
+		case *inlineFragment:
+			if ok, _ := e.included(sel.directives); ok {
+				fields = append(fields, e.collect(sel.selections)...)
+			}
+		}
+	}
+
+	return fields
+}
+
+func (e *executor) addError(path []interface{}, loc Location, message string) {
+	e.errors = append(e.errors, &Error{
+		Message:   message,
+		Locations: []Location{loc},
+		Path:      append([]interface{}(nil), path...),
+	})
+}
+
+// execute resolves the fields of the object. It returns false if a non-null field is null,
+// then the object is null in its parent, the error is already added.
+func (e *executor) execute(t *Object, source interface{}, sels []selection, path []interface{}) (*orderedMap, bool) {
+	result := &orderedMap{values: make(map[string]interface{})}
+
+	// The fields of the same key are merged, e.g a field selected by two fragments.
+	var keys []string
+	var grouped = make(map[string][]*field)
+	for _, f := range e.collect(sels) {
+		if _, exist := grouped[f.key()]; !exist {
+			keys = append(keys, f.key())
+		}
+		grouped[f.key()] = append(grouped[f.key()], f)
+	}
+
+	for _, key := range keys {
+		fields := grouped[key]
+		f := fields[0]
+		fieldPath := append(path[:len(path):len(path)], key)
+
+		if f.name == "__typename" {
+			result.set(key, t.Name)
+			continue
+		}
+
+		def := t.field(f.name)
+
+		args, err := e.arguments(def.Args, f.args, f.loc)
+		if err != nil {
+			e.addError(fieldPath, f.loc, err.Error())
+			return nil, false
+		}
+
+		var v interface{}
+		var ok bool
+
+		if def.Resolve == nil {
+			e.addError(fieldPath, f.loc, fmt.Sprintf("The field %s.%s has no resolver.", t.Name, def.Name))
+		} else if v, err = def.Resolve(Params{Context: e.ctx, Source: source, Args: args, fields: fields, exec: e}); err != nil {
+			e.addError(fieldPath, f.loc, err.Error())
+		} else {
+			v, ok = e.complete(def.Type, fields, v, fieldPath)
+		}
+
+		if !ok {
+			if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, false
+			}
+			v = nil
+		}
+
+		result.set(key, v)
+	}
+
+	return result, true
+}
+
+// complete returns the JSON value of the value resolved, it returns false if the value is null because of an error.
+func (e *executor) complete(t Type, fields []*field, v interface{}, path []interface{}) (interface{}, bool) {
+	if nonNull, ok := t.(*NonNull); ok {
+		result, ok := e.complete(nonNull.Of, fields, v, path)
+		if !ok {
+			return nil, false
+		}
+		if result == nil {
+			e.addError(path, fields[0].loc, fmt.Sprintf("Cannot return null for non-nullable field %s.", fields[0].name))
+			return nil, false
+		}
+		return result, true
+	}
+
+	if isNil(v) {
+		return nil, true
+	}
+
+	switch t := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(path, fields[0].loc, fmt.Sprintf("Expected a list for the field %s.", fields[0].name))
+			return nil, false
+		}
+
+		var list = make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, ok := e.complete(t.Of, fields, rv.Index(i).Interface(), append(path[:len(path):len(path)], i))
+			if !ok {
+				if _, nonNull := t.Of.(*NonNull); nonNull {
+					return nil, false
+				}
+				item = nil
+			}
+			list = append(list, item)
+		}
+		return list, true
+
+	case *Scalar:
+		result, err := t.Serialize(v)
+		if err != nil {
+			e.addError(path, fields[0].loc, err.Error())
+			return nil, false
+		}
+		return result, true
+
+	case *Object:
+		var sels []selection
+		for _, f := range fields {
+			sels = append(sels, f.selections...)
+		}
+
+		result, ok := e.execute(t, v, sels, path)
+		if !ok {
+			return nil, false
+		}
+		return result, true
+	}
+
+	e.addError(path, fields[0].loc, fmt.Sprintf("%s is not an output type.", t))
+	return nil, false
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// orderedMap is a JSON object keeping the order of the fields of the query.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, v interface{}) {
+	if _, exist := m.values[key]; !exist {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,312 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	id   string
+	name string
+}
+
+func testSchema(t *testing.T, selected *[]string) *Schema {
+	itemType := &Object{Name: "Item"}
+	itemType.Fields = []*Field{
+		{Name: "id", Type: &NonNull{Of: ID}, Resolve: func(p Params) (interface{}, error) {
+			return p.Source.(*item).id, nil
+		}},
+		{Name: "name", Type: String, Resolve: func(p Params) (interface{}, error) {
+			return p.Source.(*item).name, nil
+		}},
+		{Name: "broken", Type: &NonNull{Of: String}, Resolve: func(p Params) (interface{}, error) {
+			return nil, nil
+		}},
+		{
+			Name: "children",
+			Type: &NonNull{Of: &List{Of: &NonNull{Of: itemType}}},
+			Args: []*Argument{{Name: "first", Type: Int, Default: 2}},
+			Resolve: func(p Params) (interface{}, error) {
+				return items(p.Args["first"].(int)), nil
+			},
+			Complexity: func(args map[string]interface{}) int { return args["first"].(int) },
+		},
+	}
+
+	input := &InputObject{
+		Name: "EchoInput",
+		Fields: []*Argument{
+			{Name: "text", Type: &NonNull{Of: String}},
+			{Name: "times", Type: Int, Default: 1},
+			{Name: "tags", Type: &List{Of: String}},
+		},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name: "hello",
+				Type: &NonNull{Of: String},
+				Args: []*Argument{{Name: "name", Type: String, Default: "world"}},
+				Resolve: func(p Params) (interface{}, error) {
+					return "hello " + p.Args["name"].(string), nil
+				},
+			},
+			{
+				Name: "item",
+				Type: itemType,
+				Args: []*Argument{{Name: "id", Type: &NonNull{Of: ID}}},
+				Resolve: func(p Params) (interface{}, error) {
+					if selected != nil {
+						*selected = p.Selected()
+					}
+					return &item{id: p.Args["id"].(string), name: "item"}, nil
+				},
+			},
+			{
+				Name: "items",
+				Type: &NonNull{Of: &List{Of: &NonNull{Of: itemType}}},
+				Args: []*Argument{{Name: "first", Type: Int, Default: 2}},
+				Resolve: func(p Params) (interface{}, error) {
+					return items(p.Args["first"].(int)), nil
+				},
+				Complexity: func(args map[string]interface{}) int { return args["first"].(int) },
+			},
+			{
+				Name: "fail",
+				Type: String,
+				Resolve: func(p Params) (interface{}, error) {
+					return nil, fmt.Errorf("failed")
+				},
+			},
+			{
+				Name: "echo",
+				Type: String,
+				Args: []*Argument{{Name: "input", Type: input}},
+				Resolve: func(p Params) (interface{}, error) {
+					data, err := json.Marshal(p.Args["input"])
+					return string(data), err
+				},
+			},
+		},
+	}
+
+	s, err := NewSchema(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func items(n int) []*item {
+	var list []*item
+	for i := 1; i <= n; i++ {
+		list = append(list, &item{id: fmt.Sprint(i), name: fmt.Sprintf("item %d", i)})
+	}
+	return list
+}
+
+func do(t *testing.T, s *Schema, query string, variables map[string]interface{}) string {
+	resp := s.Do(context.Background(), Request{Query: query, Variables: variables})
+
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(resp); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func TestDo(t *testing.T) {
+	s := testSchema(t, nil)
+
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			`{ a: hello, hello(name: "you") }`, nil,
+			`{"data":{"a":"hello world","hello":"hello you"}}`,
+		},
+		{
+			`query Q($name: String = "default") { hello(name: $name) }`, nil,
+			`{"data":{"hello":"hello default"}}`,
+		},
+		{
+			`query Q($name: String = "default") { hello(name: $name) }`, map[string]interface{}{"name": "variable"},
+			`{"data":{"hello":"hello variable"}}`,
+		},
+		{
+			`query ($skip: Boolean!) {
+				items(first: 3) { ...Ids ... on Item { name @skip(if: $skip) } }
+				item(id: 7) { id name @include(if: $skip) __typename }
+			}
+			fragment Ids on Item { id }`,
+			map[string]interface{}{"skip": true},
+			`{"data":{"items":[{"id":"1"},{"id":"2"},{"id":"3"}],"item":{"id":"7","name":"item","__typename":"Item"}}}`,
+		},
+		{
+			`{ items(first: 1) { id children(first: 1) { id } } }`, nil,
+			`{"data":{"items":[{"id":"1","children":[{"id":"1"}]}]}}`,
+		},
+		{
+			`{ echo(input: {text: "a\nb", tags: "single"}) }`, nil,
+			`{"data":{"echo":"{\"tags\":[\"single\"],\"text\":\"a\\nb\",\"times\":1}"}}`,
+		},
+		{
+			`query ($input: EchoInput) { echo(input: $input) }`, map[string]interface{}{"input": map[string]interface{}{"text": "x", "times": 2.0}},
+			`{"data":{"echo":"{\"text\":\"x\",\"times\":2}"}}`,
+		},
+		{
+			`{ echo(input: {text: """
+				block
+				  indented
+			"""}) }`, nil,
+			`{"data":{"echo":"{\"text\":\"block\\n  indented\",\"times\":1}"}}`,
+		},
+
+		// The errors of the fields are in the errors, the nullable parent is null.
+		{
+			`{ hello fail }`, nil,
+			`{"data":{"hello":"hello world","fail":null},"errors":[{"message":"failed","locations":[{"line":1,"column":9}],"path":["fail"]}]}`,
+		},
+		{
+			`{ item(id: "1") { id broken } hello }`, nil,
+			`{"data":{"item":null,"hello":"hello world"},"errors":[{"message":"Cannot return null for non-nullable field broken.","locations":[{"line":1,"column":22}],"path":["item","broken"]}]}`,
+		},
+		{
+			`{ items { broken } }`, nil,
+			`{"data":null,"errors":[{"message":"Cannot return null for non-nullable field broken.","locations":[{"line":1,"column":11}],"path":["items",0,"broken"]}]}`,
+		},
+
+		// The invalid queries are not executed.
+		{
+			`{ hello(`, nil,
+			`{"errors":[{"message":"Syntax Error: Unexpected <EOF>","locations":[{"line":1,"column":9}]}]}`,
+		},
+		{
+			"{\n  unknown\n}", nil,
+			`{"errors":[{"message":"Cannot query field \"unknown\" on type \"Query\".","locations":[{"line":2,"column":3}]}]}`,
+		},
+		{
+			`{ items }`, nil,
+			`{"errors":[{"message":"Field \"items\" of type \"[Item!]!\" must have a selection of subfields.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			`{ hello { id } }`, nil,
+			`{"errors":[{"message":"Field \"hello\" must not have a selection since type \"String!\" has no subfields.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			`{ item { id } }`, nil,
+			`{"errors":[{"message":"Argument \"id\" of required type \"ID!\" was not provided.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			`{ items(first: "2") { id } }`, nil,
+			`{"errors":[{"message":"Argument \"first\" has an invalid value: Int cannot represent non 32-bit signed integer value: \"2\"","locations":[{"line":1,"column":9}]}]}`,
+		},
+		{
+			`query ($id: ID!) { item(id: $id) { id } }`, nil,
+			`{"errors":[{"message":"Variable \"$id\" of required type \"ID!\" was not provided.","locations":[{"line":1,"column":8}]}]}`,
+		},
+		{
+			`{ item(id: $id) { id } }`, nil,
+			`{"errors":[{"message":"Variable \"$id\" is not defined.","locations":[{"line":1,"column":12}]}]}`,
+		},
+		{
+			`{ items { ...A } } fragment A on Item { children { ...A } }`, nil,
+			`{"errors":[{"message":"Cannot spread fragment \"A\" within itself.","locations":[{"line":1,"column":52}]}]}`,
+		},
+		{
+			`mutation { hello }`, nil,
+			`{"errors":[{"message":"The mutation operations are not supported.","locations":[{"line":1,"column":1}]}]}`,
+		},
+		{
+			`query A { hello } query B { hello }`, nil,
+			`{"errors":[{"message":"Must provide operation name if query contains multiple operations."}]}`,
+		},
+	}
+
+	for _, test := range tests {
+		if got := do(t, s, test.query, test.variables); got != test.expected {
+			t.Errorf("%s\ngot      %s\nexpected %s", test.query, got, test.expected)
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := testSchema(t, nil)
+	s.MaxDepth = 3
+	s.MaxComplexity = 111
+
+	// The depth of id is 3, its complexity is 1 + 10 * (1 + 10 * 1).
+	ok := `{ items(first: 10) { children(first: 10) { id } } }`
+	if got := do(t, s, ok, nil); strings.Contains(got, "errors") {
+		t.Errorf("%s: %s", ok, got)
+	}
+
+	tests := map[string]string{
+		`{ items(first: 10) { children(first: 10) { children { id } } } }`:       "The query exceeds the maximum depth of 3.",
+		`{ items { ...F } } fragment F on Item { children { children { id } } }`: "The query exceeds the maximum depth of 3.",
+		`{ items(first: 10) { children(first: 10) { id name } } }`:               "The query has a complexity of 211, more than the maximum of 111.",
+		`{ a: items(first: 10) { id } b: items(first: 10) { id } c: hello }`:     "",
+		`{ items(first: 0) { ...A } }
+		fragment A on Item { ...B ...B } fragment B on Item { ...C ...C } fragment C on Item { ...D ...D }
+		fragment D on Item { ...E ...E } fragment E on Item { ...F ...F } fragment F on Item { ...G ...G }
+		fragment G on Item { ...H ...H } fragment H on Item { ...I ...I } fragment I on Item { ...J ...J }
+		fragment J on Item { ...K ...K } fragment K on Item { ...L ...L } fragment L on Item { ...M ...M }
+		fragment M on Item { ...N ...N } fragment N on Item { ...O ...O } fragment O on Item { ...P ...P }
+		fragment P on Item { ...Q ...Q } fragment Q on Item { ...R ...R } fragment R on Item { id id }`: "The query is too large.",
+	}
+
+	for query, expected := range tests {
+		resp := s.Do(context.Background(), Request{Query: query})
+
+		var got string
+		if len(resp.Errors) > 0 {
+			got = resp.Errors[0].Message
+		}
+		if got != expected {
+			t.Errorf("%s: got %q, expected %q", query, got, expected)
+		}
+		if expected != "" && resp.Data != nil {
+			t.Errorf("%s: the query is executed", query)
+		}
+	}
+}
+
+func TestSelected(t *testing.T) {
+	var selected []string
+	s := testSchema(t, &selected)
+
+	do(t, s, `{ item(id: 1) { id ...F ... @skip(if: true) { broken } } } fragment F on Item { name id }`, nil)
+
+	if !reflect.DeepEqual(selected, []string{"id", "name"}) {
+		t.Errorf("got %v", selected)
+	}
+}
+
+func TestSchemaString(t *testing.T) {
+	s := testSchema(t, nil)
+
+	for _, expected := range []string{
+		"type Query {\n  hello(name: String = \"world\"): String!\n",
+		"  items(first: Int = 2): [Item!]!\n",
+		"input EchoInput {\n  text: String!\n  times: Int = 1\n  tags: [String]\n}\n",
+		"type Item {\n  id: ID!\n",
+	} {
+		if !strings.Contains(s.String(), expected) {
+			t.Errorf("%q not found in\n%s", expected, s.String())
+		}
+	}
+
+	if _, err := NewSchema(&Object{Name: "Query", Fields: []*Field{{Name: "a", Type: &Object{Name: "Query"}}}}); err == nil {
+		t.Error("expected an error for two types of the same name")
+	}
+}
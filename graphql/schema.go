@@ -0,0 +1,427 @@
+// Package graphql executes the GraphQL queries against a schema built in Go.
+// It supports the queries with variables, fragments, aliases and the @skip and @include directives,
+// and limits their depth and their complexity. The mutations, the subscriptions and the introspection are not supported,
+// the schema is printed in the schema definition language by Schema.String instead.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Type is a *Scalar, an *Object, an *InputObject, a *List or a *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type, e.g String or a DateTime.
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize returns the JSON value of the value resolved.
+	Serialize func(v interface{}) (interface{}, error)
+	// Parse returns the value of a JSON value or a literal of the query, e.g an Int is a float64.
+	Parse func(v interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is an output type made of fields.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// ResolveFunc returns the value of a field, of its type or its Go equivalent,
+// e.g a slice for a list, and the source of the fields of an object.
+type ResolveFunc func(p Params) (interface{}, error)
+
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Argument
+	Resolve     ResolveFunc
+	// Complexity returns the number of times the selections of the field are counted,
+	// e.g the number of items of a page. It is counted once if nil.
+	Complexity func(args map[string]interface{}) int
+}
+
+type Argument struct {
+	Name        string
+	Description string
+	Type        Type
+	// Default is the value of the argument if it is not given, after parsing, e.g an int for an Int.
+	Default interface{}
+}
+
+// InputObject is an input type made of fields, e.g a filter.
+type InputObject struct {
+	Name        string
+	Description string
+	Fields      []*Argument
+}
+
+func (o *InputObject) String() string { return o.Name }
+
+type List struct {
+	Of Type
+}
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+type NonNull struct {
+	Of Type
+}
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+var String = &Scalar{
+	Name: "String",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("String cannot represent %v", v)
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("String cannot represent a non string value: %s", inspect(v))
+	},
+}
+
+var ID = &Scalar{
+	Name: "ID",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("ID cannot represent %v", v)
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) {
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			}
+		}
+		return nil, fmt.Errorf("ID cannot represent value: %s", inspect(v))
+	},
+}
+
+var Int = &Scalar{
+	Name: "Int",
+	Serialize: func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case int:
+			return v, nil
+		case int32:
+			return int(v), nil
+		case int64:
+			return v, nil
+		}
+		return nil, fmt.Errorf("Int cannot represent %v", v)
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		f, ok := v.(float64)
+		if !ok || f != math.Trunc(f) || f > math.MaxInt32 || f < math.MinInt32 {
+			return nil, fmt.Errorf("Int cannot represent non 32-bit signed integer value: %s", inspect(v))
+		}
+		return int(f), nil
+	},
+}
+
+var Float = &Scalar{
+	Name: "Float",
+	Serialize: func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case float32:
+			return float64(v), nil
+		case int:
+			return float64(v), nil
+		}
+		return nil, fmt.Errorf("Float cannot represent %v", v)
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		if f, ok := v.(float64); ok {
+			return f, nil
+		}
+		return nil, fmt.Errorf("Float cannot represent non numeric value: %s", inspect(v))
+	},
+}
+
+var Boolean = &Scalar{
+	Name: "Boolean",
+	Serialize: func(v interface{}) (interface{}, error) {
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent %v", v)
+	},
+	Parse: func(v interface{}) (interface{}, error) {
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("Boolean cannot represent a non boolean value: %s", inspect(v))
+	},
+}
+
+func inspect(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Params are the parameters of a ResolveFunc.
+type Params struct {
+	Context context.Context
+	// Source is the value of the parent object, nil for the fields of the query.
+	Source interface{}
+	// Args are the arguments, parsed and with their default values.
+	Args map[string]interface{}
+
+	fields []*field
+	exec   *executor
+}
+
+// Selected returns the names of the fields selected under the field, or under the path of the fields below it,
+// e.g Selected("edges", "node") of a connection. The resolvers use it to read only what is selected.
+func (p Params) Selected(path ...string) []string {
+	var sels []selection
+	for _, f := range p.fields {
+		sels = append(sels, f.selections...)
+	}
+
+	for _, name := range path {
+		var next []selection
+		for _, f := range p.exec.collect(sels) {
+			if f.name == name {
+				next = append(next, f.selections...)
+			}
+		}
+		sels = next
+	}
+
+	var names []string
+	var seen = make(map[string]bool)
+	for _, f := range p.exec.collect(sels) {
+		if !seen[f.name] {
+			seen[f.name] = true
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// Schema is the schema of the queries.
+type Schema struct {
+	Query *Object
+	// MaxDepth is the maximum nesting of the fields, e.g 2 for { news { title } }. It is not limited if 0.
+	MaxDepth int
+	// MaxComplexity is the maximum number of fields resolved, counting the lists by their Field.Complexity.
+	// It is not limited if 0.
+	MaxComplexity int
+
+	types []Type
+	named map[string]Type
+}
+
+// NewSchema returns the schema of the query type, the names of its types must be unique.
+func NewSchema(query *Object) (*Schema, error) {
+	s := &Schema{Query: query, named: make(map[string]Type)}
+
+	for _, scalar := range []*Scalar{String, ID, Int, Float, Boolean} {
+		s.named[scalar.Name] = scalar
+	}
+
+	if err := s.add(query); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// add adds the named type and the types of its fields.
+func (s *Schema) add(t Type) error {
+	switch v := t.(type) {
+	case *List:
+		return s.add(v.Of)
+	case *NonNull:
+		return s.add(v.Of)
+	}
+
+	name := t.String()
+	if existing, exist := s.named[name]; exist {
+		if existing != t {
+			return fmt.Errorf("graphql: two types are named %s", name)
+		}
+		return nil
+	}
+
+	s.named[name] = t
+	s.types = append(s.types, t)
+
+	switch v := t.(type) {
+	case *Object:
+		for _, f := range v.Fields {
+			if err := s.add(f.Type); err != nil {
+				return err
+			}
+			for _, arg := range f.Args {
+				if err := s.addInput(arg.Type); err != nil {
+					return err
+				}
+			}
+		}
+
+	case *InputObject:
+		for _, f := range v.Fields {
+			if err := s.addInput(f.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) addInput(t Type) error {
+	switch named(t).(type) {
+	case *Scalar, *InputObject:
+		return s.add(t)
+	}
+	return fmt.Errorf("graphql: %s is not an input type", t)
+}
+
+// named returns the type without its list and non-null wrappers.
+func named(t Type) Type {
+	for {
+		switch v := t.(type) {
+		case *List:
+			t = v.Of
+		case *NonNull:
+			t = v.Of
+		default:
+			return t
+		}
+	}
+}
+
+// String returns the schema in the schema definition language.
+func (s *Schema) String() string {
+	var b strings.Builder
+
+	var types = append([]Type(nil), s.types...)
+	sort.SliceStable(types[1:], func(i, j int) bool {
+		return types[i+1].String() < types[j+1].String()
+	})
+
+	for i, t := range types {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		switch v := t.(type) {
+		case *Scalar:
+			description(&b, "", v.Description)
+			fmt.Fprintf(&b, "scalar %s\n", v.Name)
+
+		case *Object:
+			description(&b, "", v.Description)
+			fmt.Fprintf(&b, "type %s {\n", v.Name)
+			for _, f := range v.Fields {
+				description(&b, "  ", f.Description)
+				fmt.Fprintf(&b, "  %s%s: %s\n", f.Name, arguments(f.Args), f.Type)
+			}
+			b.WriteString("}\n")
+
+		case *InputObject:
+			description(&b, "", v.Description)
+			fmt.Fprintf(&b, "input %s {\n", v.Name)
+			for _, f := range v.Fields {
+				description(&b, "  ", f.Description)
+				fmt.Fprintf(&b, "  %s\n", inputValue(f))
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	return b.String()
+}
+
+func description(b *strings.Builder, indent string, s string) {
+	if s == "" {
+		return
+	}
+	if !strings.Contains(s, "\n") && !strings.Contains(s, `"`) {
+		fmt.Fprintf(b, "%s\"%s\"\n", indent, s)
+		return
+	}
+
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+	for _, line := range strings.Split(strings.Replace(s, `"""`, `\"""`, -1), "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, line)
+	}
+	fmt.Fprintf(b, "%s\"\"\"\n", indent)
+}
+
+func arguments(args []*Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	var list []string
+	for _, arg := range args {
+		list = append(list, inputValue(arg))
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
+
+func inputValue(arg *Argument) string {
+	s := arg.Name + ": " + arg.Type.String()
+	if arg.Default != nil {
+		s += " = " + inspect(arg.Default)
+	}
+	return s
+}
+
+// Location is a position in the query, starting at 1.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error is an error of the response, its path is the path of the field in the data.
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
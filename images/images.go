@@ -0,0 +1,242 @@
+package images
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	// Register the decoders used by image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/pkg/errors"
+)
+
+// The largest width or height of a thumbnail.
+const thumbnailSize = 320
+
+// Pictures larger than this are not mirrored.
+const maxSize = 20 << 20
+
+const thumbnailSuffix = ".thumb.jpg"
+
+var ErrNotFound = errors.New("The image is not found")
+var ErrNotImage = errors.New("The url is not an image")
+var ErrTooLarge = errors.New("The image is too large")
+
+var hashPattern = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// Store mirrors the pictures of the news on the local disk, content-addressed by their SHA-256.
+// The images are stored as <dir>/<hash[:2]>/<hash> along with a <hash>.thumb.jpg thumbnail.
+type Store struct {
+	httpClient *http.Client
+	dir        string
+}
+
+func NewStore(hc *http.Client, dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "[images] mkdir error")
+	}
+
+	return &Store{httpClient: hc, dir: dir}, nil
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Mirror downloads the picture, then fills its hash, dimensions and MIME type.
+func (s *Store) Mirror(p *model.Picture) error {
+	if p.ImageUrl == "" || p.Hash != "" {
+		return nil
+	}
+
+	data, err := s.download(p.ImageUrl)
+	if err != nil {
+		return err
+	}
+
+	// Only the formats we can decode are mirrored, the pictures must have their dimensions and thumbnail.
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ErrNotImage
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(data))
+	path := s.path(hash)
+
+	// The thumbnail is written last, the image is stored once it has its thumbnail.
+	thumbPath := path + thumbnailSuffix
+	if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return ErrNotImage
+		}
+
+		buf := &bytes.Buffer{}
+		if err := jpeg.Encode(buf, thumbnail(img, thumbnailSize), &jpeg.Options{Quality: 80}); err != nil {
+			return errors.Wrap(err, "[images] jpeg encode error")
+		}
+
+		if err := write(path, data); err != nil {
+			return err
+		}
+
+		if err := write(thumbPath, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	p.Hash = hash
+	p.MimeType = "image/" + format
+	p.Width = config.Width
+	p.Height = config.Height
+
+	return nil
+}
+
+// Open returns the image, or its thumbnail, of the hash.
+func (s *Store) Open(hash string, thumbnail bool) (*os.File, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, ErrNotFound
+	}
+
+	path := s.path(hash)
+	if thumbnail {
+		path += thumbnailSuffix
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return f, err
+}
+
+func (s *Store) download(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/61.0.3163.100 Safari/537.36")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("[images] unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxSize {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// write writes to a temporary file of the directory first, so a crash never leaves a truncated image behind,
+// and the concurrent writes of the same image do not share it.
+func write(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "[images] mkdir error")
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "[images] temp file error")
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "[images] write error")
+	}
+
+	return nil
+}
+
+// thumbnail scales the image down so its longest side is at most size, by averaging the source pixels.
+func thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, h*size/w
+		} else {
+			w, h = w*size/h, size
+		}
+	}
+
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := b.Min.Y + (y+1)*b.Dy()/h
+		if y1 == y0 {
+			y1++
+		}
+
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := b.Min.X + (x+1)*b.Dx()/w
+			if x1 == x0 {
+				x1++
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(bl / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+
+	return dst
+}
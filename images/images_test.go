@@ -0,0 +1,98 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestMirror(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for x := 0; x < 640; x++ {
+		img.Set(x, x%480, color.RGBA{R: 255, A: 255})
+	}
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a.png":
+			w.Write(buf.Bytes())
+		case "/error.png":
+			// An error page served as an image.
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("<html><body>Not found</body></html>"))
+		case "/truncated.png":
+			w.Write(buf.Bytes()[:64])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	s, err := NewStore(server.Client(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &model.Picture{ImageUrl: server.URL + "/a.png"}
+	if err := s.Mirror(p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Hash) != 64 || p.MimeType != "image/png" || p.Width != 640 || p.Height != 480 {
+		t.Errorf("got %+v", p)
+	}
+
+	f, err := s.Open(p.Hash, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumb, format, err := image.DecodeConfig(f)
+	f.Close()
+	if err != nil || format != "jpeg" || thumb.Width != thumbnailSize || thumb.Height != 240 {
+		t.Errorf("got %+v, %s, %v", thumb, format, err)
+	}
+
+	// The same image is stored once.
+	again := &model.Picture{ImageUrl: server.URL + "/a.png?size=large"}
+	if err := s.Mirror(again); err != nil || again.Hash != p.Hash {
+		t.Errorf("got %+v, %v", again, err)
+	}
+
+	for _, path := range []string{"/error.png", "/truncated.png"} {
+		if err := s.Mirror(&model.Picture{ImageUrl: server.URL + path}); err != ErrNotImage {
+			t.Errorf("%s: got %v, expected %v", path, err, ErrNotImage)
+		}
+	}
+	if err := s.Mirror(&model.Picture{ImageUrl: server.URL + "/missing.png"}); err == nil {
+		t.Error("got no error for a missing image")
+	}
+
+	// Only the image and its thumbnail are written, without a temporary file left.
+	var files []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files = append(files, filepath.Base(path))
+		}
+		return nil
+	})
+	if len(files) != 2 || !strings.HasPrefix(files[0], p.Hash) || !strings.HasPrefix(files[1], p.Hash) {
+		t.Errorf("got %v", files)
+	}
+
+	if _, err := s.Open("../../etc/passwd", false); err != ErrNotFound {
+		t.Errorf("got %v, expected %v", err, ErrNotFound)
+	}
+}
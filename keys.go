@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+const keysUsage = `usage: scrapenews keys <command>
+
+  list
+  create [-name name] [-scopes read,export,admin] [-rate-limit n] [-expires duration]
+  rotate <id>
+  revoke <id>
+  expire <id> <duration>
+`
+
+// keys manages the API keys from the command line, then exits.
+// The running server sees the changes within a minute.
+func keys(args []string) {
+	newsStore, err := getStore()
+	if err != nil {
+		log.Fatalf("failed to init data store: %s", err)
+	}
+
+	apiKeys, err := auth.NewKeys(newsStore, env.RateLimit)
+	if err != nil {
+		log.Fatalf("failed to init api keys: %s", err)
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "list":
+		list, err := apiKeys.List()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tRATE LIMIT\tEXPIRES\tREVOKED\tREQUESTS")
+		for _, v := range list {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%d\n", v.Id, v.Name, v.Prefix, strings.Join(v.Scopes, ","),
+				v.RateLimit, formatTime(v.Expires), formatTime(v.Revoked), v.Requests)
+		}
+		w.Flush()
+
+	case "create":
+		flags := flag.NewFlagSet("create", flag.ExitOnError)
+		name := flags.String("name", "", "the name of the key")
+		scopes := flags.String("scopes", model.ScopeRead, "the comma separated scopes: read, export or admin")
+		rateLimit := flags.Int("rate-limit", 0, "the requests per minute, 0 uses RATE_LIMIT")
+		expires := flags.Duration("expires", 0, "the duration until the key expires, e.g 720h, 0 never expires")
+		flags.Parse(args[1:])
+
+		var list []string
+		for _, s := range strings.Split(*scopes, ",") {
+			if !auth.ValidScope(s) {
+				log.Fatalf("unknown scope: %s", s)
+			}
+			list = append(list, s)
+		}
+
+		key, token, err := apiKeys.Create(*name, list, *rateLimit, expiry(*expires))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s %s\n", key.Id, token)
+
+	case "rotate":
+		if len(args) != 2 {
+			log.Fatal(keysUsage)
+		}
+
+		key, token, err := apiKeys.Rotate(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%s %s\n", key.Id, token)
+
+	case "revoke":
+		if len(args) != 2 {
+			log.Fatal(keysUsage)
+		}
+
+		if _, err := apiKeys.Revoke(args[1]); err != nil {
+			log.Fatal(err)
+		}
+
+	case "expire":
+		if len(args) != 3 {
+			log.Fatal(keysUsage)
+		}
+
+		d, err := time.ParseDuration(args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		key, err := apiKeys.Get(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		key.Expires = expiry(d)
+		if err := apiKeys.Save(key); err != nil {
+			log.Fatal(err)
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, keysUsage)
+		os.Exit(2)
+	}
+}
+
+// expiry returns the time after the duration, nil if the duration is 0.
+func expiry(d time.Duration) *time.Time {
+	if d == 0 {
+		return nil
+	}
+
+	t := time.Now().Add(d)
+	return &t
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
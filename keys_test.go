@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestRequiredScope(t *testing.T) {
+	tests := map[string]string{
+		"/get":                 model.ScopeRead,
+		"/stats/counts":        model.ScopeRead,
+		"/stream":              model.ScopeExport,
+		"/webhooks/w1":         model.ScopeAdmin,
+		"/admin/keys/k1":       model.ScopeAdmin,
+		"/administration/page": model.ScopeRead,
+	}
+
+	for path, expected := range tests {
+		if got := requiredScope(httptest.NewRequest("GET", path, nil)); got != expected {
+			t.Errorf("%s: got %q, expected %q", path, got, expected)
+		}
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	if expiry(0) != nil || formatTime(nil) != "-" {
+		t.Error("got an expiry without a duration")
+	}
+
+	if e := expiry(time.Hour); e == nil || e.Sub(time.Now()) <= 59*time.Minute || e.Sub(time.Now()) > time.Hour {
+		t.Errorf("got %v", e)
+	}
+}
+
+func TestGetAuthenticator(t *testing.T) {
+	defer func(saved Env) { env = saved }(env)
+
+	env = Env{AuthMode: "jwt", JwtSecret: "secret"}
+	if _, err := getAuthenticator(nil); err == nil {
+		t.Error("got an authenticator of the JWTs without an audience")
+	}
+
+	env.JwtAudience = "scrapenews"
+	a, err := getAuthenticator(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.JWT == nil || a.JWT.Audience != "scrapenews" || a.Keys != nil {
+		t.Errorf("got %+v", a)
+	}
+
+	env.AuthMode = "unknown"
+	if _, err := getAuthenticator(nil); err == nil {
+		t.Error("got an authenticator of an unknown mode")
+	}
+}
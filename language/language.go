@@ -0,0 +1,122 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	English = "en"
+	Malay   = "ms"
+)
+
+var englishStopwords = toSet(`
+a about after again against all also an and any are as at be because been before being between both but by
+can could did do does during each for from had has have he her his how i if in into is it its more most
+no not of on once only or other our out over said says she should so some such than that the their them
+then there these they this those through to too under until up very was we were what when where which
+while who will with would you year years mr mrs ms new one two three
+`)
+
+var malayStopwords = toSet(`
+ada adalah agar akan antara apabila atas atau bagi bagaimana bahawa baru beberapa begitu belum beliau
+berada berkata bersama boleh buat dalam dan dapat dari daripada dengan di dia hanya hari ini itu iaitu
+jika juga kali kami kata katanya ke kepada kerana ketika kini lagi lain lebih mahu maka manakala masih
+mereka menerusi menjadi menurut namun oleh pada para pula saat sahaja saja sama sebagai sebelum secara
+sedang sejak selepas semua sementara serta setiap sudah supaya tahun tanpa telah tersebut tetapi untuk
+yang turut berhubung jumlah mulai
+`)
+
+func toSet(words string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.Fields(words) {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+// Words splits the text into lower case words.
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// IsStopword returns true if the lower case word carries no topic, in either English or Malay.
+func IsStopword(w string) bool {
+	if _, exist := englishStopwords[w]; exist {
+		return true
+	}
+	_, exist := malayStopwords[w]
+	return exist
+}
+
+// Detect returns the language of the text, English or Malay, by counting their stopwords.
+// It returns an empty string if the text has too few of them.
+func Detect(text string) string {
+	var en, ms int
+	for _, w := range Words(text) {
+		if _, exist := englishStopwords[w]; exist {
+			en++
+		}
+		if _, exist := malayStopwords[w]; exist {
+			ms++
+		}
+	}
+
+	switch {
+	case en+ms < 3:
+		return ""
+	case en > ms:
+		return English
+	case ms > en:
+		return Malay
+	}
+
+	return ""
+}
+
+// Tokenize returns the stemmed words of the text that are not stopwords.
+func Tokenize(text string, lang string) []string {
+	var tokens []string
+	for _, w := range Words(text) {
+		if IsStopword(w) {
+			continue
+		}
+		tokens = append(tokens, Stem(w, lang))
+	}
+	return tokens
+}
+
+// Stem returns the stem of the lower case word. Words of an unknown language are returned as they are.
+func Stem(w string, lang string) string {
+	switch lang {
+	case English:
+		return stemEnglish(w)
+	case Malay:
+		return stemMalay(w)
+	}
+	return w
+}
+
+// Contains returns true if the text contains every word of the query that is not a stopword.
+// The words are stemmed in the language, so banjir matches kebanjiran and flood matches flooding.
+func Contains(text string, query string, lang string) bool {
+	terms := Tokenize(query, lang)
+	if len(terms) == 0 {
+		return false
+	}
+
+	tokens := make(map[string]struct{})
+	for _, t := range Tokenize(text, lang) {
+		tokens[t] = struct{}{}
+	}
+
+	for _, t := range terms {
+		if _, exist := tokens[t]; !exist {
+			return false
+		}
+	}
+
+	return true
+}
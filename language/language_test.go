@@ -0,0 +1,74 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Ribuan mangsa banjir di Kelantan telah dipindahkan ke pusat pemindahan sementara, kata polis.", Malay},
+		{"Thousands of flood victims in Kelantan have been moved to the relief centres, the police said.", English},
+		// Too few stopwords.
+		{"Banjir Kelantan", ""},
+		{"", ""},
+		// As many stopwords of each language.
+		{"the and of yang dan di", ""},
+		// The case is ignored.
+		{"THE POLICE SAID THAT THE SUSPECTS WERE ARRESTED", English},
+	}
+
+	for _, test := range tests {
+		if got := Detect(test.text); got != test.expected {
+			t.Errorf("%q: got %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("KUALA LUMPUR: RM2.5 bilion, kata Dr. Mahathir (PM) — 'esok'")
+	expected := []string{"kuala", "lumpur", "rm2", "5", "bilion", "kata", "dr", "mahathir", "pm", "esok"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Mangsa banjir telah dipindahkan ke pusat pemindahan", Malay)
+	expected := []string{"mangsa", "banjir", "pindah", "pusat", "pindah"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+
+	if got := Tokenize("The floods were flooding the roads", English); !reflect.DeepEqual(got, []string{"flood", "flood", "road"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		text, query, lang string
+		expected          bool
+	}{
+		{"Ribuan mangsa kebanjiran dipindahkan", "banjir", Malay, true},
+		{"Ribuan mangsa kebanjiran dipindahkan", "mangsa banjir", Malay, true},
+		{"Ribuan mangsa kebanjiran dipindahkan", "banjir johor", Malay, false},
+		{"The town was flooded overnight", "flooding", English, true},
+		{"The town was flooded overnight", "fire", English, false},
+		// The stopwords of the query are ignored, a query of only stopwords matches nothing.
+		{"The town was flooded overnight", "the flood of", English, true},
+		{"The town was flooded overnight", "the", English, false},
+		// The words are not stemmed without the language.
+		{"The town was flooded overnight", "flooding", "", false},
+		{"The town was flooded overnight", "FLOODED", "", true},
+	}
+
+	for _, test := range tests {
+		if got := Contains(test.text, test.query, test.lang); got != test.expected {
+			t.Errorf("%q in %q: got %v, expected %v", test.query, test.text, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The abbreviations ending with a period that do not end a sentence, in lower case without the period.
+var (
+	abbreviations = toSet(`
+no jan feb mar mac apr jun jul aug ogo sep sept oct okt nov dec dis sdn bhd st jln kg lt col gen capt
+`)
+	englishAbbreviations = toSet(`
+mr mrs ms dr prof sr jr inc ltd co corp dept est govt vs etc approx
+`)
+	malayAbbreviations = toSet(`
+dr prof tn pn en cik hj hjh dll dsb sdr sdri kpt bil
+`)
+)
+
+// Sentences splits the text into sentences, a new line always ends a sentence.
+// A period ends a sentence unless it follows an abbreviation of the language or an initial, e.g Dr. or A. Samad.
+func Sentences(text string, lang string) []string {
+	var sentences []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := []rune(paragraph)
+		start := 0
+
+		for i := 0; i < len(runes); i++ {
+			r := runes[i]
+			if r != '.' && r != '!' && r != '?' {
+				continue
+			}
+
+			end := i + 1
+			for end < len(runes) && strings.ContainsRune(`"'”’)`, runes[end]) {
+				end++
+			}
+
+			if end >= len(runes) || !unicode.IsSpace(runes[end]) {
+				continue
+			}
+
+			next := end
+			for next < len(runes) && unicode.IsSpace(runes[next]) {
+				next++
+			}
+			if next >= len(runes) || !(unicode.IsUpper(runes[next]) || unicode.IsDigit(runes[next]) || strings.ContainsRune(`"'“‘(`, runes[next])) {
+				continue
+			}
+
+			if r == '.' && isAbbreviation(lastWord(runes[start:i]), lang) {
+				continue
+			}
+
+			if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = end
+			i = end - 1
+		}
+
+		if s := strings.TrimSpace(string(runes[start:])); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+
+	return sentences
+}
+
+func lastWord(runes []rune) string {
+	i := len(runes)
+	for i > 0 && unicode.IsLetter(runes[i-1]) {
+		i--
+	}
+	return strings.ToLower(string(runes[i:]))
+}
+
+func isAbbreviation(w string, lang string) bool {
+	if len([]rune(w)) == 1 {
+		return true
+	}
+
+	if _, exist := abbreviations[w]; exist {
+		return true
+	}
+
+	switch lang {
+	case English:
+		_, exist := englishAbbreviations[w]
+		return exist
+	case Malay:
+		_, exist := malayAbbreviations[w]
+		return exist
+	}
+
+	_, english := englishAbbreviations[w]
+	_, malay := malayAbbreviations[w]
+	return english || malay
+}
@@ -0,0 +1,77 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSentences(t *testing.T) {
+	tests := []struct {
+		text     string
+		lang     string
+		expected []string
+	}{
+		{
+			"Sebuah kilang terbakar. Tiada kecederaan dilaporkan.",
+			Malay,
+			[]string{"Sebuah kilang terbakar.", "Tiada kecederaan dilaporkan."},
+		},
+		// The initials and the titles do not end a sentence.
+		{
+			"Sasterawan Negara Dr. A. Samad Said hadir. Beliau berucap.",
+			Malay,
+			[]string{"Sasterawan Negara Dr. A. Samad Said hadir.", "Beliau berucap."},
+		},
+		{
+			"Menurut Tn. Hj. Ahmad, kerja dll. akan siap. Pn. Siti dan Sdr. Ali bersetuju.",
+			Malay,
+			[]string{"Menurut Tn. Hj. Ahmad, kerja dll. akan siap.", "Pn. Siti dan Sdr. Ali bersetuju."},
+		},
+		{
+			"Syarikat itu, XYZ Sdn. Bhd. ditubuhkan pada Jan. 2018. Ia berpangkalan di Jln. Ampang.",
+			Malay,
+			[]string{"Syarikat itu, XYZ Sdn. Bhd. ditubuhkan pada Jan. 2018.", "Ia berpangkalan di Jln. Ampang."},
+		},
+		{
+			"Mr. Lim met Prof. Tan at 10 a.m. today. They talked.",
+			English,
+			[]string{"Mr. Lim met Prof. Tan at 10 a.m. today.", "They talked."},
+		},
+		// The abbreviations of the other language end a sentence.
+		{
+			"Ia dijual kepada Corp. Beliau setuju.",
+			Malay,
+			[]string{"Ia dijual kepada Corp.", "Beliau setuju."},
+		},
+		{
+			"Ia dijual kepada Corp. Beliau setuju.",
+			"",
+			[]string{"Ia dijual kepada Corp. Beliau setuju."},
+		},
+		// The quotes and the questions.
+		{
+			`"Kami tidak tahu." Katanya lagi. Adakah ia benar? "Ya!" jawabnya.`,
+			Malay,
+			[]string{`"Kami tidak tahu."`, "Katanya lagi.", "Adakah ia benar?", `"Ya!" jawabnya.`},
+		},
+		// A period not followed by a new sentence, e.g a number or a lower case word.
+		{
+			"Harga naik 2.5 peratus. 3 orang ditahan. Kata dia. lalu pergi",
+			Malay,
+			[]string{"Harga naik 2.5 peratus.", "3 orang ditahan.", "Kata dia. lalu pergi"},
+		},
+		// A new line always ends a sentence.
+		{
+			"Perenggan pertama\n\nPerenggan kedua. Ayat kedua",
+			Malay,
+			[]string{"Perenggan pertama", "Perenggan kedua.", "Ayat kedua"},
+		},
+		{"", Malay, nil},
+	}
+
+	for _, test := range tests {
+		if got := Sentences(test.text, test.lang); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%q: got %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
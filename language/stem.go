@@ -0,0 +1,147 @@
+package language
+
+import "strings"
+
+// The shortest stem left after removing an affix.
+const minStem = 3
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiou", b) != -1
+}
+
+// trimPrefix removes the prefix if the word is long enough to keep a stem.
+func trimPrefix(w, prefix string) (string, bool) {
+	if strings.HasPrefix(w, prefix) && len(w)-len(prefix) >= minStem {
+		return w[len(prefix):], true
+	}
+	return w, false
+}
+
+func trimSuffix(w, suffix string) (string, bool) {
+	if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= minStem {
+		return w[:len(w)-len(suffix)], true
+	}
+	return w, false
+}
+
+// stemEnglish removes the common inflection suffixes, e.g floods, flooded, flooding to flood.
+// The final e is removed too, so the inflections of charge share the same stem.
+func stemEnglish(w string) string {
+	switch {
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "sses"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(w, "ss"), strings.HasSuffix(w, "us"), strings.HasSuffix(w, "is"):
+		return w
+	}
+
+	for _, suffix := range []string{"ing", "ed", "ly", "s"} {
+		if stem, ok := trimSuffix(w, suffix); ok {
+			// running to run
+			if n := len(stem); suffix != "s" && n > 3 && stem[n-1] == stem[n-2] && !isVowel(stem[n-1]) && stem[n-1] != 'l' && stem[n-1] != 's' {
+				stem = stem[:n-1]
+			}
+			w = stem
+			break
+		}
+	}
+
+	// charge, charged and charging to charg
+	if stem, ok := trimSuffix(w, "e"); ok && len(stem) > minStem {
+		return stem
+	}
+
+	return w
+}
+
+// stemMalay strips the Malay particles, prefixes and suffixes,
+// e.g dipindahkan, pemindahan and memindahkan to pindah, kebanjiran to banjir.
+// It does not use a dictionary, so a few words are over-stemmed. The same word is always stemmed the same way.
+func stemMalay(w string) string {
+	if len(w) <= minStem+1 {
+		return w
+	}
+
+	// The -lah and -kah particles end a few roots too, e.g sekolah, so they need a longer stem.
+	for _, particle := range []string{"nya", "pun", "lah", "kah"} {
+		min := minStem
+		if particle == "lah" || particle == "kah" {
+			min = minStem + 2
+		}
+
+		if strings.HasSuffix(w, particle) && len(w)-len(particle) >= min {
+			w = w[:len(w)-len(particle)]
+			break
+		}
+	}
+
+	// The ke-an and per-an confixes, these prefixes are only stripped along with the suffix.
+	for _, prefix := range []string{"ke", "per"} {
+		if strings.HasPrefix(w, prefix) && strings.HasSuffix(w, "an") && len(w)-len(prefix)-2 >= minStem {
+			return w[len(prefix) : len(w)-2]
+		}
+	}
+
+	w = stripMalayPrefix(w)
+
+	// Many roots end with -an, e.g jalan, makan, so the root must be longer than the minimum stem.
+	// The -i suffix is left alone, it is more often part of the root, e.g cari, beri.
+	for _, suffix := range []string{"kan", "an"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) > minStem {
+			return w[:len(w)-len(suffix)]
+		}
+	}
+
+	return w
+}
+
+func stripMalayPrefix(w string) string {
+	// meN- and peN- change the first letter of the root
+	for _, p := range []string{"me", "pe"} {
+		if !strings.HasPrefix(w, p) {
+			continue
+		}
+		rest := w[len(p):]
+
+		switch {
+		case strings.HasPrefix(rest, "ny") && len(rest) > 2 && isVowel(rest[2]):
+			// menyapu to sapu
+			return "s" + rest[2:]
+		case strings.HasPrefix(rest, "ng") && len(rest) > 2:
+			// mengambil to ambil, menggali to gali
+			if stem, ok := trimPrefix(rest, "ng"); ok {
+				return stem
+			}
+		case strings.HasPrefix(rest, "m") && len(rest) > 1:
+			if isVowel(rest[1]) {
+				// memukul to pukul
+				return "p" + rest[1:]
+			}
+			// membaca to baca
+			if stem, ok := trimPrefix(rest, "m"); ok {
+				return stem
+			}
+		case strings.HasPrefix(rest, "n") && len(rest) > 1:
+			if isVowel(rest[1]) {
+				// menulis to tulis
+				return "t" + rest[1:]
+			}
+			// mendengar to dengar
+			if stem, ok := trimPrefix(rest, "n"); ok {
+				return stem
+			}
+		case len(rest) >= minStem && strings.IndexByte("lrwy", rest[0]) != -1:
+			// melawat to lawat
+			return rest
+		}
+	}
+
+	for _, prefix := range []string{"ber", "ter", "di"} {
+		if stem, ok := trimPrefix(w, prefix); ok {
+			return stem
+		}
+	}
+
+	return w
+}
@@ -0,0 +1,84 @@
+package language
+
+import "testing"
+
+func TestStemEnglish(t *testing.T) {
+	tests := map[string]string{
+		"flood":    "flood",
+		"floods":   "flood",
+		"flooded":  "flood",
+		"flooding": "flood",
+		"running":  "run",
+		"stopped":  "stop",
+		"falling":  "fall",
+		"missed":   "miss",
+		"charge":   "charg",
+		"charged":  "charg",
+		"charging": "charg",
+		"charges":  "charg",
+		"parties":  "party",
+		"classes":  "class",
+		"class":    "class",
+		"bus":      "bus",
+		"crisis":   "crisis",
+		"quickly":  "quick",
+		"red":      "red",
+		"sing":     "sing",
+		"police":   "polic",
+		"use":      "use",
+	}
+
+	for w, expected := range tests {
+		if got := Stem(w, English); got != expected {
+			t.Errorf("%s: got %s, expected %s", w, got, expected)
+		}
+	}
+}
+
+func TestStemMalay(t *testing.T) {
+	tests := map[string]string{
+		// The prefixes of the verbs.
+		"dipindahkan": "pindah",
+		"memindahkan": "pindah",
+		"menyapu":     "sapu",
+		"mengambil":   "ambil",
+		"menggali":    "gali",
+		"memukul":     "pukul",
+		"membaca":     "baca",
+		"menulis":     "tulis",
+		"mendengar":   "dengar",
+		"melawat":     "lawat",
+		"berjalan":    "jalan",
+		"terbakar":    "bakar",
+		"dibakar":     "bakar",
+		// The confixes.
+		"kebanjiran":  "banjir",
+		"pemindahan":  "pindah",
+		"perhubungan": "hubung",
+		// The particles.
+		"rumahnya":    "rumah",
+		"diapun":      "dia",
+		"berjalanlah": "jalan",
+		// -lah needs a longer stem.
+		"bacalah": "bacalah",
+		"apakah":  "apakah",
+		// The roots are left alone.
+		"sekolah": "sekolah",
+		"jalan":   "jalan",
+		"makan":   "makan",
+		"cari":    "cari",
+		"banjir":  "banjir",
+		"ibu":     "ibu",
+	}
+
+	for w, expected := range tests {
+		if got := Stem(w, Malay); got != expected {
+			t.Errorf("%s: got %s, expected %s", w, got, expected)
+		}
+	}
+
+	// The words of an unknown language are kept.
+	if got := Stem("dipindahkan", ""); got != "dipindahkan" {
+		t.Errorf("got %s", got)
+	}
+}
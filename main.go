@@ -6,37 +6,78 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/smtp"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/ahmadmuzakkir/scrapenews/store/boltdb"
 
+	"github.com/ahmadmuzakkir/scrapenews/alert"
 	"github.com/ahmadmuzakkir/scrapenews/api"
+	"github.com/ahmadmuzakkir/scrapenews/archive"
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/images"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/processor"
+	"github.com/ahmadmuzakkir/scrapenews/pubsub"
+	"github.com/ahmadmuzakkir/scrapenews/rpc"
 	"github.com/ahmadmuzakkir/scrapenews/store"
 	"github.com/ahmadmuzakkir/scrapenews/store/mysql"
 	"github.com/ahmadmuzakkir/scrapenews/store/sqlite"
+	"github.com/ahmadmuzakkir/scrapenews/story"
+	"github.com/ahmadmuzakkir/scrapenews/taxonomy"
+	"github.com/ahmadmuzakkir/scrapenews/trend"
+	"github.com/ahmadmuzakkir/scrapenews/webhook"
 	"github.com/getsentry/raven-go"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/robfig/cron"
+	"google.golang.org/grpc"
 )
 
-var authorized map[string]struct{}
 var env Env
 
 type Env struct {
 	SentryDsn     string   `envconfig:"SENTRY_DSN"`
-	Port          int      `envconfig:"PORT" required:"true"`
+	Port          int      `envconfig:"PORT"`
 	ApiKeys       []string `envconfig:"API_KEYS"`
 	Database      string   `envconfig:"DATABASE"`
 	MysqlAddress  string   `envconfig:"MYSQL_ADDRESS"`
 	MysqlUsername string   `envconfig:"MYSQL_USERNAME"`
 	MysqlPassword string   `envconfig:"MYSQL_PASSWORD"`
 	MysqlDatabase string   `envconfig:"MYSQL_DATABASE"`
+	ArchiveDir    string   `envconfig:"ARCHIVE_DIR"`
+	ImageDir      string   `envconfig:"IMAGE_DIR"`
+	SmtpAddress   string   `envconfig:"SMTP_ADDRESS"`
+	SmtpUsername  string   `envconfig:"SMTP_USERNAME"`
+	SmtpPassword  string   `envconfig:"SMTP_PASSWORD"`
+	SmtpFrom      string   `envconfig:"SMTP_FROM"`
+	// RateLimit is the number of requests per minute of the API keys without their own rate limit, and of the JWTs.
+	// They are not limited if it is 0.
+	RateLimit int `envconfig:"RATE_LIMIT" default:"600"`
+
+	// AuthMode accepts the API keys (keys), the JWTs (jwt) or both (both).
+	AuthMode string `envconfig:"AUTH_MODE" default:"keys"`
+	// The JWKS file of the keys verifying the JWTs, and the secret verifying the HS256 JWTs without a key id.
+	JwtJwks   string `envconfig:"JWT_JWKS"`
+	JwtSecret string `envconfig:"JWT_SECRET"`
+	// The audience the JWTs must have in their aud claim, required by the jwt and both modes.
+	JwtAudience string `envconfig:"JWT_AUDIENCE"`
+	// The claim of the scopes, and the map of its values to the scopes, e.g news.read:read,news.admin:admin
+	JwtScopeClaim string            `envconfig:"JWT_SCOPE_CLAIM" default:"scope"`
+	JwtScopes     map[string]string `envconfig:"JWT_SCOPES"`
+
+	// The limits of the GraphQL queries, see graphql.Schema.
+	GraphqlMaxDepth      int `envconfig:"GRAPHQL_MAX_DEPTH" default:"10"`
+	GraphqlMaxComplexity int `envconfig:"GRAPHQL_MAX_COMPLEXITY" default:"5000"`
+
+	// GrpcPort is the port of the gRPC server, it is not started if 0.
+	GrpcPort int `envconfig:"GRPC_PORT"`
 }
 
 func main() {
@@ -45,6 +86,21 @@ func main() {
 	env = Env{}
 	envconfig.Process("", &env)
 
+	if len(os.Args) > 1 && os.Args[1] == "reparse" {
+		reparse()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "canonicalise" {
+		canonicalise()
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		keys(os.Args[2:])
+		return
+	}
+
 	if env.Port == 0 {
 		panic("Port cannot be empty")
 	}
@@ -56,13 +112,37 @@ func main() {
 		raven.SetDSN(env.SentryDsn)
 	}
 
-	setAuthorization(env.ApiKeys)
-
 	newsStore, err := getStore()
 	if err != nil {
 		log.Fatalf("failed to init data store: %s", err)
 	}
 
+	apiKeys, err := auth.NewKeys(newsStore, env.RateLimit)
+	if err != nil {
+		log.Fatalf("failed to init api keys: %s", err)
+	}
+
+	imported, err := apiKeys.Import(env.ApiKeys)
+	if err != nil {
+		log.Fatalf("failed to import api keys: %s", err)
+	}
+	if imported > 0 {
+		log.Printf("Imported %d API keys from API_KEYS, they are stored and can be removed from the environment", imported)
+	}
+
+	authenticator, err := getAuthenticator(apiKeys)
+	if err != nil {
+		log.Fatalf("failed to init authentication: %s", err)
+	}
+	if authenticator.JWT == nil && !apiKeys.Enabled() {
+		log.Println("No API key, the API is open")
+	}
+
+	newsArchive, err := getArchive()
+	if err != nil {
+		log.Fatalf("failed to init archive: %s", err)
+	}
+
 	var netTransport = &http.Transport{
 		Dial: (&net.Dialer{
 			Timeout: 30 * time.Second,
@@ -74,29 +154,76 @@ func main() {
 		Timeout:   time.Second * 30,
 	}
 
-	newsRefresher := store.NewRefresher(hc, newsStore)
+	newsImages, err := getImages(hc)
+	if err != nil {
+		log.Fatalf("failed to init images: %s", err)
+	}
+
+	newsTaxonomy, err := getTaxonomy(newsStore)
+	if err != nil {
+		log.Fatalf("failed to init taxonomy: %s", err)
+	}
+
+	newsRefresher := store.NewRefresher(hc, newsStore, newsArchive, getPipelines(newsImages, newsTaxonomy))
+	newsDispatcher := webhook.NewDispatcher(newsStore, hc)
+	newsRefresher.AddListener(newsDispatcher)
+
+	newsAlerter := alert.NewAlerter(newsStore, getNotifiers(hc))
+	newsRefresher.AddListener(newsAlerter)
+
+	newsBroker := pubsub.NewBroker(1000)
+	newsRefresher.AddListener(newsBroker)
 	go newsRefresher.Refresh()
 
 	newsApi := api.NewNewsHandler(newsStore)
+	newsApi.Keys = apiKeys
+	newsApi.Taxonomy = newsTaxonomy
+	newsTrends := trend.NewJob(newsStore)
+	newsApi.Trends = newsTrends
 
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(recoverer)
 	r.Use(middleware.DefaultCompress)
-	r.Use(authorization)
+	r.Use(authenticator.Middleware(requiredScope))
 	r.Mount("/", newsApi.Routes())
+	if newsImages != nil {
+		r.Mount("/images", api.NewImageHandler(newsImages).Routes())
+	}
+	r.Mount("/stream", api.NewStreamHandler(newsBroker).Routes())
+	r.Mount("/graphql", api.NewGraphQLHandler(newsStore, env.GraphqlMaxDepth, env.GraphqlMaxComplexity).Routes())
 
 	httpServer := &http.Server{Addr: ":" + strconv.Itoa(env.Port), Handler: r}
 
 	go func() {
-		// Run the Http server
-		log.Fatal(httpServer.ListenAndServe())
+		// Run the Http server, it is closed by the shutdown
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
 	}()
 
+	var grpcServer *grpc.Server
+	if env.GrpcPort != 0 {
+		log.Println("gRPC port: ", env.GrpcPort)
+
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(env.GrpcPort))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		grpcServer = rpc.NewServer(rpc.NewNewsService(newsStore, newsBroker), authenticator)
+		go func() {
+			// Run the gRPC server, it is stopped by the shutdown
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	var mycron *cron.Cron
 	raven.CapturePanicAndWait(func() {
-		mycron = startCron(newsRefresher)
+		mycron = startCron(newsRefresher, story.NewJob(newsStore), newsTrends, newsAlerter, newsDispatcher, apiKeys)
 	}, map[string]string{"module": "cron"})
 
 	shutdownSignal := make(chan os.Signal, 1)
@@ -105,6 +232,10 @@ func main() {
 	select {
 	case <-shutdownSignal:
 	}
+	if mycron != nil {
+		mycron.Stop()
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
@@ -112,49 +243,78 @@ func main() {
 	if err != nil {
 		log.Println("Error shutting down HTTP server, ", err)
 	}
-}
 
-func recoverer(next http.Handler) http.Handler {
-	fn := raven.RecoveryHandler(next.ServeHTTP)
+	if grpcServer != nil {
+		stopGrpc(ctx, grpcServer)
+	}
 
-	return http.HandlerFunc(fn)
+	if err := apiKeys.Sync(); err != nil {
+		log.Println("Error saving the API keys usage, ", err)
+	}
 }
 
-func setAuthorization(apiKeys []string) {
-	if apiKeys == nil {
-		authorized = nil
-		return
-	}
+// stopGrpc waits for the calls to end, the calls left when the context is done are cancelled, e.g WatchNews.
+func stopGrpc(ctx context.Context, s *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
 
-	authorized = make(map[string]struct{})
-	for _, v := range apiKeys {
-		log.Println("Api Key: ", v)
-		authorized[v] = struct{}{}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
 	}
 }
 
-func authorization(next http.Handler) http.Handler {
-	fn := func(w http.ResponseWriter, r *http.Request) {
-		auth := r.Header.Get("Authorization")
+func recoverer(next http.Handler) http.Handler {
+	fn := raven.RecoveryHandler(next.ServeHTTP)
+
+	return http.HandlerFunc(fn)
+}
 
-		if authorized == nil {
-			next.ServeHTTP(w, r)
-			return
+// getAuthenticator returns the authenticator of the AuthMode.
+func getAuthenticator(apiKeys *auth.Keys) (*auth.Authenticator, error) {
+	switch env.AuthMode {
+	case "keys":
+		return auth.NewAuthenticator(apiKeys, nil, env.RateLimit), nil
+	case "jwt", "both":
+		// The tokens issued for another service are accepted without an audience.
+		if env.JwtAudience == "" {
+			return nil, fmt.Errorf("JWT_AUDIENCE is required by the auth mode %s", env.AuthMode)
 		}
 
-		_, exist := authorized[auth]
-		if auth != "" && exist {
-			next.ServeHTTP(w, r)
-			return
+		jwt, err := auth.NewJWT(env.JwtJwks, env.JwtSecret)
+		if err != nil {
+			return nil, err
 		}
 
-		w.WriteHeader(http.StatusUnauthorized)
+		jwt.Audience = env.JwtAudience
+		jwt.ScopeClaim = env.JwtScopeClaim
+		jwt.Scopes = env.JwtScopes
+
+		if env.AuthMode == "jwt" {
+			return auth.NewAuthenticator(nil, jwt, env.RateLimit), nil
+		}
+		return auth.NewAuthenticator(apiKeys, jwt, env.RateLimit), nil
 	}
 
-	return http.HandlerFunc(fn)
+	return nil, fmt.Errorf("unknown auth mode: %s", env.AuthMode)
 }
 
-func startCron(refresher *store.Refresher) *cron.Cron {
+// requiredScope returns the scope of the API key required by the request.
+func requiredScope(r *http.Request) string {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/admin/"), strings.HasPrefix(r.URL.Path, "/webhooks"):
+		return model.ScopeAdmin
+	case strings.HasPrefix(r.URL.Path, "/stream"):
+		return model.ScopeExport
+	}
+	return model.ScopeRead
+}
+
+func startCron(refresher *store.Refresher, stories *story.Job, trends *trend.Job, alerter *alert.Alerter, dispatcher *webhook.Dispatcher, apiKeys *auth.Keys) *cron.Cron {
 	loc, err := time.LoadLocation("Asia/Kuala_Lumpur")
 	if err != nil {
 		log.Panic(err)
@@ -189,6 +349,47 @@ func startCron(refresher *store.Refresher) *cron.Cron {
 		go refresher.Refresh()
 	})
 
+	// Regroup the stories after the refreshes
+	c.AddFunc("0 30 * * * *", func() {
+		if err := stories.Run(); err != nil {
+			log.Println("stories: ", err)
+		}
+	})
+
+	// The trends change as the news are published, not only when they are scraped
+	c.AddFunc("0 */15 * * * *", func() {
+		if err := trends.Run(); err != nil {
+			log.Println("trends: ", err)
+		}
+	})
+
+	// The digests, the immediate alerts that failed are retried along with the hourly ones
+	c.AddFunc("0 0 * * * *", func() {
+		if err := alerter.Deliver(model.Immediate, model.Hourly); err != nil {
+			log.Println("alerts: ", err)
+		}
+	})
+
+	c.AddFunc("0 0 8 * * *", func() {
+		if err := alerter.Deliver(model.Daily); err != nil {
+			log.Println("alerts: ", err)
+		}
+	})
+
+	// The failed webhook deliveries, and the ones left while the queue was full
+	c.AddFunc("30 * * * * *", func() {
+		if err := dispatcher.Retry(); err != nil {
+			log.Println("webhooks: ", err)
+		}
+	})
+
+	// Save the usage of the API keys, and see the keys changed by the command line
+	c.AddFunc("0 * * * * *", func() {
+		if err := apiKeys.Sync(); err != nil {
+			log.Println("api keys: ", err)
+		}
+	})
+
 	c.Start()
 
 	return c
@@ -206,3 +407,172 @@ func getStore() (store.NewsStore, error) {
 
 	return nil, fmt.Errorf("unknown store type: %s", env.Database)
 }
+
+// getArchive returns nil if the archive is not configured.
+func getArchive() (*archive.Archive, error) {
+	if env.ArchiveDir == "" {
+		return nil, nil
+	}
+
+	return archive.New(env.ArchiveDir)
+}
+
+// getImages returns nil if the image mirroring is not configured.
+func getImages(hc *http.Client) (*images.Store, error) {
+	if env.ImageDir == "" {
+		return nil, nil
+	}
+
+	return images.NewStore(hc, env.ImageDir)
+}
+
+// getTaxonomy returns the taxonomy of the topics of the store, the default topics are saved in a store without topics.
+func getTaxonomy(newsStore store.NewsStore) (*taxonomy.Taxonomy, error) {
+	topics, err := newsStore.GetTopics()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(topics) == 0 {
+		now := time.Now()
+		for _, v := range taxonomy.Defaults {
+			topic := *v
+			topic.Created = now
+			topic.Updated = now
+			if err := newsStore.SaveTopic(&topic); err != nil {
+				return nil, err
+			}
+			topics = append(topics, &topic)
+		}
+	}
+
+	return taxonomy.New(topics), nil
+}
+
+// getNotifiers returns the notifiers of the alerts, the emails are only sent if the SMTP server is configured.
+func getNotifiers(hc *http.Client) map[string]alert.Notifier {
+	notifiers := map[string]alert.Notifier{
+		"slack": &alert.Slack{HttpClient: hc},
+	}
+
+	if env.SmtpAddress != "" {
+		var auth smtp.Auth
+		if env.SmtpUsername != "" {
+			host, _, _ := net.SplitHostPort(env.SmtpAddress)
+			auth = smtp.PlainAuth("", env.SmtpUsername, env.SmtpPassword, host)
+		}
+
+		notifiers["email"] = &alert.SMTP{Addr: env.SmtpAddress, Auth: auth, From: env.SmtpFrom}
+	}
+
+	return notifiers
+}
+
+// getPipelines returns the processing pipeline of each newspaper.
+// The pictures are mirrored only if images is not nil.
+func getPipelines(images *images.Store, taxonomy *taxonomy.Taxonomy) map[string]*processor.Pipeline {
+	stages := func(readAlso ...string) []processor.Processor {
+		stages := []processor.Processor{
+			processor.Whitespace{},
+			processor.ReadAlso{Prefixes: readAlso},
+			processor.Language{},
+			processor.Entities{},
+			processor.Html{},
+			processor.Topics{Taxonomy: taxonomy},
+			processor.Summary{},
+			processor.MinLength{Min: 200},
+		}
+
+		// Mirror last, so the pictures of the dropped news are not downloaded.
+		if images != nil {
+			stages = append(stages, processor.Images{Store: images})
+		}
+		return stages
+	}
+
+	return map[string]*processor.Pipeline{
+		model.NstId:     processor.NewPipeline(stages("Read more", "Read also", "Also read", "Read:")...),
+		model.BharianId: processor.NewPipeline(stages("Baca juga", "Baca:", "Berita berkaitan")...),
+		model.UtusanId:  processor.NewPipeline(stages("Baca juga", "Baca:", "Artikel berkaitan")...),
+	}
+}
+
+// reparse re-runs the extractors over the archived pages, then exits.
+func reparse() {
+	newsStore, err := getStore()
+	if err != nil {
+		log.Fatalf("failed to init data store: %s", err)
+	}
+
+	newsArchive, err := getArchive()
+	if err != nil {
+		log.Fatalf("failed to init archive: %s", err)
+	}
+
+	newsTaxonomy, err := getTaxonomy(newsStore)
+	if err != nil {
+		log.Fatalf("failed to init taxonomy: %s", err)
+	}
+
+	// The pictures added by the current extractors are mirrored too.
+	newsImages, err := getImages(http.DefaultClient)
+	if err != nil {
+		log.Fatalf("failed to init images: %s", err)
+	}
+
+	count, err := store.NewRefresher(http.DefaultClient, newsStore, newsArchive, getPipelines(newsImages, newsTaxonomy)).Reparse()
+	if err != nil {
+		log.Fatalf("reparse failed after %d news: %s", count, err)
+	}
+
+	log.Println("Reparsed: ", count)
+}
+
+// canonicalise moves the news stored before their ids were generated from their canonical url, and merges their duplicates,
+// then exits. The archived pages are moved along with the news.
+func canonicalise() {
+	newsStore, err := getStore()
+	if err != nil {
+		log.Fatalf("failed to init data store: %s", err)
+	}
+
+	newsArchive, err := getArchive()
+	if err != nil {
+		log.Fatalf("failed to init archive: %s", err)
+	}
+
+	list, err := newsStore.GetAll(store.Filter{Fields: store.MergeFields})
+	if err != nil {
+		log.Fatalf("failed to get news: %s", err)
+	}
+
+	merges := store.Merges(list)
+	if err := newsStore.MergeNews(merges); err != nil {
+		log.Fatalf("failed to merge news: %s", err)
+	}
+
+	var moved, removed int
+	for _, m := range merges {
+		removed += len(m.Duplicates)
+		if m.From == "" {
+			continue
+		}
+		moved++
+
+		if newsArchive == nil {
+			continue
+		}
+		body, archived, err := newsArchive.Get(m.From)
+		if err == archive.ErrNotFound {
+			continue
+		}
+		if err == nil {
+			err = newsArchive.Put(m.Id, body, archived)
+		}
+		if err != nil {
+			log.Println("archive error: ", err)
+		}
+	}
+
+	log.Printf("Canonicalised %d news: %d moved, %d duplicates removed", len(list), moved, removed)
+}
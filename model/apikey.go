@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// The scopes of the API keys.
+const (
+	// ScopeRead reads the news, the stories and the saved searches of the key.
+	ScopeRead = "read"
+	// ScopeExport streams the news out, e.g the server-sent events.
+	ScopeExport = "export"
+	// ScopeAdmin manages the API keys and the webhooks, it grants every scope.
+	ScopeAdmin = "admin"
+)
+
+// ApiKey authorizes the requests to the API, see package auth.
+type ApiKey struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// Hash is the SHA-256 of the key, the key itself is only returned when it is created or rotated.
+	Hash string `json:"-"`
+	// Prefix is the start of the key, to tell the keys apart.
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// RateLimit is the number of requests per minute, 0 uses the default rate limit.
+	RateLimit int `json:"rate_limit"`
+
+	// Expires is nil if the key does not expire.
+	Expires *time.Time `json:"expires"`
+	// Revoked is nil until the key is revoked.
+	Revoked *time.Time `json:"revoked"`
+	Created time.Time  `json:"created"`
+
+	// The usage counters, they are saved periodically.
+	Requests int64      `json:"requests"`
+	LastUsed *time.Time `json:"last_used"`
+}
+
+// Active returns true if the key is neither revoked nor expired.
+func (k *ApiKey) Active(now time.Time) bool {
+	return k.Revoked == nil && (k.Expires == nil || now.Before(*k.Expires))
+}
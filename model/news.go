@@ -3,7 +3,10 @@ package model
 import (
 	"crypto/sha1"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/canonical"
 )
 
 type News struct {
@@ -17,6 +20,37 @@ type News struct {
 	Tags     []string   `json:"tags"`
 	Url      string     `json:"url"`
 	Source   NewsSource `json:"source"`
+
+	// The paragraphs of the article body without the boilerplate, the content is the paragraphs separated by a new line.
+	// See package content.
+	Paragraphs []string `json:"paragraphs"`
+	// The sanitised HTML of the article body, only the text formatting and the links are kept.
+	Html string `json:"html,omitempty"`
+
+	// The first paragraph, and the most central sentences of the content, see package summary.
+	Lead    string `json:"lead"`
+	Summary string `json:"summary"`
+
+	// Language is the ISO 639-1 code detected from the content, see package language.
+	Language string `json:"language"`
+
+	// The id of the first news of its near-duplicates, see store.Refresher.
+	ClusterId string `json:"cluster_id"`
+
+	// The people, organisations and places mentioned, see package entity.
+	Entities []*Entity `json:"entities"`
+
+	// The ids of the topics of the tags of the news and of its source, see package taxonomy.
+	Topics []string `json:"topics"`
+
+	// The metadata the page declares, its canonical url, description, section and last modification, see provider.Metadata.
+	CanonicalUrl string     `json:"canonical_url"`
+	Description  string     `json:"description"`
+	Section      string     `json:"section"`
+	Modified     *time.Time `json:"modified"`
+
+	// The notes of the pipeline stages, see package processor.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 func (n *News) ToString() string {
@@ -39,17 +73,49 @@ func (n *News) ToString() string {
 	)
 }
 
+// SetParagraphs sets the paragraphs and the content.
+func (n *News) SetParagraphs(paragraphs []string) {
+	n.Paragraphs = paragraphs
+	n.Content = strings.Join(paragraphs, "\n")
+}
+
+// SetContent sets the content and splits it into paragraphs, one per line.
+func (n *News) SetContent(content string) {
+	var paragraphs []string
+	for _, p := range strings.Split(content, "\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	n.SetParagraphs(paragraphs)
+}
+
+// GenerateId sets the id to the hash of the canonical url, so the variants of the url of an article share its id.
+// See package canonical.
 func (n *News) GenerateId() {
 	hash := sha1.New()
 
-	hash.Write([]byte(n.Url))
+	hash.Write([]byte(canonical.Url(n.Url)))
 
 	n.Id = fmt.Sprintf("%x", hash.Sum(nil))
 }
 
+type Entity struct {
+	Id    string `json:"id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
 type Picture struct {
 	ImageUrl string `json:"url"`
 	Caption  string `json:"caption"`
+
+	// Set when the picture is mirrored locally, see package images.
+	Hash     string `json:"hash,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
 }
 
 func (p *Picture) ToString() string {
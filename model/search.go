@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// The frequencies of the alerts of a saved search.
+const (
+	Immediate = "immediate"
+	Hourly    = "hourly"
+	Daily     = "daily"
+)
+
+// SavedSearch alerts its owner of the new news matching it, see package alert.
+type SavedSearch struct {
+	Id string `json:"id"`
+	// Owner is the id of the client that saved it, the id of its API key or jwt:<subject>, see auth.Principal.
+	Owner string `json:"-"`
+	Name  string `json:"name"`
+
+	// A news containing all the words of the query matches, e.g 1MDB or banjir.
+	Query string `json:"query"`
+	// The newspaper ids, e.g bharian. A news of any of them matches, the empty list matches every newspaper.
+	Newspapers []string `json:"newspapers"`
+	// The ISO 639-1 code of the language, the empty language matches every news.
+	Language string `json:"language"`
+
+	// Frequency is Immediate, Hourly or Daily. The hourly and daily alerts are delivered as a digest.
+	Frequency string `json:"frequency"`
+	// Notifier is the name of the notifier delivering the alerts, e.g email or slack. Target is its address or url.
+	Notifier string `json:"notifier"`
+	Target   string `json:"target"`
+
+	Created time.Time `json:"created"`
+}
+
+// Alert is a news matching a saved search.
+type Alert struct {
+	Id        string    `json:"id"`
+	SearchId  string    `json:"search_id"`
+	NewsId    string    `json:"news_id"`
+	Title     string    `json:"title"`
+	Url       string    `json:"url"`
+	Newspaper string    `json:"newspaper"`
+	Datetime  time.Time `json:"datetime"`
+	Created   time.Time `json:"created"`
+	// Delivered is nil until the alert is notified.
+	Delivered *time.Time `json:"delivered"`
+}
+
+// NewAlert returns the alert of the news, its id is the same for the same search and news.
+func NewAlert(search *SavedSearch, n *News) *Alert {
+	return &Alert{
+		Id:        hashString(search.Id + "/" + n.Id),
+		SearchId:  search.Id,
+		NewsId:    n.Id,
+		Title:     n.Title,
+		Url:       n.Url,
+		Newspaper: n.Source.NewspaperName,
+		Datetime:  n.Datetime,
+		Created:   time.Now(),
+	}
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Story groups the news covering the same event, across the newspapers and over several days.
+type Story struct {
+	Id string `json:"id"`
+	// Label is the title of the news closest to the centre of the story.
+	Label         string    `json:"label"`
+	Keywords      []string  `json:"keywords"`
+	FirstDatetime time.Time `json:"first_datetime"`
+	LastDatetime  time.Time `json:"last_datetime"`
+	Newspapers    []string  `json:"newspapers"`
+	NewsIds       []string  `json:"news_ids"`
+	News          []*News   `json:"news,omitempty"`
+}
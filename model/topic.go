@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Topic is a canonical tag, the tags of the newspapers meaning the same are mapped to it, see package taxonomy.
+type Topic struct {
+	// Id is a lower case English slug, e.g crime or foreign-affairs.
+	Id string `json:"id"`
+	// Names are the names of the topic by language, e.g Crime in en and Jenayah in ms.
+	Names map[string]string `json:"names"`
+	// Aliases are the other tags of the newspapers meaning the topic, e.g Kes and Crime & Courts for crime.
+	Aliases []string `json:"aliases"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Webhook is a subscription to the new news matching its filters, see package webhook.
+// The empty filters match every news.
+type Webhook struct {
+	Id  string `json:"id"`
+	Url string `json:"url"`
+	// Secret signs the deliveries, it is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+
+	// The newspaper ids, e.g bharian. A news of any of them matches.
+	Newspapers []string `json:"newspapers"`
+	// The tags of the news or of its source, e.g crime. A news with any of them matches.
+	Tags []string `json:"tags"`
+	// A news containing any of the keywords matches, a keyword of several words must match all of them.
+	Keywords []string `json:"keywords"`
+
+	Created time.Time `json:"created"`
+}
+
+// Delivery is an attempt to deliver news to a webhook.
+type Delivery struct {
+	Id        string   `json:"id"`
+	WebhookId string   `json:"webhook_id"`
+	NewsIds   []string `json:"news_ids"`
+	// Attempt is 0 if the delivery was not attempted yet, while the deliveries were queued.
+	Attempt  int       `json:"attempt"`
+	Datetime time.Time `json:"datetime"`
+	// The status code of the response, 0 if there was no response.
+	StatusCode int `json:"status_code"`
+	// The error of the attempt, empty if the delivery succeeded.
+	Error string `json:"error"`
+	// The time to respond, in milliseconds.
+	Duration int64 `json:"duration"`
+	// Retry is the time of the next attempt of a failed delivery, nil if there is none or it was made.
+	Retry *time.Time `json:"retry,omitempty"`
+}
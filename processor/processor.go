@@ -0,0 +1,159 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// Processor is a stage of the pipeline between the providers and the store.
+// It can modify the news, annotate it, or drop it by returning Drop.
+type Processor interface {
+	Name() string
+	Process(n *model.News) error
+}
+
+// DropError drops the news from the pipeline, it is not stored.
+type DropError struct {
+	Reason string
+}
+
+func (e *DropError) Error() string {
+	return "dropped: " + e.Reason
+}
+
+func Drop(reason string) error {
+	return &DropError{Reason: reason}
+}
+
+// Annotate records a note about the news, e.g by which stage and why it was modified.
+func Annotate(n *model.News, key, value string) {
+	if n.Annotations == nil {
+		n.Annotations = make(map[string]string)
+	}
+	n.Annotations[key] = value
+}
+
+type StageStats struct {
+	Processed int           `json:"processed"`
+	Dropped   int           `json:"dropped"`
+	Errors    int           `json:"errors"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Pipeline runs the news through its stages in order. It records the time spent in each stage and why the news were dropped.
+type Pipeline struct {
+	stages []Processor
+
+	mu    sync.Mutex
+	stats map[string]*StageStats
+	drops map[string]int
+}
+
+func NewPipeline(stages ...Processor) *Pipeline {
+	return &Pipeline{
+		stages: stages,
+		stats:  make(map[string]*StageStats),
+		drops:  make(map[string]int),
+	}
+}
+
+// Run returns the news that are not dropped. A stage failing on a news is logged, the news is kept.
+func (p *Pipeline) Run(news []*model.News) []*model.News {
+	if p == nil {
+		return news
+	}
+
+	var result = make([]*model.News, 0, len(news))
+
+	for _, n := range news {
+		if p.process(n) {
+			result = append(result, n)
+		}
+	}
+
+	return result
+}
+
+func (p *Pipeline) process(n *model.News) bool {
+	for _, stage := range p.stages {
+		start := time.Now()
+		err := stage.Process(n)
+		elapsed := time.Since(start)
+
+		p.mu.Lock()
+		stats := p.stats[stage.Name()]
+		if stats == nil {
+			stats = &StageStats{}
+			p.stats[stage.Name()] = stats
+		}
+		stats.Processed++
+		stats.Duration += elapsed
+
+		drop, isDrop := err.(*DropError)
+		if isDrop {
+			stats.Dropped++
+			p.drops[stage.Name()+": "+drop.Reason]++
+		} else if err != nil {
+			stats.Errors++
+		}
+		p.mu.Unlock()
+
+		if isDrop {
+			log.Printf("pipeline: %s %s dropped: %s", stage.Name(), n.Url, drop.Reason)
+			return false
+		}
+
+		if err != nil {
+			log.Printf("pipeline: %s %s error: %s", stage.Name(), n.Url, err)
+		}
+	}
+
+	return true
+}
+
+// Stats returns the stats of every stage, and the number of news dropped for each reason.
+func (p *Pipeline) Stats() (map[string]StageStats, map[string]int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make(map[string]StageStats, len(p.stats))
+	for k, v := range p.stats {
+		stats[k] = *v
+	}
+
+	drops := make(map[string]int, len(p.drops))
+	for k, v := range p.drops {
+		drops[k] = v
+	}
+
+	return stats, drops
+}
+
+// String summarises the stats, in the order of the stages.
+func (p *Pipeline) String() string {
+	stats, drops := p.Stats()
+
+	var parts []string
+	for _, stage := range p.stages {
+		s := stats[stage.Name()]
+		parts = append(parts, fmt.Sprintf("%s %d in %s, %d dropped, %d errors", stage.Name(), s.Processed, s.Duration, s.Dropped, s.Errors))
+	}
+
+	var reasons []string
+	for k, v := range drops {
+		reasons = append(reasons, fmt.Sprintf("%s (%d)", k, v))
+	}
+	sort.Strings(reasons)
+
+	if len(reasons) > 0 {
+		parts = append(parts, "dropped: "+strings.Join(reasons, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
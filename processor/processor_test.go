@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// stage runs its function, and records the ids of the news it sees.
+type stage struct {
+	name    string
+	process func(n *model.News) error
+	seen    []string
+}
+
+func (s *stage) Name() string {
+	return s.name
+}
+
+func (s *stage) Process(n *model.News) error {
+	s.seen = append(s.seen, n.Id)
+	return s.process(n)
+}
+
+func TestPipeline(t *testing.T) {
+	filter := &stage{name: "filter", process: func(n *model.News) error {
+		if n.Title == "" {
+			return Drop("no title")
+		}
+		return nil
+	}}
+	failing := &stage{name: "failing", process: func(n *model.News) error {
+		if n.Id == "b" {
+			return errors.New("failed")
+		}
+		return nil
+	}}
+	last := &stage{name: "last", process: func(n *model.News) error { return nil }}
+
+	p := NewPipeline(filter, failing, last)
+	result := p.Run([]*model.News{{Id: "a", Title: "A"}, {Id: "b", Title: "B"}, {Id: "c"}, {Id: "d"}})
+
+	// The news dropped is not stored, nor seen by the later stages. The news a stage failed on is kept.
+	var ids []string
+	for _, n := range result {
+		ids = append(ids, n.Id)
+	}
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got %v, expected %v", ids, expected)
+	}
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(failing.seen, expected) || !reflect.DeepEqual(last.seen, expected) {
+		t.Errorf("got %v and %v, expected %v", failing.seen, last.seen, expected)
+	}
+
+	stats, drops := p.Stats()
+	expected := map[string]StageStats{
+		"filter":  {Processed: 4, Dropped: 2},
+		"failing": {Processed: 2, Errors: 1},
+		"last":    {Processed: 2},
+	}
+	for name, s := range stats {
+		s.Duration = 0
+		if s != expected[name] {
+			t.Errorf("%s: got %+v, expected %+v", name, s, expected[name])
+		}
+	}
+	if len(stats) != len(expected) {
+		t.Errorf("got %v", stats)
+	}
+	if expected := map[string]int{"filter: no title": 2}; !reflect.DeepEqual(drops, expected) {
+		t.Errorf("got %v, expected %v", drops, expected)
+	}
+
+	// A nil pipeline keeps every news.
+	var none *Pipeline
+	if list := none.Run(result); len(list) != 2 {
+		t.Errorf("got %v", list)
+	}
+}
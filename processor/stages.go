@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/content"
+	"github.com/ahmadmuzakkir/scrapenews/entity"
+	"github.com/ahmadmuzakkir/scrapenews/images"
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/summary"
+	"github.com/ahmadmuzakkir/scrapenews/taxonomy"
+)
+
+// Whitespace collapses the spaces and removes the empty lines.
+type Whitespace struct{}
+
+func (Whitespace) Name() string {
+	return "whitespace"
+}
+
+func (Whitespace) Process(n *model.News) error {
+	n.Title = content.Normalise(n.Title)
+	n.Author = content.Normalise(n.Author)
+	n.Location = content.Normalise(n.Location)
+
+	var paragraphs []string
+	for _, p := range strings.Split(n.Content, "\n") {
+		if p = content.Normalise(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	n.SetParagraphs(paragraphs)
+
+	for _, p := range n.Pictures {
+		p.Caption = content.Normalise(p.Caption)
+	}
+
+	return nil
+}
+
+// ReadAlso removes the lines linking to the other news, e.g Baca juga: ...
+type ReadAlso struct {
+	// The prefixes are matched case-insensitively.
+	Prefixes []string
+}
+
+func (ReadAlso) Name() string {
+	return "readalso"
+}
+
+func (r ReadAlso) Process(n *model.News) error {
+	var lines []string
+	var removed int
+
+	for _, line := range strings.Split(n.Content, "\n") {
+		if r.match(line) {
+			removed++
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if removed > 0 {
+		n.SetParagraphs(lines)
+		Annotate(n, "readalso.removed", strconv.Itoa(removed))
+	}
+
+	return nil
+}
+
+func (r ReadAlso) match(line string) bool {
+	line = strings.ToLower(strings.TrimSpace(line))
+	for _, prefix := range r.Prefixes {
+		if strings.HasPrefix(line, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Language detects the language of the news.
+type Language struct{}
+
+func (Language) Name() string {
+	return "language"
+}
+
+func (Language) Process(n *model.News) error {
+	n.Language = language.Detect(n.Title + "\n" + n.Content)
+	return nil
+}
+
+// Entities tags the entities of the news and normalises its location.
+type Entities struct{}
+
+func (Entities) Name() string {
+	return "entities"
+}
+
+func (Entities) Process(n *model.News) error {
+	entity.Tag(n)
+	return nil
+}
+
+// Html rewrites the HTML of the news from its paragraphs, after the stages removing or cutting them, see content.Html.
+type Html struct{}
+
+func (Html) Name() string {
+	return "html"
+}
+
+func (Html) Process(n *model.News) error {
+	if n.Html != "" {
+		n.Html = content.Html(n.Html, n.Paragraphs)
+	}
+	return nil
+}
+
+// Topics maps the tags of the news and of its source to the topics of the taxonomy.
+type Topics struct {
+	Taxonomy *taxonomy.Taxonomy
+}
+
+func (Topics) Name() string {
+	return "topics"
+}
+
+func (t Topics) Process(n *model.News) error {
+	n.Topics = t.Taxonomy.Topics(n)
+	return nil
+}
+
+// Summary sets the lead and the summary of the news.
+type Summary struct{}
+
+func (Summary) Name() string {
+	return "summary"
+}
+
+func (Summary) Process(n *model.News) error {
+	summary.Summarise(n)
+	return nil
+}
+
+// MinLength drops the news with too little content, usually a video or a gallery page.
+type MinLength struct {
+	// The minimum number of characters of the content.
+	Min int
+}
+
+func (MinLength) Name() string {
+	return "minlength"
+}
+
+func (m MinLength) Process(n *model.News) error {
+	if len([]rune(n.Content)) < m.Min {
+		return Drop(fmt.Sprintf("content shorter than %d", m.Min))
+	}
+	return nil
+}
+
+// Images mirrors the pictures of the news, see package images.
+type Images struct {
+	Store *images.Store
+}
+
+func (Images) Name() string {
+	return "images"
+}
+
+func (i Images) Process(n *model.News) error {
+	var failed int
+	for _, p := range n.Pictures {
+		if err := i.Store.Mirror(p); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to mirror %d pictures", failed)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestHtml(t *testing.T) {
+	n := &model.News{
+		Html: "<p>PETALING JAYA: Sebuah kilang <b>terbakar</b>.</p>\n<p>Baca juga: Bomba kekurangan anggota</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada kecederaan.</p>\n",
+	}
+	n.SetParagraphs([]string{"PETALING JAYA: Sebuah kilang terbakar.", "Baca juga: Bomba kekurangan anggota", "Menurut Bomba, tiada kecederaan."})
+
+	p := NewPipeline(Whitespace{}, ReadAlso{Prefixes: []string{"Baca juga"}}, Entities{}, Html{})
+	if len(p.Run([]*model.News{n})) != 1 {
+		t.Fatal("got the news dropped")
+	}
+
+	// The read also line and the dateline are cut from the HTML too.
+	expected := "<p>Sebuah kilang terbakar.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada kecederaan.</p>\n"
+	if n.Location != "Petaling Jaya" || n.Html != expected {
+		t.Errorf("got %q, %q, expected %q", n.Location, n.Html, expected)
+	}
+
+	// The news without HTML keeps none.
+	n = &model.News{Content: "Sebuah kilang terbakar."}
+	Html{}.Process(n)
+	if n.Html != "" {
+		t.Errorf("got %q", n.Html)
+	}
+}
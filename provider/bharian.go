@@ -1,26 +1,39 @@
 package provider
 
 import (
-	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ahmadmuzakkir/scrapenews/archive"
+	"github.com/ahmadmuzakkir/scrapenews/content"
+	"github.com/ahmadmuzakkir/scrapenews/date"
 	"github.com/ahmadmuzakkir/scrapenews/model"
-	"github.com/pkg/errors"
 )
 
+// bharianRules are the boilerplate of the Berita Harian article body, see content.Shared.
+var bharianRules = content.Rules{
+	Selectors: []string{"div.related-articles", "div.field-name-field-related-articles"},
+	Patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(ikuti|follow) (kami|bh|berita harian)`),
+		regexp.MustCompile(`(?i)^muat turun aplikasi`),
+	},
+}
+
 type Bharian struct {
 	httpClient *http.Client
+	archive    *archive.Archive
 	baseUrl    string
 }
 
-func NewBharian(hc *http.Client) *Bharian {
+func NewBharian(hc *http.Client, a *archive.Archive) *Bharian {
 	return &Bharian{
 		httpClient: hc,
+		archive:    a,
 		baseUrl:    "https://www.bharian.com.my",
 	}
 }
@@ -108,58 +121,63 @@ func (b *Bharian) scrapePage(url string, source model.NewsSource, lastUpdate tim
 	return newsList, nil
 }
 
+// scrapeDetail parses the detail page at the url it is redirected to, the page is archived by the canonical url of the news.
 func (b *Bharian) scrapeDetail(url string, news *model.News) error {
-	var err error
-	req, err := http.NewRequest("GET", url, nil)
+	body, url, err := getBody(b.httpClient, url)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/61.0.3163.100 Safari/537.36")
-
-	resp, err := b.httpClient.Do(req)
-	if err != nil {
+	fetched := time.Now()
+	if err := b.Parse(url, body, fetched, news); err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromResponse(resp)
-	if err != nil {
-		return nil
-	}
+	archiveDetail(b.archive, news.Url, body, fetched)
+	return nil
+}
 
-	datetimeLabel := doc.Find("div.node-meta").Text()
-	//datetimeLabel := strings.TrimSpace(doc.Find("div.node-meta").After("div").Text())
-	datetime, err := b.parseDate(datetimeLabel)
+func (b *Bharian) Parse(url string, body []byte, fetched time.Time, news *model.News) error {
+	doc, err := parseHtml(body)
 	if err != nil {
 		return err
 	}
-	news.Datetime = *datetime
-	log.Println("datetime: ", news.Datetime)
-
-	author := doc.Find("div.author").Text()
-	log.Println("Author: ", author)
-	author = strings.TrimPrefix(author, "Oleh")
-	author = strings.TrimSpace(author)
 
-	news.Author = author
+	// The structured data comes first, the selectors break on every redesign.
+	meta := extractMetadata(doc, url, fetched)
 
-	var content string
-	doc.Find("div.field-item.even").Find("p").Each(func(i int, s *goquery.Selection) {
-		content += s.Text()
-		content += "\n"
-	})
-	log.Println("Content: ", content)
-	news.Content = content
+	// The title of the listing is kept without a headline.
+	news.Title = orDefault(meta.Title, news.Title)
+	meta.record(news, "title", news.Title != "")
 
-	if locationIndex := strings.Index(content, ":"); locationIndex != -1 {
-		news.Location = content[:locationIndex]
-		news.Content = strings.TrimPrefix(content[locationIndex+1:], " ")
+	news.Datetime = meta.Published
+	if news.Datetime.IsZero() {
+		datetimeLabel := doc.Find("div.node-meta").Text()
+		datetime, err := date.Parse(datetimeLabel, fetched)
+		if err != nil {
+			return err
+		}
+		news.Datetime = datetime
+	}
+	log.Println("datetime: ", news.Datetime)
+	meta.record(news, "datetime", true)
 
-	} else {
-		news.Content = content
+	news.Author = meta.Author
+	if news.Author == "" {
+		author := doc.Find("div.author").Text()
+		author = strings.TrimPrefix(author, "Oleh")
+		news.Author = strings.TrimSpace(author)
 	}
+	log.Println("Author: ", news.Author)
+	meta.record(news, "author", news.Author != "")
+
+	article := content.Extract(doc, "div.field-item.even", bharianRules)
+	log.Println("Content: ", article.Paragraphs)
+
+	location, paragraphs := splitDateline(article.Paragraphs)
+	news.Location = location
+	news.SetParagraphs(paragraphs)
+	news.Html = content.Html(article.Html, paragraphs)
 
 	var pictures []*model.Picture
 
@@ -176,130 +194,14 @@ func (b *Bharian) scrapeDetail(url string, news *model.News) error {
 		pictures = append(pictures, &model.Picture{ImageUrl: imageUrl, Caption: caption})
 	})
 	news.Pictures = pictures
-	news.Url = url
-	return nil
-}
-
-func (b *Bharian) parseDate(str string) (*time.Time, error) {
-	parts := strings.Fields(str)
-	if len(parts) != 7 {
-		return nil, errors.New(fmt.Sprintf("could not parse the date %d, %s", len(parts), str))
-	}
 
-	var month time.Month
-
-	switch parts[3] {
-	case "Januari":
-		fallthrough
-	case "JANUARI":
-		fallthrough
-	case "JAN":
-		month = time.January
-	case "Februari":
-		fallthrough
-	case "FEBRUARI":
-		fallthrough
-	case "FEB":
-		month = time.February
-	case "Mac":
-		fallthrough
-	case "MAC":
-		month = time.March
-	case "April":
-		fallthrough
-	case "APRIL":
-		fallthrough
-	case "APR":
-		month = time.April
-	case "Mei":
-		fallthrough
-	case "MEI":
-		month = time.May
-	case "Jun":
-		fallthrough
-	case "JUN":
-		month = time.June
-	case "Julai":
-		fallthrough
-	case "JULAI":
-		fallthrough
-	case "JUL":
-		month = time.July
-	case "Ogos":
-		fallthrough
-	case "OGOS":
-		fallthrough
-	case "OGO":
-		month = time.August
-	case "September":
-		fallthrough
-	case "SEPTEMBER":
-		fallthrough
-	case "SEP":
-		month = time.September
-	case "Oktober":
-		fallthrough
-	case "OKTOBER":
-		fallthrough
-	case "OKT":
-		month = time.October
-	case "November":
-		fallthrough
-	case "NOVEMBER":
-		fallthrough
-	case "NOV":
-		month = time.November
-	case "Disember":
-		fallthrough
-	case "DISEMBER":
-		fallthrough
-	case "DIS":
-		month = time.December
-	}
-
-	if month == 0 {
-		return nil, errors.New(fmt.Sprintf("could not parse the month %s", parts[2]))
-	}
-
-	var day int
-	var err error
-
-	dayPart := strings.Trim(parts[2], ",")
-	if day, err = strconv.Atoi(dayPart); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the day %s", dayPart))
-	}
-
-	var year int
-
-	yearPart := strings.TrimSpace(parts[4])
-	if year, err = strconv.Atoi(yearPart); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the year %s", yearPart))
-	}
-
-	timePart := strings.TrimSpace(parts[6])
-	clock := timePart[len(timePart)-2:]
-	timeStr := timePart[:len(timePart)-2]
-
-	timeArray := strings.Split(timeStr, ":")
-	if len(timeArray) != 2 {
-		return nil, errors.New(fmt.Sprintf("could not parse the time %s", timeStr))
-	}
-
-	var hour int
-	if hour, err = strconv.Atoi(timeArray[0]); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the hour %s", timeArray[0]))
-	}
-
-	var minute int
-	if minute, err = strconv.Atoi(timeArray[1]); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the minute %s", timeArray[1]))
-	}
-
-	if clock == "PM" || clock == "pm" {
-		hour += 12
-	}
-
-	t := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	var tags []string
+	doc.Find("div.field-name-field-tags").Find("a").Each(func(i int, s *goquery.Selection) {
+		tags = append(tags, strings.TrimSpace(s.Text()))
+	})
+	news.Tags = tags
+	news.Url = url
 
-	return &t, nil
+	meta.apply(news)
+	return nil
 }
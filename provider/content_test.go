@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// TestParseContent parses the article body of each newspaper with its rules.
+func TestParseContent(t *testing.T) {
+	tests := []struct {
+		fixture    string
+		parse      func(url string, body []byte, fetched time.Time, news *model.News) error
+		location   string
+		paragraphs []string
+		html       string
+	}{
+		{
+			"bharian.html", (&Bharian{}).Parse, "KLANG",
+			[]string{"Sebuah kilang terbakar awal pagi tadi.", "Menurut Bomba, tiada kecederaan dilaporkan."},
+			"<p>Sebuah kilang terbakar awal pagi tadi.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada kecederaan dilaporkan.</p>\n",
+		},
+		{
+			"nst.html", (&Nst{}).Parse, "KLANG",
+			[]string{"A factory caught fire early this morning.", "According to the Fire Department, no one was hurt."},
+			"<p>A factory caught fire early this morning.</p>\n<p>According to the <a href=\"https://www.bomba.gov.my\">Fire Department</a>, no one was hurt.</p>\n",
+		},
+		{
+			"utusan.html", (&Utusan{}).Parse, "",
+			[]string{"Sebuah kilang terbakar di Klang awal pagi tadi.", "Menurut Bomba, tiada kecederaan dilaporkan."},
+			"<p>Sebuah kilang <b>terbakar</b> di Klang awal pagi tadi.</p>\n<p>Menurut <a href=\"https://www.bomba.gov.my\">Bomba</a>, tiada kecederaan dilaporkan.</p>\n",
+		},
+	}
+
+	for _, test := range tests {
+		body, err := ioutil.ReadFile(filepath.Join("testdata", test.fixture))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		news := &model.News{}
+		if err := test.parse("https://example.com/news/kilang", body, time.Now(), news); err != nil {
+			t.Fatalf("%s: %v", test.fixture, err)
+		}
+
+		if news.Location != test.location {
+			t.Errorf("%s: got the location %q, expected %q", test.fixture, news.Location, test.location)
+		}
+		if !reflect.DeepEqual(news.Paragraphs, test.paragraphs) {
+			t.Errorf("%s: got %q, expected %q", test.fixture, news.Paragraphs, test.paragraphs)
+		}
+		// The HTML has the paragraphs without the dateline.
+		if news.Html != test.html {
+			t.Errorf("%s: got the html %q, expected %q", test.fixture, news.Html, test.html)
+		}
+	}
+}
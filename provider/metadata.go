@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ahmadmuzakkir/scrapenews/canonical"
+	"github.com/ahmadmuzakkir/scrapenews/date"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/processor"
+)
+
+// The strategies that produce the fields of a news, recorded in its annotations, see ExtractionKey.
+const (
+	StrategyJsonLd    = "jsonld"
+	StrategyOpenGraph = "opengraph"
+	StrategyTwitter   = "twitter"
+	StrategyMeta      = "meta"
+	StrategySelector  = "selector"
+	StrategyMissing   = "missing"
+)
+
+// ExtractedFields are the fields whose strategy is recorded, the structured data is tried before the CSS selectors.
+var ExtractedFields = []string{"title", "author", "datetime", "image"}
+
+// ExtractionKey returns the annotation key of the strategy of the field, e.g extract.author.
+func ExtractionKey(field string) string {
+	return "extract." + field
+}
+
+// Metadata is the metadata an article page declares for the search engines and the social networks,
+// read from its JSON-LD NewsArticle, its OpenGraph and Twitter card tags and its meta keywords, in that order.
+type Metadata struct {
+	Title        string
+	Author       string
+	Description  string
+	Section      string
+	CanonicalUrl string
+	Image        string
+	Published    time.Time
+	Modified     time.Time
+
+	// The keywords and the article:tag of the page, without the duplicates.
+	Keywords []string
+
+	// The strategies of the ExtractedFields found, by field.
+	Strategies map[string]string
+
+	// The time the page was fetched, see Provider.Parse.
+	fetched time.Time
+}
+
+// candidate is a value of a field, along with the strategy that produced it.
+type candidate struct {
+	strategy string
+	value    string
+}
+
+// extractMetadata returns the metadata of the document, the urls are resolved against the url of the page
+// and the relative dates against the time it was fetched.
+func extractMetadata(doc *goquery.Document, pageUrl string, fetched time.Time) *Metadata {
+	m := &Metadata{Strategies: make(map[string]string), fetched: fetched}
+	article := findArticle(doc)
+	if article == nil {
+		article = &jsonLdArticle{}
+	}
+
+	m.Title = m.first("title",
+		candidate{StrategyJsonLd, strings.TrimSpace(article.Headline)},
+		candidate{StrategyOpenGraph, meta(doc, "og:title")},
+		candidate{StrategyTwitter, meta(doc, "twitter:title")},
+	)
+	m.Author = m.first("author",
+		candidate{StrategyJsonLd, article.authorName()},
+		candidate{StrategyOpenGraph, meta(doc, "article:author")},
+		candidate{StrategyMeta, meta(doc, "author")},
+	)
+	m.Image = m.first("image",
+		candidate{StrategyJsonLd, firstString(article.Image)},
+		candidate{StrategyOpenGraph, meta(doc, "og:image")},
+		candidate{StrategyTwitter, orDefault(meta(doc, "twitter:image"), meta(doc, "twitter:image:src"))},
+	)
+	m.Published = m.firstDate("datetime",
+		candidate{StrategyJsonLd, article.DatePublished},
+		candidate{StrategyOpenGraph, meta(doc, "article:published_time")},
+	)
+
+	link, _ := doc.Find(`link[rel="canonical"]`).Attr("href")
+	m.Description = orDefault(strings.TrimSpace(article.Description), meta(doc, "og:description"), meta(doc, "twitter:description"), meta(doc, "description"))
+	m.Section = orDefault(firstString(article.ArticleSection), meta(doc, "article:section"))
+	m.CanonicalUrl = orDefault(firstString(article.MainEntityOfPage), strings.TrimSpace(link), meta(doc, "og:url"))
+	m.Modified = m.firstDate("",
+		candidate{StrategyJsonLd, article.DateModified},
+		candidate{StrategyOpenGraph, meta(doc, "article:modified_time")},
+		candidate{StrategyOpenGraph, meta(doc, "og:updated_time")},
+	)
+
+	m.addKeywords(article.Keywords)
+	doc.Find(`meta[property="article:tag"], meta[name="article:tag"]`).Each(func(i int, s *goquery.Selection) {
+		m.addKeywords(s.AttrOr("content", ""))
+	})
+	m.addKeywords(meta(doc, "keywords"), meta(doc, "news_keywords"))
+
+	// The author of the OpenGraph tags may be the url of a profile.
+	if strings.HasPrefix(m.Author, "http://") || strings.HasPrefix(m.Author, "https://") {
+		m.Author = ""
+		delete(m.Strategies, "author")
+	}
+	if m.CanonicalUrl != "" {
+		m.CanonicalUrl = resolveUrl(pageUrl, m.CanonicalUrl)
+	}
+	if m.Image != "" {
+		m.Image = resolveUrl(pageUrl, m.Image)
+	}
+
+	return m
+}
+
+// first returns the first value of the candidates and records its strategy for the field.
+func (m *Metadata) first(field string, candidates ...candidate) string {
+	for _, c := range candidates {
+		if c.value != "" {
+			m.Strategies[field] = c.strategy
+			return c.value
+		}
+	}
+	return ""
+}
+
+// firstDate returns the first date of the candidates that is valid and records its strategy for the field, if it is not empty.
+func (m *Metadata) firstDate(field string, candidates ...candidate) time.Time {
+	for _, c := range candidates {
+		if t := parseMetaDate(strings.TrimSpace(c.value), m.fetched); !t.IsZero() {
+			if field != "" {
+				m.Strategies[field] = c.strategy
+			}
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// record annotates the news with the strategy of the field: the one of the metadata if it has the field,
+// else the selectors if they found it, else missing.
+func (m *Metadata) record(news *model.News, field string, selected bool) {
+	strategy, exist := m.Strategies[field]
+	switch {
+	case exist:
+	case selected:
+		strategy = StrategySelector
+	default:
+		strategy = StrategyMissing
+	}
+	processor.Annotate(news, ExtractionKey(field), strategy)
+}
+
+// apply sets the metadata fields of the news and adds the keywords to its tags and the image to its pictures.
+// The strategy of the image is recorded, the image comes first. The url of the news is replaced by its canonical url.
+func (m *Metadata) apply(news *model.News) {
+	news.Url = m.url(news.Url)
+	news.CanonicalUrl = m.CanonicalUrl
+	news.Description = m.Description
+	news.Section = m.Section
+	if !m.Modified.IsZero() {
+		modified := m.Modified
+		news.Modified = &modified
+	}
+
+	news.Tags = mergeTags(news.Tags, m.Keywords)
+
+	m.record(news, "image", len(news.Pictures) > 0)
+	if m.Image == "" {
+		return
+	}
+
+	for i, p := range news.Pictures {
+		if p.ImageUrl == m.Image {
+			news.Pictures = append(append([]*model.Picture{p}, news.Pictures[:i]...), news.Pictures[i+1:]...)
+			return
+		}
+	}
+	news.Pictures = append([]*model.Picture{{ImageUrl: m.Image}}, news.Pictures...)
+}
+
+// url returns the canonical url of the page, see canonical.Declared.
+func (m *Metadata) url(pageUrl string) string {
+	return canonical.Declared(pageUrl, m.CanonicalUrl)
+}
+
+// addKeywords adds the keywords separated by commas, a value may also be a list of keywords.
+func (m *Metadata) addKeywords(values ...interface{}) {
+	for _, v := range values {
+		for _, s := range texts(v) {
+			m.Keywords = mergeTags(m.Keywords, strings.Split(s, ","))
+		}
+	}
+}
+
+// mergeTags appends the tags not already in the list, ignoring the case and the empty ones.
+func mergeTags(list []string, tags []string) []string {
+	seen := make(map[string]struct{})
+	var merged []string
+	for _, tag := range append(append([]string(nil), list...), tags...) {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if _, exist := seen[key]; exist || tag == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+// jsonLdArticle is the part of a schema.org NewsArticle read. Most of its properties may be a text, an object or a list.
+type jsonLdArticle struct {
+	Type             interface{} `json:"@type"`
+	Headline         string      `json:"headline"`
+	Description      string      `json:"description"`
+	ArticleSection   interface{} `json:"articleSection"`
+	Keywords         interface{} `json:"keywords"`
+	DatePublished    string      `json:"datePublished"`
+	DateModified     string      `json:"dateModified"`
+	Image            interface{} `json:"image"`
+	Author           interface{} `json:"author"`
+	MainEntityOfPage interface{} `json:"mainEntityOfPage"`
+}
+
+// articleTypes are the schema.org types of an article.
+var articleTypes = map[string]bool{
+	"NewsArticle": true, "Article": true, "ReportageNewsArticle": true, "AnalysisNewsArticle": true,
+	"OpinionNewsArticle": true, "BlogPosting": true,
+}
+
+// findArticle returns the first article of the JSON-LD scripts of the document, nil if there is none.
+// A script holds an object, a list of objects or a @graph of objects.
+func findArticle(doc *goquery.Document) *jsonLdArticle {
+	var article *jsonLdArticle
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		var v interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &v); err != nil {
+			return true
+		}
+
+		var objects []interface{}
+		switch v := v.(type) {
+		case []interface{}:
+			objects = v
+		case map[string]interface{}:
+			if graph, ok := v["@graph"].([]interface{}); ok {
+				objects = graph
+			} else {
+				objects = []interface{}{v}
+			}
+		}
+
+		for _, o := range objects {
+			o, ok := o.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			isArticle := false
+			for _, t := range texts(o["@type"]) {
+				isArticle = isArticle || articleTypes[t]
+			}
+			if !isArticle {
+				continue
+			}
+
+			// The object is decoded again into the article, it is valid JSON.
+			b, _ := json.Marshal(o)
+			article = &jsonLdArticle{}
+			if err := json.Unmarshal(b, article); err != nil {
+				article = nil
+				continue
+			}
+			return false
+		}
+		return true
+	})
+	return article
+}
+
+// authorName returns the names of the authors separated by commas.
+func (a *jsonLdArticle) authorName() string {
+	return strings.Join(texts(a.Author), ", ")
+}
+
+// texts returns the texts of a JSON-LD value, a text, an object by its name, url or @id, or a list of them.
+func texts(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			return []string{v}
+		}
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			list = append(list, texts(item)...)
+		}
+		return list
+	case map[string]interface{}:
+		for _, key := range []string{"name", "url", "@id"} {
+			if s, ok := v[key].(string); ok && strings.TrimSpace(s) != "" {
+				return []string{strings.TrimSpace(s)}
+			}
+		}
+	}
+	return nil
+}
+
+func firstString(v interface{}) string {
+	if list := texts(v); len(list) > 0 {
+		return list[0]
+	}
+	return ""
+}
+
+// meta returns the content of the meta tag by its property or its name, e.g og:title or keywords.
+func meta(doc *goquery.Document, key string) string {
+	content, _ := doc.Find(`meta[property="` + key + `"], meta[name="` + key + `"]`).First().Attr("content")
+	return strings.TrimSpace(content)
+}
+
+// parseMetaDate parses the ISO 8601 date of the metadata, the zero time if it is missing or invalid.
+func parseMetaDate(s string, fetched time.Time) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := date.Parse(s, fetched)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func orDefault(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
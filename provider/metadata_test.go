@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+const articlePage = `<html><head>
+<link rel="canonical" href="/news/2018/06/kebakaran">
+<meta property="og:title" content="Kebakaran kilang">
+<meta property="og:description" content="Sebuah kilang terbakar.">
+<meta property="og:image" content="https://example.com/og.jpg">
+<meta property="article:section" content="Nasional">
+<meta property="article:modified_time" content="2018-06-11T10:00:00+08:00">
+<meta property="article:tag" content="Kebakaran">
+<meta property="article:tag" content="Bomba">
+<meta name="keywords" content="kebakaran, kilang, ">
+<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+	{"@type": "WebSite", "name": "Example"},
+	{"@type": ["NewsArticle"], "headline": "Kilang terbakar di Klang", "articleSection": ["Nasional", "Jenayah"],
+	"datePublished": "2018-06-11T08:00:00+08:00", "image": {"@type": "ImageObject", "url": "/ld.jpg"},
+	"author": [{"@type": "Person", "name": "Ahmad"}, {"@type": "Person", "name": "Siti"}], "keywords": ["Klang"]}
+]}</script>
+<script type="application/ld+json">{ invalid</script>
+</head><body></body></html>`
+
+func TestExtractMetadata(t *testing.T) {
+	doc, err := parseHtml([]byte(articlePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := extractMetadata(doc, "https://example.com/news/2018/06/kebakaran?utm_source=x", time.Now())
+
+	// The JSON-LD comes first, then the OpenGraph tags.
+	if m.Title != "Kilang terbakar di Klang" || m.Author != "Ahmad, Siti" || m.Section != "Nasional" {
+		t.Errorf("got %+v", m)
+	}
+	if m.Description != "Sebuah kilang terbakar." {
+		t.Errorf("got the description %q", m.Description)
+	}
+	if m.CanonicalUrl != "https://example.com/news/2018/06/kebakaran" || m.Image != "https://example.com/ld.jpg" {
+		t.Errorf("got the urls %q and %q", m.CanonicalUrl, m.Image)
+	}
+	if !m.Published.Equal(time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)) || !m.Modified.Equal(time.Date(2018, 6, 11, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("got the dates %v and %v", m.Published, m.Modified)
+	}
+	if expected := []string{"Klang", "Kebakaran", "Bomba", "kilang"}; !reflect.DeepEqual(m.Keywords, expected) {
+		t.Errorf("got the keywords %q, expected %q", m.Keywords, expected)
+	}
+
+	news := &model.News{Tags: []string{"BOMBA", "Selangor"}, Pictures: []*model.Picture{{ImageUrl: "https://example.com/body.jpg"}}}
+	m.apply(news)
+
+	if expected := []string{"BOMBA", "Selangor", "Klang", "Kebakaran", "kilang"}; !reflect.DeepEqual(news.Tags, expected) {
+		t.Errorf("got the tags %q, expected %q", news.Tags, expected)
+	}
+	if len(news.Pictures) != 2 || news.Pictures[0].ImageUrl != m.Image {
+		t.Errorf("got the pictures %v", news.Pictures)
+	}
+	if news.Modified == nil || news.CanonicalUrl != m.CanonicalUrl || news.Section != "Nasional" {
+		t.Errorf("got %+v", news)
+	}
+
+	// The lead image is not added twice.
+	m.apply(news)
+	if len(news.Pictures) != 2 {
+		t.Errorf("got the pictures %v", news.Pictures)
+	}
+}
+
+func TestExtractMetadataWithoutJsonLd(t *testing.T) {
+	doc, err := parseHtml([]byte(`<html><head>
+<meta property="og:url" content="https://example.com/a">
+<meta name="twitter:title" content="Tajuk">
+<meta name="twitter:image" content="https://example.com/t.jpg">
+<meta name="description" content="Keterangan">
+</head></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := extractMetadata(doc, "https://example.com/a", time.Now())
+	if m.Title != "Tajuk" || m.Description != "Keterangan" || m.CanonicalUrl != "https://example.com/a" || m.Image != "https://example.com/t.jpg" {
+		t.Errorf("got %+v", m)
+	}
+	if !m.Modified.IsZero() || m.Keywords != nil {
+		t.Errorf("got %+v", m)
+	}
+
+	news := &model.News{}
+	m.apply(news)
+	if news.Modified != nil || news.Tags != nil {
+		t.Errorf("got %+v", news)
+	}
+}
+
+func TestParseStrategies(t *testing.T) {
+	body := `<html><head>
+<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Kilang terbakar", "datePublished": "2018-06-11T08:00:00+08:00"}</script>
+</head><body>
+<div class="author">Oleh Ahmad</div>
+<div class="node-meta">Isnin, 11 Jun 2018 @ 7:00 AM</div>
+<div class="field-item even"><p>KLANG: Sebuah kilang terbakar.</p></div>
+</body></html>`
+
+	news := &model.News{Title: "Tajuk senarai"}
+	if err := NewBharian(nil, nil).Parse("https://www.bharian.com.my/berita/kes/2018/06/1", []byte(body), time.Now(), news); err != nil {
+		t.Fatal(err)
+	}
+
+	if news.Title != "Kilang terbakar" || news.Author != "Ahmad" || !news.Datetime.Equal(time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("got %+v", news)
+	}
+
+	expected := map[string]string{
+		"extract.title": StrategyJsonLd, "extract.author": StrategySelector, "extract.datetime": StrategyJsonLd, "extract.image": StrategyMissing,
+	}
+	if !reflect.DeepEqual(news.Annotations, expected) {
+		t.Errorf("got %v, expected %v", news.Annotations, expected)
+	}
+
+	// Without the structured data, the selectors are used.
+	news = &model.News{Title: "Tajuk senarai"}
+	if err := NewNst(nil, nil).Parse("https://www.nst.com.my/news/1", []byte(`<span class="post-date">June 11, 2018 @ 8:00am</span>`), time.Now(), news); err != nil {
+		t.Fatal(err)
+	}
+	if news.Title != "Tajuk senarai" || news.Annotations["extract.title"] != StrategySelector ||
+		news.Annotations["extract.datetime"] != StrategySelector || news.Annotations["extract.author"] != StrategyMissing {
+		t.Errorf("got %+v", news)
+	}
+}
+
+func TestParseRelativeDate(t *testing.T) {
+	fetched := time.Date(2018, 6, 11, 10, 0, 0, 0, time.UTC)
+	expected := time.Date(2018, 6, 11, 8, 0, 0, 0, time.UTC)
+
+	// The date is the same on a reparse, it is relative to the fetch.
+	news := &model.News{}
+	body := []byte(`<div class="node-meta">2 jam lalu</div>`)
+	if err := NewBharian(nil, nil).Parse("https://www.bharian.com.my/berita/kes/2018/06/1", body, fetched, news); err != nil {
+		t.Fatal(err)
+	}
+	if !news.Datetime.Equal(expected) {
+		t.Errorf("got %v, expected %v", news.Datetime, expected)
+	}
+
+	news = &model.News{}
+	body = []byte(`<span class="post-date">2 hours ago</span>`)
+	if err := NewNst(nil, nil).Parse("https://www.nst.com.my/news/1", body, fetched, news); err != nil {
+		t.Fatal(err)
+	}
+	if !news.Datetime.Equal(expected) {
+		t.Errorf("got %v, expected %v", news.Datetime, expected)
+	}
+}
+
+func TestMetadataUrl(t *testing.T) {
+	const page = "http://www.utusan.com.my//berita/nasional/a/?utm_source=facebook"
+
+	tests := map[string]string{
+		"": "https://www.utusan.com.my/berita/nasional/a",
+		"http://www.utusan.com.my/berita/nasional/a-1": "https://www.utusan.com.my/berita/nasional/a-1",
+		"https://www.utusan.com.my/":                   "https://www.utusan.com.my/berita/nasional/a",
+		"https://www.utusan.com.my/berita/a-1":         "https://www.utusan.com.my/berita/nasional/a",
+		"https://www.facebook.com/utusanonline/":       "https://www.utusan.com.my/berita/nasional/a",
+	}
+
+	for declared, expected := range tests {
+		m := &Metadata{CanonicalUrl: declared}
+		if got := m.url(page); got != expected {
+			t.Errorf("%q: got %q, expected %q", declared, got, expected)
+		}
+	}
+}
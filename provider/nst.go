@@ -1,26 +1,39 @@
 package provider
 
 import (
-	"errors"
-	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ahmadmuzakkir/scrapenews/archive"
+	"github.com/ahmadmuzakkir/scrapenews/content"
+	"github.com/ahmadmuzakkir/scrapenews/date"
 	"github.com/ahmadmuzakkir/scrapenews/model"
 )
 
+// nstRules are the boilerplate of the NST article body, see content.Shared.
+var nstRules = content.Rules{
+	Selectors: []string{"div.related-articles", "div.field-name-field-related-articles"},
+	Patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^\(?(nstp|nst) (file )?(pic|photo)\)?`),
+		regexp.MustCompile(`(?i)^download the nst app`),
+	},
+}
+
 type Nst struct {
 	httpClient *http.Client
+	archive    *archive.Archive
 	baseUrl    string
 }
 
-func NewNst(hc *http.Client) *Nst {
+func NewNst(hc *http.Client, a *archive.Archive) *Nst {
 	return &Nst{
 		httpClient: hc,
+		archive:    a,
 		baseUrl:    "https://www.nst.com.my",
 	}
 }
@@ -77,7 +90,7 @@ func (b *Nst) scrapePage(url string, source model.NewsSource, lastUpdate time.Ti
 		//		return
 		//	}
 		//
-		//	news.Datetime = *datetime
+		//	news.Datetime = datetime
 		//
 		//	log.Println("Datetime: ", news.Datetime)
 		//})
@@ -127,56 +140,63 @@ func (b *Nst) scrapePage(url string, source model.NewsSource, lastUpdate time.Ti
 	return newsList, nil
 }
 
+// scrapeDetail parses the detail page at the url it is redirected to, the page is archived by the canonical url of the news.
 func (b *Nst) scrapeDetail(url string, news *model.News) error {
-	var err error
-	req, err := http.NewRequest("GET", url, nil)
+	body, url, err := getBody(b.httpClient, url)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/61.0.3163.100 Safari/537.36")
-
-	resp, err := b.httpClient.Do(req)
-	if err != nil {
+	fetched := time.Now()
+	if err := b.Parse(url, body, fetched, news); err != nil {
 		return err
 	}
 
-	defer resp.Body.Close()
+	archiveDetail(b.archive, news.Url, body, fetched)
+	return nil
+}
 
-	doc, err := goquery.NewDocumentFromResponse(resp)
+func (b *Nst) Parse(url string, body []byte, fetched time.Time, news *model.News) error {
+	doc, err := parseHtml(body)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	author := doc.Find("div.author").Find("a").Text()
-	if author == "" {
-		author = doc.Find("span.author").Find("a").Text()
-	}
-	author = strings.TrimSpace(author)
-	log.Println("Author: ", author)
-	news.Author = author
+	// The structured data comes first, the selectors break on every redesign.
+	meta := extractMetadata(doc, url, fetched)
 
-	datetime, err := b.parseDate(doc.Find("span.post-date").Text())
-	if err != nil {
-		return err
+	// The title of the listing is kept without a headline.
+	news.Title = orDefault(meta.Title, news.Title)
+	meta.record(news, "title", news.Title != "")
+
+	news.Author = meta.Author
+	if news.Author == "" {
+		author := doc.Find("div.author").Find("a").Text()
+		if author == "" {
+			author = doc.Find("span.author").Find("a").Text()
+		}
+		news.Author = strings.TrimSpace(author)
 	}
-	news.Datetime = *datetime
+	log.Println("Author: ", news.Author)
+	meta.record(news, "author", news.Author != "")
 
-	var content string
-	doc.Find("div.field-item.even").Find("p").Each(func(i int, s *goquery.Selection) {
-		content += s.Text()
-		content += "\n"
-	})
-	log.Println("Content: ", content)
-	news.Content = content
+	news.Datetime = meta.Published
+	if news.Datetime.IsZero() {
+		datetime, err := date.Parse(doc.Find("span.post-date").Text(), fetched)
+		if err != nil {
+			return err
+		}
+		news.Datetime = datetime
+	}
+	meta.record(news, "datetime", true)
 
-	if locationIndex := strings.Index(content, ":"); locationIndex != -1 {
-		news.Location = content[:locationIndex]
-		news.Content = strings.TrimPrefix(content[locationIndex+1:], " ")
+	article := content.Extract(doc, "div.field-item.even", nstRules)
+	log.Println("Content: ", article.Paragraphs)
 
-	} else {
-		news.Content = content
-	}
+	location, paragraphs := splitDateline(article.Paragraphs)
+	news.Location = location
+	news.SetParagraphs(paragraphs)
+	news.Html = content.Html(article.Html, paragraphs)
 
 	var pictures []*model.Picture
 
@@ -193,88 +213,14 @@ func (b *Nst) scrapeDetail(url string, news *model.News) error {
 		pictures = append(pictures, &model.Picture{ImageUrl: imageUrl, Caption: caption})
 	})
 	news.Pictures = pictures
-	news.Url = url
-	return nil
-}
-
-func (b *Nst) parseDate(str string) (*time.Time, error) {
-	parts := strings.Fields(str)
-	if len(parts) != 5 {
-		return nil, errors.New(fmt.Sprintf("could not parse the date %s", str))
-	}
 
-	var month time.Month
-
-	switch parts[0] {
-	case "January":
-		month = time.January
-	case "February":
-		month = time.February
-	case "March":
-		month = time.March
-	case "April":
-		month = time.April
-	case "May":
-		month = time.May
-	case "June":
-		month = time.June
-	case "July":
-		month = time.July
-	case "August":
-		month = time.August
-	case "September":
-		month = time.September
-	case "October":
-		month = time.October
-	case "November":
-		month = time.November
-	case "December":
-		month = time.December
-	}
-
-	if month == 0 {
-		return nil, errors.New(fmt.Sprintf("could not parse the month %s", parts[0]))
-	}
-
-	var day int
-	var err error
-
-	dayPart := strings.Trim(parts[1], ",")
-	if day, err = strconv.Atoi(dayPart); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the day %s", dayPart))
-	}
-
-	var year int
-
-	yearPart := strings.TrimSpace(parts[2])
-	if year, err = strconv.Atoi(yearPart); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the year %s", yearPart))
-	}
-
-	timePart := strings.TrimSpace(parts[4])
-	clock := timePart[len(timePart)-2:]
-	timeStr := timePart[:len(timePart)-2]
-
-	timeArray := strings.Split(timeStr, ":")
-	if len(timeArray) != 2 {
-		return nil, errors.New(fmt.Sprintf("could not parse the time %s", timeStr))
-	}
-
-	var hour int
-	if hour, err = strconv.Atoi(timeArray[0]); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the hour %s", timeArray[0]))
-	}
-
-	var minute int
-	if minute, err = strconv.Atoi(timeArray[1]); err != nil {
-		return nil, errors.New(fmt.Sprintf("could not parse the minute %s", timeArray[1]))
-	}
-
-	if clock == "PM" || clock == "pm" {
-		hour += 12
-	}
-
-	t := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	var tags []string
+	doc.Find("div.field-name-field-tags").Find("a").Each(func(i int, s *goquery.Selection) {
+		tags = append(tags, strings.TrimSpace(s.Text()))
+	})
+	news.Tags = tags
+	news.Url = url
 
-	return &t, nil
+	meta.apply(news)
+	return nil
 }
@@ -7,4 +7,8 @@ import (
 
 type Provider interface {
 	Scrape(source model.NewsSource, maxPageNo int, lastUpdate time.Time) ([]*model.News, error)
-}
\ No newline at end of file
+
+	// Parse extracts the detail of the news from the raw detail page at the url, fetched at the given time.
+	// The relative dates of the page, e.g 2 jam lalu, are relative to the fetch, so a reparse finds the same dates.
+	Parse(url string, body []byte, fetched time.Time, news *model.News) error
+}
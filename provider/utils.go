@@ -1,9 +1,17 @@
 package provider
 
 import (
+	"bytes"
+	"io/ioutil"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ahmadmuzakkir/scrapenews/archive"
+	"github.com/ahmadmuzakkir/scrapenews/model"
 )
 
 func getUrl(client *http.Client, url string) (*goquery.Document, error) {
@@ -29,3 +37,78 @@ func getUrl(client *http.Client, url string) (*goquery.Document, error) {
 
 	return doc, nil
 }
+
+// getBody returns the raw body of the url, along with the url it was redirected to.
+func getBody(client *http.Client, url string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/61.0.3163.100 Safari/537.36")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, resp.Request.URL.String(), err
+}
+
+func parseHtml(body []byte) (*goquery.Document, error) {
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// archiveDetail keeps the raw detail page of the news by the id of its url, and the time it was fetched, if the archive is enabled.
+// A failure is only logged, it should not stop the scraping.
+func archiveDetail(a *archive.Archive, url string, body []byte, fetched time.Time) {
+	if a == nil {
+		return
+	}
+
+	news := &model.News{Url: url}
+	news.GenerateId()
+
+	if err := a.Put(news.Id, body, fetched); err != nil {
+		log.Println("archive error: ", err)
+	}
+}
+
+// resolveUrl resolves the ref, which may be relative, against the base url.
+func resolveUrl(base, ref string) string {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return base + ref
+	}
+
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return base + ref
+	}
+
+	return baseUrl.ResolveReference(refUrl).String()
+}
+
+// splitDateline takes the text before the first colon of the first paragraph as the location, e.g KUALA LUMPUR: ...
+// See entity.NormaliseLocation for the text that is not a dateline.
+func splitDateline(paragraphs []string) (string, []string) {
+	if len(paragraphs) == 0 {
+		return "", paragraphs
+	}
+
+	locationIndex := strings.Index(paragraphs[0], ":")
+	if locationIndex == -1 {
+		return "", paragraphs
+	}
+
+	location := paragraphs[0][:locationIndex]
+	first := strings.TrimSpace(paragraphs[0][locationIndex+1:])
+
+	if first == "" {
+		return location, paragraphs[1:]
+	}
+	return location, append([]string{first}, paragraphs[1:]...)
+}
@@ -3,22 +3,35 @@ package provider
 import (
 	"log"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/ahmadmuzakkir/scrapenews/archive"
+	"github.com/ahmadmuzakkir/scrapenews/content"
 	"github.com/ahmadmuzakkir/scrapenews/model"
 )
 
+// utusanRules are the boilerplate of the Utusan article body, see content.Shared.
+var utusanRules = content.Rules{
+	Selectors: []string{"aside", "div.inline-related", "div.content__article-share"},
+	Patterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^(ikuti|sertai) .*(telegram|facebook|twitter|instagram)`),
+	},
+}
+
 type Utusan struct {
 	httpClient *http.Client
+	archive    *archive.Archive
 	baseUrl    string
 	urls       []string
 }
 
-func NewUtusan(hc *http.Client) *Utusan {
+func NewUtusan(hc *http.Client, a *archive.Archive) *Utusan {
 	return &Utusan{
 		httpClient: hc,
+		archive:    a,
 		baseUrl:    "http://www.utusan.com.my/",
 		urls:       []string{"http://www.utusan.com.my/berita/nasional"},
 	}
@@ -51,7 +64,7 @@ func (b *Utusan) scrapePage(source model.NewsSource, lastUpdate time.Time) ([]*m
 			return true
 		}
 
-		detailUrl := b.baseUrl + val
+		detailUrl := resolveUrl(b.baseUrl, val)
 		log.Println("detail url: ", detailUrl)
 
 		news = &model.News{Source: source}
@@ -77,15 +90,37 @@ func (b *Utusan) scrapePage(source model.NewsSource, lastUpdate time.Time) ([]*m
 	return newsList, nil
 }
 
+// scrapeDetail parses the detail page at the url it is redirected to, the page is archived by the canonical url of the news.
 func (b *Utusan) scrapeDetail(url string, news *model.News) error {
-	doc, err := getUrl(b.httpClient, url)
+	body, url, err := getBody(b.httpClient, url)
 	if err != nil {
 		return err
 	}
 
-	title := doc.Find("div.content_header.content__header.tonal__header").Find("h1").Text()
-	news.Title = title
-	log.Println("title: ", title)
+	fetched := time.Now()
+	if err := b.Parse(url, body, fetched, news); err != nil {
+		return err
+	}
+
+	archiveDetail(b.archive, news.Url, body, fetched)
+	return nil
+}
+
+func (b *Utusan) Parse(url string, body []byte, fetched time.Time, news *model.News) error {
+	doc, err := parseHtml(body)
+	if err != nil {
+		return err
+	}
+
+	// The structured data comes first, the selectors break on every redesign.
+	meta := extractMetadata(doc, url, fetched)
+
+	news.Title = meta.Title
+	if news.Title == "" {
+		news.Title = doc.Find("div.content_header.content__header.tonal__header").Find("h1").Text()
+	}
+	log.Println("title: ", news.Title)
+	meta.record(news, "title", news.Title != "")
 
 	var pictures []*model.Picture
 
@@ -99,7 +134,7 @@ func (b *Utusan) scrapeDetail(url string, news *model.News) error {
 		}
 		log.Println("url: ", url)
 
-		url = b.baseUrl + url
+		url = resolveUrl(b.baseUrl, url)
 		caption, _ = s.Attr("title")
 
 		log.Println("caption: ", caption)
@@ -109,7 +144,9 @@ func (b *Utusan) scrapeDetail(url string, news *model.News) error {
 	})
 	news.Pictures = pictures
 
-	if timestamp, exist := doc.Find("p.content__dateline").Find("time").Attr("data-timestamp"); exist {
+	if !meta.Published.IsZero() {
+		news.Datetime = meta.Published
+	} else if timestamp, exist := doc.Find("p.content__dateline").Find("time").Attr("data-timestamp"); exist {
 		log.Println("timestamp: ", timestamp)
 
 		timestampInt, err := strconv.ParseInt(timestamp, 10, 64)
@@ -117,18 +154,20 @@ func (b *Utusan) scrapeDetail(url string, news *model.News) error {
 			log.Println("timestamp err: ", err)
 			return err
 		}
-		news.Datetime = time.Unix(timestampInt/1000, 0)
+		news.Datetime = time.Unix(timestampInt/1000, 0).UTC()
 	}
+	meta.record(news, "datetime", !news.Datetime.IsZero())
 
-	news.Author = doc.Find("a.tone-colour.author").Find("span").Text()
+	news.Author = meta.Author
+	if news.Author == "" {
+		news.Author = doc.Find("a.tone-colour.author").Find("span").Text()
+	}
+	meta.record(news, "author", news.Author != "")
 
-	var content string
-	doc.Find("div.clearfix.article_body.content__article-body.from-content-api.js-article__body").Find("p").Each(func(i int, s *goquery.Selection) {
-		content += s.Text()
-		content += "\n"
-	})
-	log.Println("content: ", content)
-	news.Content = content
+	article := content.Extract(doc, "div.clearfix.article_body.content__article-body.from-content-api.js-article__body", utusanRules)
+	log.Println("content: ", article.Paragraphs)
+	news.SetParagraphs(article.Paragraphs)
+	news.Html = article.Html
 
 	var tags []string
 	doc.Find("ul.tag-list").Find("li").Each(func(i int, s *goquery.Selection) {
@@ -139,6 +178,8 @@ func (b *Utusan) scrapeDetail(url string, news *model.News) error {
 	})
 	news.Tags = tags
 	news.Url = url
+
+	meta.apply(news)
 	log.Println("news: ", news.ToString())
 	log.Println()
 
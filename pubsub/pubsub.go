@@ -0,0 +1,100 @@
+package pubsub
+
+import (
+	"log"
+	"sync"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// The number of events queued for a subscriber, a subscriber too slow to keep up is closed.
+const subscriberBuffer = 256
+
+// Event is an inserted news, the ids are increasing.
+type Event struct {
+	Id   uint64
+	News *model.News
+}
+
+// Subscription receives the events published after it is created, until it is closed.
+// C is closed if the subscriber does not keep up, it may resume from its last event id.
+type Subscription struct {
+	C <-chan Event
+	c chan Event
+}
+
+// Broker publishes the news inserted by the store.Refresher to the subscribers, see store.Listener.
+// It keeps the latest events, so a subscriber can resume from the id of its last event.
+type Broker struct {
+	mu          sync.Mutex
+	seq         uint64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker(historySize int) *Broker {
+	return &Broker{
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Inserted publishes the news.
+func (b *Broker) Inserted(news []*model.News) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, n := range news {
+		b.seq++
+		e := Event{Id: b.seq, News: n}
+
+		b.history = append(b.history, e)
+		if len(b.history) > b.historySize {
+			b.history = b.history[len(b.history)-b.historySize:]
+		}
+
+		for s := range b.subscribers {
+			select {
+			case s.c <- e:
+			default:
+				log.Println("pubsub: a subscriber is too slow, it is closed")
+				delete(b.subscribers, s)
+				close(s.c)
+			}
+		}
+	}
+}
+
+// Subscribe returns the events after lastId that are still kept, and the subscription to the next events.
+// All the kept events are returned if lastId is unknown, e.g after a restart. A lastId of 0 returns none.
+func (b *Broker) Subscribe(lastId uint64) ([]Event, *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var missed []Event
+	if lastId > 0 {
+		for _, e := range b.history {
+			if e.Id > lastId || lastId > b.seq {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	c := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: c, c: c}
+	b.subscribers[s] = struct{}{}
+
+	return missed, s
+}
+
+// Unsubscribe closes the subscription.
+func (b *Broker) Unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exist := b.subscribers[s]; exist {
+		delete(b.subscribers, s)
+		close(s.c)
+	}
+}
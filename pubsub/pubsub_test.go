@@ -0,0 +1,108 @@
+package pubsub
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func newsList(ids ...string) []*model.News {
+	var news []*model.News
+	for _, id := range ids {
+		news = append(news, &model.News{Id: id})
+	}
+	return news
+}
+
+func eventIds(events []Event) []uint64 {
+	var ids []uint64
+	for _, e := range events {
+		ids = append(ids, e.Id)
+	}
+	return ids
+}
+
+func TestInserted(t *testing.T) {
+	b := NewBroker(10)
+
+	_, s := b.Subscribe(0)
+	b.Inserted(newsList("a", "b"))
+
+	for i, id := range []string{"a", "b"} {
+		e := <-s.C
+		if e.Id != uint64(i+1) || e.News.Id != id {
+			t.Errorf("got %d %s, expected %d %s", e.Id, e.News.Id, i+1, id)
+		}
+	}
+
+	b.Unsubscribe(s)
+	if _, ok := <-s.C; ok {
+		t.Error("got the subscription open after Unsubscribe")
+	}
+
+	// The events are not sent to the closed subscription.
+	b.Inserted(newsList("c"))
+	b.Unsubscribe(s)
+}
+
+func TestSubscribe(t *testing.T) {
+	b := NewBroker(3)
+	b.Inserted(newsList("a", "b", "c", "d", "e"))
+
+	tests := []struct {
+		lastId   uint64
+		expected []uint64
+	}{
+		// A new subscriber gets no history.
+		{0, nil},
+		// Only the latest 3 events are kept.
+		{1, []uint64{3, 4, 5}},
+		{3, []uint64{4, 5}},
+		{5, nil},
+		// The id of a previous run, after a restart, gets every kept event.
+		{42, []uint64{3, 4, 5}},
+	}
+
+	for _, test := range tests {
+		missed, s := b.Subscribe(test.lastId)
+		if got := eventIds(missed); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%d: got %v, expected %v", test.lastId, got, test.expected)
+		}
+		b.Unsubscribe(s)
+	}
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	b := NewBroker(1)
+
+	_, slow := b.Subscribe(0)
+	_, fast := b.Subscribe(0)
+
+	var received int
+	for i := 0; i < subscriberBuffer+1; i++ {
+		b.Inserted(newsList("a"))
+		<-fast.C
+		received++
+	}
+
+	// The buffer of the slow subscriber is full, it is closed after its queued events.
+	var queued int
+	for range slow.C {
+		queued++
+	}
+	if queued != subscriberBuffer || received != subscriberBuffer+1 {
+		t.Errorf("got %d queued and %d received, expected %d and %d", queued, received, subscriberBuffer, subscriberBuffer+1)
+	}
+
+	// The slow subscriber resumes from its last event.
+	missed, s := b.Subscribe(uint64(queued))
+	if got := eventIds(missed); len(got) != 1 || got[0] != subscriberBuffer+1 {
+		t.Errorf("got %v", got)
+	}
+	b.Unsubscribe(s)
+
+	// Unsubscribe does not close it twice.
+	b.Unsubscribe(slow)
+	b.Unsubscribe(fast)
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/rpc/newspb"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toNews returns the message of the news with only the fields, see store.ParseFields.
+// The fields the store read for the filters are left out, as the HTTP API does.
+func toNews(n *model.News, fields []string) *newspb.News {
+	selected := store.Filter{Fields: fields}
+
+	m := &newspb.News{Id: n.Id}
+
+	if selected.Selects("author") {
+		m.Author = n.Author
+	}
+	if selected.Selects("datetime") {
+		m.Datetime = timestamppb.New(n.Datetime)
+	}
+	if selected.Selects("title") {
+		m.Title = n.Title
+	}
+	if selected.Selects("location") {
+		m.Location = n.Location
+	}
+	if selected.Selects("content") {
+		m.Content = n.Content
+	}
+	if selected.Selects("pictures") {
+		for _, p := range n.Pictures {
+			m.Pictures = append(m.Pictures, &newspb.Picture{
+				Url:      p.ImageUrl,
+				Caption:  p.Caption,
+				Hash:     p.Hash,
+				Width:    int32(p.Width),
+				Height:   int32(p.Height),
+				MimeType: p.MimeType,
+			})
+		}
+	}
+	if selected.Selects("tags") {
+		m.Tags = n.Tags
+	}
+	if selected.Selects("url") {
+		m.Url = n.Url
+	}
+	m.Source = toNewsSource(&n.Source, selected)
+	if selected.Selects("paragraphs") {
+		m.Paragraphs = n.Paragraphs
+	}
+	if selected.Selects("html") {
+		m.Html = n.Html
+	}
+	if selected.Selects("lead") {
+		m.Lead = n.Lead
+	}
+	if selected.Selects("summary") {
+		m.Summary = n.Summary
+	}
+	if selected.Selects("language") {
+		m.Language = n.Language
+	}
+	if selected.Selects("cluster_id") {
+		m.ClusterId = n.ClusterId
+	}
+	if selected.Selects("entities") {
+		for _, e := range n.Entities {
+			m.Entities = append(m.Entities, &newspb.Entity{Id: e.Id, Type: e.Type, Name: e.Name, Count: int32(e.Count)})
+		}
+	}
+	if selected.Selects("annotations") {
+		m.Annotations = n.Annotations
+	}
+
+	return m
+}
+
+// toNewsSource returns nil if no field of the source is selected.
+func toNewsSource(s *model.NewsSource, selected store.Filter) *newspb.NewsSource {
+	var m *newspb.NewsSource
+	set := func(field string, fn func(m *newspb.NewsSource)) {
+		if selected.Selects("source." + field) {
+			if m == nil {
+				m = &newspb.NewsSource{}
+			}
+			fn(m)
+		}
+	}
+
+	set("name", func(m *newspb.NewsSource) { m.Name = s.NewspaperName })
+	set("id", func(m *newspb.NewsSource) { m.Id = s.NewspaperId })
+	set("category", func(m *newspb.NewsSource) { m.Category = s.OriginalCategory })
+	set("subcategory", func(m *newspb.NewsSource) { m.Subcategory = s.OriginalSubcategory })
+	set("tags", func(m *newspb.NewsSource) { m.Tags = s.Tags })
+	set("url", func(m *newspb.NewsSource) { m.Url = s.Url })
+
+	return m
+}
@@ -0,0 +1,212 @@
+package rpc
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/pubsub"
+	"github.com/ahmadmuzakkir/scrapenews/rpc/newspb"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errServer = status.Error(codes.Internal, "Server error")
+
+// NewsService implements newspb.NewsServiceServer over the store, and the broker of the inserted news.
+type NewsService struct {
+	newspb.UnimplementedNewsServiceServer
+
+	Logger    *log.Logger
+	newsStore store.NewsStore
+	broker    *pubsub.Broker
+}
+
+func NewNewsService(newsStore store.NewsStore, broker *pubsub.Broker) *NewsService {
+	return &NewsService{
+		Logger:    log.New(os.Stderr, "", log.LstdFlags),
+		newsStore: newsStore,
+		broker:    broker,
+	}
+}
+
+func (s *NewsService) logError(format string, v ...interface{}) {
+	s.Logger.Printf("ERROR: "+format, v...)
+}
+
+func (s *NewsService) GetNews(ctx context.Context, req *newspb.GetNewsRequest) (*newspb.News, error) {
+	fields, err := store.ParseFields(req.Fields)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	news, err := s.newsStore.Get(req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "News not found")
+	}
+	if err != nil {
+		s.logError("rpc get: %s", err)
+		return nil, errServer
+	}
+
+	return toNews(news, fields), nil
+}
+
+func (s *NewsService) ListNews(req *newspb.ListNewsRequest, stream newspb.NewsService_ListNewsServer) error {
+	fields, err := store.ParseFields(req.Fields)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := newFilter(req.From, req.Until, req.Language, req.Entity, req.EntityType)
+	filter.Fields = append(fields, "cluster_id")
+
+	list, err := s.newsStore.GetAll(filter)
+	if err != nil {
+		s.logError("rpc list: %s", err)
+		return errServer
+	}
+
+	if req.Dedupe {
+		list = store.Dedupe(list)
+	}
+
+	for _, n := range list {
+		if err := stream.Send(toNews(n, fields)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *NewsService) Search(ctx context.Context, req *newspb.SearchRequest) (*newspb.SearchResponse, error) {
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "The query is required")
+	}
+
+	fields, err := store.ParseFields(req.Fields)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The words are matched against the title and the content.
+	filter := newFilter(req.From, req.Until, req.Language, req.Entity, req.EntityType)
+	filter.Fields = append(fields, "cluster_id", "title", "content", "language")
+
+	list, err := s.newsStore.GetAll(filter)
+	if err != nil {
+		s.logError("rpc search: %s", err)
+		return nil, errServer
+	}
+
+	var result []*model.News
+	for _, n := range list {
+		if matches(n, req.Query) {
+			result = append(result, n)
+		}
+	}
+
+	if req.Dedupe {
+		result = store.Dedupe(result)
+	}
+
+	response := &newspb.SearchResponse{}
+	for _, n := range result {
+		response.News = append(response.News, toNews(n, fields))
+	}
+	return response, nil
+}
+
+// WatchNews resumes after the last event id of the request, if the events are still kept.
+func (s *NewsService) WatchNews(req *newspb.WatchNewsRequest, stream newspb.NewsService_WatchNewsServer) error {
+	fields, err := store.ParseFields(req.Fields)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := store.Filter{Language: req.Language, Entity: req.Entity, EntityType: req.EntityType}
+
+	match := func(n *model.News) bool {
+		if len(req.Newspapers) > 0 && !contains(req.Newspapers, n.Source.NewspaperId) {
+			return false
+		}
+		if req.Query != "" && !matches(n, req.Query) {
+			return false
+		}
+		return filter.Match(n)
+	}
+
+	missed, sub := s.broker.Subscribe(req.LastEventId)
+	defer s.broker.Unsubscribe(sub)
+
+	// The headers tell the client it is subscribed, before the first event.
+	if err := stream.SendHeader(nil); err != nil {
+		return err
+	}
+
+	send := func(e pubsub.Event) error {
+		if !match(e.News) {
+			return nil
+		}
+		return stream.Send(&newspb.NewsEvent{Id: e.Id, News: toNews(e.News, fields)})
+	}
+
+	for _, e := range missed {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case e, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "The client did not keep up, resume from the last event id")
+			}
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// newFilter returns the filter of the request. from defaults to now and until to a day ago, as the HTTP API.
+func newFilter(from, until *timestamppb.Timestamp, lang, entity, entityType string) store.Filter {
+	filter := store.Filter{
+		From:       time.Now(),
+		Until:      time.Now().AddDate(0, 0, -1),
+		Language:   lang,
+		Entity:     entity,
+		EntityType: entityType,
+	}
+
+	if from != nil {
+		filter.From = from.AsTime()
+	}
+	if until != nil {
+		filter.Until = until.AsTime()
+	}
+
+	return filter
+}
+
+func matches(n *model.News, query string) bool {
+	return language.Contains(n.Title+"\n"+n.Content, query, n.Language)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1059 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: rpc/newspb/news.proto
+
+// The news over gRPC, the messages mirror model.News, model.NewsSource and model.Picture.
+// The calls are authenticated as the HTTP API, by an API key or a JWT in the authorization metadata.
+
+package newspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type News struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author        string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Datetime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Location      string                 `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Content       string                 `protobuf:"bytes,6,opt,name=content,proto3" json:"content,omitempty"`
+	Pictures      []*Picture             `protobuf:"bytes,7,rep,name=pictures,proto3" json:"pictures,omitempty"`
+	Tags          []string               `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	Url           string                 `protobuf:"bytes,9,opt,name=url,proto3" json:"url,omitempty"`
+	Source        *NewsSource            `protobuf:"bytes,10,opt,name=source,proto3" json:"source,omitempty"`
+	Paragraphs    []string               `protobuf:"bytes,11,rep,name=paragraphs,proto3" json:"paragraphs,omitempty"`
+	Html          string                 `protobuf:"bytes,12,opt,name=html,proto3" json:"html,omitempty"`
+	Lead          string                 `protobuf:"bytes,13,opt,name=lead,proto3" json:"lead,omitempty"`
+	Summary       string                 `protobuf:"bytes,14,opt,name=summary,proto3" json:"summary,omitempty"`
+	Language      string                 `protobuf:"bytes,15,opt,name=language,proto3" json:"language,omitempty"`
+	ClusterId     string                 `protobuf:"bytes,16,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	Entities      []*Entity              `protobuf:"bytes,17,rep,name=entities,proto3" json:"entities,omitempty"`
+	Annotations   map[string]string      `protobuf:"bytes,18,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *News) Reset() {
+	*x = News{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *News) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*News) ProtoMessage() {}
+
+func (x *News) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use News.ProtoReflect.Descriptor instead.
+func (*News) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *News) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *News) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *News) GetDatetime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Datetime
+	}
+	return nil
+}
+
+func (x *News) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *News) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *News) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *News) GetPictures() []*Picture {
+	if x != nil {
+		return x.Pictures
+	}
+	return nil
+}
+
+func (x *News) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *News) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *News) GetSource() *NewsSource {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *News) GetParagraphs() []string {
+	if x != nil {
+		return x.Paragraphs
+	}
+	return nil
+}
+
+func (x *News) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *News) GetLead() string {
+	if x != nil {
+		return x.Lead
+	}
+	return ""
+}
+
+func (x *News) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *News) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *News) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *News) GetEntities() []*Entity {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *News) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+type NewsSource struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Category      string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Subcategory   string                 `protobuf:"bytes,4,opt,name=subcategory,proto3" json:"subcategory,omitempty"`
+	Tags          []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsSource) Reset() {
+	*x = NewsSource{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsSource) ProtoMessage() {}
+
+func (x *NewsSource) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsSource.ProtoReflect.Descriptor instead.
+func (*NewsSource) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NewsSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NewsSource) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NewsSource) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *NewsSource) GetSubcategory() string {
+	if x != nil {
+		return x.Subcategory
+	}
+	return ""
+}
+
+func (x *NewsSource) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *NewsSource) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type Picture struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Url     string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Caption string                 `protobuf:"bytes,2,opt,name=caption,proto3" json:"caption,omitempty"`
+	// Set when the picture is mirrored locally.
+	Hash          string `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Width         int32  `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32  `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	MimeType      string `protobuf:"bytes,6,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Picture) Reset() {
+	*x = Picture{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Picture) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Picture) ProtoMessage() {}
+
+func (x *Picture) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Picture.ProtoReflect.Descriptor instead.
+func (*Picture) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Picture) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Picture) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+func (x *Picture) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Picture) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Picture) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Picture) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+type Entity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Entity) Reset() {
+	*x = Entity{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entity) ProtoMessage() {}
+
+func (x *Entity) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entity.ProtoReflect.Descriptor instead.
+func (*Entity) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Entity) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Entity) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Entity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Entity) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetNewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The fields of the news returned, by their JSON name as the fields parameter of the HTTP API,
+	// e.g title and source.name. Every field but the full bodies if empty, * stands for them.
+	Fields        []string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNewsRequest) Reset() {
+	*x = GetNewsRequest{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNewsRequest) ProtoMessage() {}
+
+func (x *GetNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNewsRequest.ProtoReflect.Descriptor instead.
+func (*GetNewsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetNewsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetNewsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ListNewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// from is the latest datetime and defaults to now, until is the earliest and defaults to a day ago.
+	From     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Until    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	Language string                 `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	// entity is the id of an entity mentioned, entity_type the type of any entity mentioned.
+	Entity     string `protobuf:"bytes,4,opt,name=entity,proto3" json:"entity,omitempty"`
+	EntityType string `protobuf:"bytes,5,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	// dedupe keeps the first news of every cluster of near-duplicates.
+	Dedupe bool `protobuf:"varint,6,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	// See GetNewsRequest.fields.
+	Fields        []string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNewsRequest) Reset() {
+	*x = ListNewsRequest{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNewsRequest) ProtoMessage() {}
+
+func (x *ListNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNewsRequest.ProtoReflect.Descriptor instead.
+func (*ListNewsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListNewsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListNewsRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *ListNewsRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ListNewsRequest) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+func (x *ListNewsRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *ListNewsRequest) GetDedupe() bool {
+	if x != nil {
+		return x.Dedupe
+	}
+	return false
+}
+
+func (x *ListNewsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// The news searched, see ListNewsRequest.
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	Language      string                 `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	Entity        string                 `protobuf:"bytes,5,opt,name=entity,proto3" json:"entity,omitempty"`
+	EntityType    string                 `protobuf:"bytes,6,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Dedupe        bool                   `protobuf:"varint,7,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	Fields        []string               `protobuf:"bytes,8,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDedupe() bool {
+	if x != nil {
+		return x.Dedupe
+	}
+	return false
+}
+
+func (x *SearchRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	News          []*News                `protobuf:"bytes,1,rep,name=news,proto3" json:"news,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchResponse) GetNews() []*News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+type WatchNewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ids of the newspapers, every newspaper if empty.
+	Newspapers []string `protobuf:"bytes,1,rep,name=newspapers,proto3" json:"newspapers,omitempty"`
+	Language   string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Entity     string   `protobuf:"bytes,3,opt,name=entity,proto3" json:"entity,omitempty"`
+	EntityType string   `protobuf:"bytes,4,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	// query selects the news containing every word, as SearchRequest.query.
+	Query string `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`
+	// last_event_id resumes after the event, if it is still kept.
+	LastEventId uint64 `protobuf:"varint,6,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	// See GetNewsRequest.fields.
+	Fields        []string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchNewsRequest) Reset() {
+	*x = WatchNewsRequest{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNewsRequest) ProtoMessage() {}
+
+func (x *WatchNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNewsRequest.ProtoReflect.Descriptor instead.
+func (*WatchNewsRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchNewsRequest) GetNewspapers() []string {
+	if x != nil {
+		return x.Newspapers
+	}
+	return nil
+}
+
+func (x *WatchNewsRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetEntity() string {
+	if x != nil {
+		return x.Entity
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+func (x *WatchNewsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type NewsEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	News          *News                  `protobuf:"bytes,2,opt,name=news,proto3" json:"news,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsEvent) Reset() {
+	*x = NewsEvent{}
+	mi := &file_rpc_newspb_news_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsEvent) ProtoMessage() {}
+
+func (x *NewsEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_newspb_news_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsEvent.ProtoReflect.Descriptor instead.
+func (*NewsEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_newspb_news_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NewsEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NewsEvent) GetNews() *News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+var File_rpc_newspb_news_proto protoreflect.FileDescriptor
+
+const file_rpc_newspb_news_proto_rawDesc = "" +
+	"\n" +
+	"\x15rpc/newspb/news.proto\x12\rscrapenews.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x97\x05\n" +
+	"\x04News\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x126\n" +
+	"\bdatetime\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x1a\n" +
+	"\blocation\x18\x05 \x01(\tR\blocation\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\tR\acontent\x122\n" +
+	"\bpictures\x18\a \x03(\v2\x16.scrapenews.v1.PictureR\bpictures\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x10\n" +
+	"\x03url\x18\t \x01(\tR\x03url\x121\n" +
+	"\x06source\x18\n" +
+	" \x01(\v2\x19.scrapenews.v1.NewsSourceR\x06source\x12\x1e\n" +
+	"\n" +
+	"paragraphs\x18\v \x03(\tR\n" +
+	"paragraphs\x12\x12\n" +
+	"\x04html\x18\f \x01(\tR\x04html\x12\x12\n" +
+	"\x04lead\x18\r \x01(\tR\x04lead\x12\x18\n" +
+	"\asummary\x18\x0e \x01(\tR\asummary\x12\x1a\n" +
+	"\blanguage\x18\x0f \x01(\tR\blanguage\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x10 \x01(\tR\tclusterId\x121\n" +
+	"\bentities\x18\x11 \x03(\v2\x15.scrapenews.v1.EntityR\bentities\x12F\n" +
+	"\vannotations\x18\x12 \x03(\v2$.scrapenews.v1.News.AnnotationsEntryR\vannotations\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x94\x01\n" +
+	"\n" +
+	"NewsSource\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12 \n" +
+	"\vsubcategory\x18\x04 \x01(\tR\vsubcategory\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\"\x94\x01\n" +
+	"\aPicture\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x18\n" +
+	"\acaption\x18\x02 \x01(\tR\acaption\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\x12\x14\n" +
+	"\x05width\x18\x04 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x05 \x01(\x05R\x06height\x12\x1b\n" +
+	"\tmime_type\x18\x06 \x01(\tR\bmimeType\"V\n" +
+	"\x06Entity\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"8\n" +
+	"\x0eGetNewsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\"\xf8\x01\n" +
+	"\x0fListNewsRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x120\n" +
+	"\x05until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06entity\x18\x04 \x01(\tR\x06entity\x12\x1f\n" +
+	"\ventity_type\x18\x05 \x01(\tR\n" +
+	"entityType\x12\x16\n" +
+	"\x06dedupe\x18\x06 \x01(\bR\x06dedupe\x12\x16\n" +
+	"\x06fields\x18\a \x03(\tR\x06fields\"\x8c\x02\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x120\n" +
+	"\x05until\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1a\n" +
+	"\blanguage\x18\x04 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06entity\x18\x05 \x01(\tR\x06entity\x12\x1f\n" +
+	"\ventity_type\x18\x06 \x01(\tR\n" +
+	"entityType\x12\x16\n" +
+	"\x06dedupe\x18\a \x01(\bR\x06dedupe\x12\x16\n" +
+	"\x06fields\x18\b \x03(\tR\x06fields\"9\n" +
+	"\x0eSearchResponse\x12'\n" +
+	"\x04news\x18\x01 \x03(\v2\x13.scrapenews.v1.NewsR\x04news\"\xd9\x01\n" +
+	"\x10WatchNewsRequest\x12\x1e\n" +
+	"\n" +
+	"newspapers\x18\x01 \x03(\tR\n" +
+	"newspapers\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12\x16\n" +
+	"\x06entity\x18\x03 \x01(\tR\x06entity\x12\x1f\n" +
+	"\ventity_type\x18\x04 \x01(\tR\n" +
+	"entityType\x12\x14\n" +
+	"\x05query\x18\x05 \x01(\tR\x05query\x12\"\n" +
+	"\rlast_event_id\x18\x06 \x01(\x04R\vlastEventId\x12\x16\n" +
+	"\x06fields\x18\a \x03(\tR\x06fields\"D\n" +
+	"\tNewsEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x04news\x18\x02 \x01(\v2\x13.scrapenews.v1.NewsR\x04news2\xa0\x02\n" +
+	"\vNewsService\x12=\n" +
+	"\aGetNews\x12\x1d.scrapenews.v1.GetNewsRequest\x1a\x13.scrapenews.v1.News\x12A\n" +
+	"\bListNews\x12\x1e.scrapenews.v1.ListNewsRequest\x1a\x13.scrapenews.v1.News0\x01\x12E\n" +
+	"\x06Search\x12\x1c.scrapenews.v1.SearchRequest\x1a\x1d.scrapenews.v1.SearchResponse\x12H\n" +
+	"\tWatchNews\x12\x1f.scrapenews.v1.WatchNewsRequest\x1a\x18.scrapenews.v1.NewsEvent0\x01B0Z.github.com/ahmadmuzakkir/scrapenews/rpc/newspbb\x06proto3"
+
+var (
+	file_rpc_newspb_news_proto_rawDescOnce sync.Once
+	file_rpc_newspb_news_proto_rawDescData []byte
+)
+
+func file_rpc_newspb_news_proto_rawDescGZIP() []byte {
+	file_rpc_newspb_news_proto_rawDescOnce.Do(func() {
+		file_rpc_newspb_news_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_newspb_news_proto_rawDesc), len(file_rpc_newspb_news_proto_rawDesc)))
+	})
+	return file_rpc_newspb_news_proto_rawDescData
+}
+
+var file_rpc_newspb_news_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_rpc_newspb_news_proto_goTypes = []any{
+	(*News)(nil),                  // 0: scrapenews.v1.News
+	(*NewsSource)(nil),            // 1: scrapenews.v1.NewsSource
+	(*Picture)(nil),               // 2: scrapenews.v1.Picture
+	(*Entity)(nil),                // 3: scrapenews.v1.Entity
+	(*GetNewsRequest)(nil),        // 4: scrapenews.v1.GetNewsRequest
+	(*ListNewsRequest)(nil),       // 5: scrapenews.v1.ListNewsRequest
+	(*SearchRequest)(nil),         // 6: scrapenews.v1.SearchRequest
+	(*SearchResponse)(nil),        // 7: scrapenews.v1.SearchResponse
+	(*WatchNewsRequest)(nil),      // 8: scrapenews.v1.WatchNewsRequest
+	(*NewsEvent)(nil),             // 9: scrapenews.v1.NewsEvent
+	nil,                           // 10: scrapenews.v1.News.AnnotationsEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+}
+var file_rpc_newspb_news_proto_depIdxs = []int32{
+	11, // 0: scrapenews.v1.News.datetime:type_name -> google.protobuf.Timestamp
+	2,  // 1: scrapenews.v1.News.pictures:type_name -> scrapenews.v1.Picture
+	1,  // 2: scrapenews.v1.News.source:type_name -> scrapenews.v1.NewsSource
+	3,  // 3: scrapenews.v1.News.entities:type_name -> scrapenews.v1.Entity
+	10, // 4: scrapenews.v1.News.annotations:type_name -> scrapenews.v1.News.AnnotationsEntry
+	11, // 5: scrapenews.v1.ListNewsRequest.from:type_name -> google.protobuf.Timestamp
+	11, // 6: scrapenews.v1.ListNewsRequest.until:type_name -> google.protobuf.Timestamp
+	11, // 7: scrapenews.v1.SearchRequest.from:type_name -> google.protobuf.Timestamp
+	11, // 8: scrapenews.v1.SearchRequest.until:type_name -> google.protobuf.Timestamp
+	0,  // 9: scrapenews.v1.SearchResponse.news:type_name -> scrapenews.v1.News
+	0,  // 10: scrapenews.v1.NewsEvent.news:type_name -> scrapenews.v1.News
+	4,  // 11: scrapenews.v1.NewsService.GetNews:input_type -> scrapenews.v1.GetNewsRequest
+	5,  // 12: scrapenews.v1.NewsService.ListNews:input_type -> scrapenews.v1.ListNewsRequest
+	6,  // 13: scrapenews.v1.NewsService.Search:input_type -> scrapenews.v1.SearchRequest
+	8,  // 14: scrapenews.v1.NewsService.WatchNews:input_type -> scrapenews.v1.WatchNewsRequest
+	0,  // 15: scrapenews.v1.NewsService.GetNews:output_type -> scrapenews.v1.News
+	0,  // 16: scrapenews.v1.NewsService.ListNews:output_type -> scrapenews.v1.News
+	7,  // 17: scrapenews.v1.NewsService.Search:output_type -> scrapenews.v1.SearchResponse
+	9,  // 18: scrapenews.v1.NewsService.WatchNews:output_type -> scrapenews.v1.NewsEvent
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_rpc_newspb_news_proto_init() }
+func file_rpc_newspb_news_proto_init() {
+	if File_rpc_newspb_news_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_newspb_news_proto_rawDesc), len(file_rpc_newspb_news_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_newspb_news_proto_goTypes,
+		DependencyIndexes: file_rpc_newspb_news_proto_depIdxs,
+		MessageInfos:      file_rpc_newspb_news_proto_msgTypes,
+	}.Build()
+	File_rpc_newspb_news_proto = out.File
+	file_rpc_newspb_news_proto_goTypes = nil
+	file_rpc_newspb_news_proto_depIdxs = nil
+}
@@ -0,0 +1,100 @@
+// Package rpc serves the news over gRPC, alongside the HTTP API. The service is defined by newspb/news.proto,
+// its calls are authenticated and rate limited as the HTTP API.
+package rpc
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative rpc/newspb/news.proto
+
+import (
+	"context"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/rpc/newspb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// scopes are the scopes required by the methods, the read scope if they are not listed.
+// WatchNews streams the news out as GET /stream.
+var scopes = map[string]string{
+	newspb.NewsService_WatchNews_FullMethodName: model.ScopeExport,
+}
+
+// NewServer returns the gRPC server of the news service, its calls are authorized by the authenticator.
+func NewServer(service *NewsService, authenticator *auth.Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryAuthorizer(authenticator)),
+		grpc.ChainStreamInterceptor(streamAuthorizer(authenticator)),
+	)
+
+	s := grpc.NewServer(opts...)
+	newspb.RegisterNewsServiceServer(s, service)
+	return s
+}
+
+func unaryAuthorizer(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthorizer(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorizedStream is a stream along with the client of the call, see auth.FromContext.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize authorizes the token of the authorization metadata, and returns the context along with the client.
+// The errors are the statuses of the errors of the HTTP API: UNAUTHENTICATED, RESOURCE_EXHAUSTED and PERMISSION_DENIED.
+func authorize(ctx context.Context, a *auth.Authenticator, method string) (context.Context, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = auth.BearerToken(values[0])
+		}
+	}
+
+	scope, exist := scopes[method]
+	if !exist {
+		scope = model.ScopeRead
+	}
+
+	p, err := a.Authorize(token, scope)
+	switch err.(type) {
+	case nil:
+	case *auth.ScopeError:
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	default:
+		if err == auth.ErrRateLimited {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if p != nil {
+		ctx = auth.NewContext(ctx, p)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,250 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/auth"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/pubsub"
+	"github.com/ahmadmuzakkir/scrapenews/rpc/newspb"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const secret = "secret"
+
+func newTestClient(t *testing.T) (newspb.NewsServiceClient, *storetest.Memory, *pubsub.Broker) {
+	now := time.Now()
+
+	s := &storetest.Memory{}
+	for i := 0; i < 4; i++ {
+		n := &model.News{
+			Id:        fmt.Sprintf("n%d", i),
+			Title:     fmt.Sprintf("Banjir %d", i),
+			Content:   "Kandungan",
+			Datetime:  now.Add(-time.Duration(i) * time.Hour),
+			Language:  "ms",
+			Source:    model.BhSources[0],
+			ClusterId: fmt.Sprintf("c%d", i/2),
+		}
+		if i == 3 {
+			n.Title = "Politik"
+		}
+		s.News = append(s.News, n)
+	}
+
+	jwt, err := auth.NewJWT("", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwt.Audience = "scrapenews"
+
+	broker := pubsub.NewBroker(10)
+	service := NewNewsService(s, broker)
+	service.Logger = log.New(ioutil.Discard, "", 0)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(service, auth.NewAuthenticator(nil, jwt, 600))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return newspb.NewNewsServiceClient(conn), s, broker
+}
+
+// withToken returns the context of the calls authenticated by a JWT of the scope.
+func withToken(scope string) context.Context {
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	token := encode(map[string]string{"alg": "HS256"}) + "." +
+		encode(map[string]interface{}{"sub": "test", "aud": "scrapenews", "scope": scope, "exp": time.Now().Add(time.Hour).Unix()})
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	token += "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestAuthorization(t *testing.T) {
+	client, _, _ := newTestClient(t)
+
+	tests := []struct {
+		ctx  context.Context
+		call func(ctx context.Context) error
+		code codes.Code
+	}{
+		{context.Background(), func(ctx context.Context) error {
+			_, err := client.GetNews(ctx, &newspb.GetNewsRequest{Id: "n0"})
+			return err
+		}, codes.Unauthenticated},
+		{withToken("read"), func(ctx context.Context) error {
+			_, err := client.GetNews(ctx, &newspb.GetNewsRequest{Id: "n0"})
+			return err
+		}, codes.OK},
+		{withToken("read"), func(ctx context.Context) error {
+			_, err := client.GetNews(ctx, &newspb.GetNewsRequest{Id: "unknown"})
+			return err
+		}, codes.NotFound},
+		{withToken("read"), func(ctx context.Context) error {
+			stream, err := client.WatchNews(ctx, &newspb.WatchNewsRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.PermissionDenied},
+		{withToken("export"), func(ctx context.Context) error {
+			stream, err := client.ListNews(ctx, &newspb.ListNewsRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = stream.Recv()
+			return err
+		}, codes.PermissionDenied},
+	}
+
+	for i, test := range tests {
+		if code := status.Code(test.call(test.ctx)); code != test.code {
+			t.Errorf("%d: got %s, expected %s", i, code, test.code)
+		}
+	}
+}
+
+func TestListNews(t *testing.T) {
+	client, mem, _ := newTestClient(t)
+	ctx := withToken("read")
+
+	stream, err := client.ListNews(ctx, &newspb.ListNewsRequest{
+		Until:  timestamppb.New(time.Now().Add(-150 * time.Minute)),
+		Dedupe: true,
+		Fields: []string{"title", "source.id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*newspb.News
+	for {
+		n, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n)
+	}
+
+	// n1 is a near-duplicate of n0, n3 is too old.
+	if len(got) != 2 || got[0].Id != "n0" || got[1].Id != "n2" {
+		t.Fatalf("got %v", got)
+	}
+
+	// Only the fields are returned, the cluster id is read for the dedupe.
+	expected := &newspb.News{Id: "n0", Title: "Banjir 0", Source: &newspb.NewsSource{Id: model.BharianId}}
+	if !proto.Equal(got[0], expected) {
+		t.Errorf("got %s, expected %s", got[0], expected)
+	}
+	if fields := mem.Filter.Fields; !reflect.DeepEqual(fields, []string{"id", "title", "source.id", "cluster_id"}) {
+		t.Errorf("got fields %v", fields)
+	}
+
+	stream, err = client.ListNews(ctx, &newspb.ListNewsRequest{Fields: []string{"unknown"}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, expected an invalid argument", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	client, _, _ := newTestClient(t)
+	ctx := withToken("read")
+
+	resp, err := client.Search(ctx, &newspb.SearchRequest{Query: "banjir"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, n := range resp.News {
+		ids = append(ids, n.Id)
+		if n.Content != "" {
+			t.Errorf("%s: the content is returned", n.Id)
+		}
+	}
+	if !reflect.DeepEqual(ids, []string{"n0", "n1", "n2"}) {
+		t.Errorf("got %v", ids)
+	}
+
+	if _, err := client.Search(ctx, &newspb.SearchRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, expected an invalid argument", err)
+	}
+}
+
+func TestWatchNews(t *testing.T) {
+	client, mem, broker := newTestClient(t)
+
+	// The first event is missed, then resumed from.
+	broker.Inserted(mem.News[:1])
+
+	ctx, cancel := context.WithCancel(withToken("export"))
+	defer cancel()
+
+	stream, err := client.WatchNews(ctx, &newspb.WatchNewsRequest{Newspapers: []string{model.BharianId}, Query: "banjir", LastEventId: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the subscription, the headers are sent after it.
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	broker.Inserted(mem.News[1:])
+
+	var ids []string
+	for len(ids) < 2 {
+		e, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, fmt.Sprintf("%d:%s", e.Id, e.News.Id))
+	}
+
+	// n3 does not match the query.
+	if !reflect.DeepEqual(ids, []string{"2:n1", "3:n2"}) {
+		t.Errorf("got %v", ids)
+	}
+}
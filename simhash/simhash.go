@@ -0,0 +1,61 @@
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// The number of consecutive words in a feature.
+// Word pairs are less sensitive to common words than single words, and less sensitive to small edits than longer shingles.
+const shingleSize = 2
+
+// Hash returns the 64-bit SimHash of the text. Near-duplicate texts have hashes with a small hamming distance.
+func Hash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	if len(words) == 0 {
+		return 0
+	}
+
+	var weights [64]int
+
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		for i := uint(0); i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	if len(words) < shingleSize {
+		add(strings.Join(words, " "))
+	}
+
+	for i := 0; i+shingleSize <= len(words); i++ {
+		add(strings.Join(words[i:i+shingleSize], " "))
+	}
+
+	var hash uint64
+	for i := uint(0); i < 64; i++ {
+		if weights[i] > 0 {
+			hash |= 1 << i
+		}
+	}
+
+	return hash
+}
+
+// Distance returns the number of bits that differ between the hashes.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,61 @@
+package simhash
+
+import (
+	"strings"
+	"testing"
+)
+
+const wire = `KUALA LUMPUR: The government will allocate an additional RM2 billion for education, health and housing next year, the finance minister said today.
+He said the allocation would be distributed to all states, especially to the rural areas which still lack basic facilities such as schools and clinics.
+The announcement was welcomed by the teachers union and the medical association, which had asked for more funds since last year.`
+
+func TestHash(t *testing.T) {
+	if Hash("") != 0 || Hash(" ,.! ") != 0 {
+		t.Error("got a hash of no word")
+	}
+	if Hash("banjir") == 0 {
+		t.Error("got no hash of a single word")
+	}
+
+	// The case and the punctuation are ignored.
+	if Hash(wire) != Hash("  "+strings.ToUpper(wire)+"!!") {
+		t.Error("got a different hash of the same words")
+	}
+}
+
+func TestNearDuplicates(t *testing.T) {
+	// The same wire story, lightly edited by another newspaper.
+	edited := `KUALA LUMPUR: The government will allocate an extra RM2 billion for education, health and housing next year, Finance Minister said today.
+He said the allocation would be distributed to all states, especially to the rural areas which still lack basic facilities such as schools and clinics.
+The announcement was welcomed by the teachers union and the medical association, which had asked for more funds since last year. - Bernama`
+
+	unrelated := `JOHOR BAHRU: Two men were arrested after a car chase that ended with a crash near the Causeway early this morning.
+Police said the suspects were believed to be involved in a series of break-ins at several shops in the city centre last month.
+Both of them will be remanded for a week to assist in the investigation, while the car was seized for further inspection.`
+
+	if d := Distance(Hash(wire), Hash(edited)); d > 10 {
+		t.Errorf("got a distance of %d between the near-duplicates", d)
+	}
+	if d := Distance(Hash(wire), Hash(unrelated)); d <= 10 {
+		t.Errorf("got a distance of %d between the unrelated texts", d)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     uint64
+		expected int
+	}{
+		{0, 0, 0},
+		{0xff, 0xff, 0},
+		{0, 1, 1},
+		{0xf0, 0x0f, 8},
+		{0, ^uint64(0), 64},
+	}
+
+	for _, test := range tests {
+		if got := Distance(test.a, test.b); got != test.expected {
+			t.Errorf("Distance(%x, %x): got %d, expected %d", test.a, test.b, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveApiKey(key *model.ApiKey) error {
+	return s.updateApiKey(key.Id, func(existing *model.ApiKey) (*model.ApiKey, error) {
+		v := *key
+		if existing != nil {
+			v.Requests = existing.Requests
+			v.LastUsed = existing.LastUsed
+		}
+		return &v, nil
+	})
+}
+
+func (s *Store) GetApiKeys() ([]*model.ApiKey, error) {
+	var list = make([]*model.ApiKey, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(apiKeysBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			key := &model.ApiKey{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(key); err != nil {
+				return nil
+			}
+
+			list = append(list, key)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+
+	return list, nil
+}
+
+func (s *Store) GetApiKey(id string) (*model.ApiKey, error) {
+	var key *model.ApiKey
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(apiKeysBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return store.ErrNotFound
+		}
+
+		key = &model.ApiKey{}
+		return gob.NewDecoder(bytes.NewBuffer(v)).Decode(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (s *Store) AddApiKeyUsage(id string, requests int64, lastUsed time.Time) error {
+	return s.updateApiKey(id, func(existing *model.ApiKey) (*model.ApiKey, error) {
+		if existing == nil {
+			return nil, nil
+		}
+
+		existing.Requests += requests
+		existing.LastUsed = &lastUsed
+		return existing, nil
+	})
+}
+
+// updateApiKey replaces the API key by the result of update, existing is nil if the key does not exist.
+// Nothing is saved if update returns nil.
+func (s *Store) updateApiKey(id string, update func(existing *model.ApiKey) (*model.ApiKey, error)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(apiKeysBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		var existing *model.ApiKey
+		if v := b.Get([]byte(id)); v != nil {
+			existing = &model.ApiKey{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(existing); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Decode() error")
+			}
+		}
+
+		key, err := update(existing)
+		if err != nil || key == nil {
+			return err
+		}
+
+		buf := &bytes.Buffer{}
+		if err := gob.NewEncoder(buf).Encode(key); err != nil {
+			return errors.Wrap(err, "[boltdb] gob.Encode() error")
+		}
+
+		return b.Put([]byte(id), buf.Bytes())
+	})
+}
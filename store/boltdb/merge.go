@@ -0,0 +1,195 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) MergeNews(merges []*store.Merge) error {
+	if len(merges) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		// The new cluster ids, by the ids of the news moved or deleted.
+		var clusterIds = make(map[string]string)
+
+		for _, m := range merges {
+			for _, id := range m.Duplicates {
+				if err := b.Delete([]byte(id)); err != nil {
+					return errors.Wrap(err, "[boltdb] MergeNews() Delete error")
+				}
+				clusterIds[id] = m.Id
+			}
+
+			from := m.Id
+			if m.From != "" {
+				from = m.From
+				clusterIds[from] = m.Id
+			}
+
+			v := b.Get([]byte(from))
+			if v == nil {
+				continue
+			}
+
+			n := &model.News{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(n); err != nil {
+				return errors.Wrap(err, "[boltdb] MergeNews() gob.Decode() error")
+			}
+			n.Id = m.Id
+			n.Url = m.Url
+
+			if err := b.Delete([]byte(from)); err != nil {
+				return errors.Wrap(err, "[boltdb] MergeNews() Delete error")
+			}
+			if err := s.put(b, n); err != nil {
+				return err
+			}
+		}
+
+		if len(clusterIds) == 0 {
+			return nil
+		}
+
+		// The stories, the deliveries and the alerts keep referring to the news.
+		if err := rewriteReferences(tx, clusterIds); err != nil {
+			return err
+		}
+
+		var moved []*model.News
+		err := b.ForEach(func(k, v []byte) error {
+			n := &model.News{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(n); err != nil {
+				return errors.Wrap(err, "[boltdb] MergeNews() gob.Decode() error")
+			}
+			if id, exist := clusterIds[n.ClusterId]; exist {
+				n.ClusterId = id
+				moved = append(moved, n)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The bucket is not modified while it is iterated.
+		for _, n := range moved {
+			if err := s.put(b, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// put encodes the news under its id.
+func (s *Store) put(b *bolt.Bucket, n *model.News) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(n); err != nil {
+		return errors.Wrap(err, "[boltdb] gob.Encode() error")
+	}
+
+	if err := b.Put([]byte(n.Id), buf.Bytes()); err != nil {
+		return errors.Wrap(err, "[boltdb] put() Put error")
+	}
+	return nil
+}
+
+// rewriteReferences replaces the moved ids of the news ids of the stories, the deliveries and the alerts,
+// see store.RewriteIds.
+func rewriteReferences(tx *bolt.Tx, moved map[string]string) error {
+	stories := tx.Bucket([]byte(storiesBucket))
+	if stories == nil {
+		return bolt.ErrBucketNotFound
+	}
+	err := rewrite(stories, func() interface{} { return &model.Story{} }, func(v interface{}) bool {
+		story := v.(*model.Story)
+		ids, changed := store.RewriteIds(story.NewsIds, moved)
+		story.NewsIds = ids
+		return changed
+	})
+	if err != nil {
+		return err
+	}
+
+	deliveries := tx.Bucket([]byte(deliveriesBucket))
+	if deliveries == nil {
+		return bolt.ErrBucketNotFound
+	}
+	var webhookIds [][]byte
+	if err := deliveries.ForEach(func(k, v []byte) error {
+		if v == nil {
+			webhookIds = append(webhookIds, append([]byte{}, k...))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, webhookId := range webhookIds {
+		err := rewrite(deliveries.Bucket(webhookId), func() interface{} { return &model.Delivery{} }, func(v interface{}) bool {
+			delivery := v.(*model.Delivery)
+			ids, changed := store.RewriteIds(delivery.NewsIds, moved)
+			delivery.NewsIds = ids
+			return changed
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	alerts := tx.Bucket([]byte(alertsBucket))
+	if alerts == nil {
+		return bolt.ErrBucketNotFound
+	}
+	return rewrite(alerts, func() interface{} { return &model.Alert{} }, func(v interface{}) bool {
+		alert := v.(*model.Alert)
+		id, exist := moved[alert.NewsId]
+		if exist {
+			alert.NewsId = id
+		}
+		return exist
+	})
+}
+
+// rewrite decodes every value of the bucket into a new value, and puts it again if the update changed it.
+func rewrite(b *bolt.Bucket, value func() interface{}, update func(v interface{}) bool) error {
+	var keys [][]byte
+	var values []interface{}
+	err := b.ForEach(func(k, v []byte) error {
+		decoded := value()
+		if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(decoded); err != nil {
+			return nil
+		}
+		if update(decoded) {
+			keys = append(keys, append([]byte{}, k...))
+			values = append(values, decoded)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The bucket is not modified while it is iterated.
+	for i, k := range keys {
+		buf := &bytes.Buffer{}
+		if err := gob.NewEncoder(buf).Encode(values[i]); err != nil {
+			return errors.Wrap(err, "[boltdb] gob.Encode() error")
+		}
+		if err := b.Put(k, buf.Bytes()); err != nil {
+			return errors.Wrap(err, "[boltdb] rewrite() Put error")
+		}
+	}
+	return nil
+}
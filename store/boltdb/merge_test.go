@@ -0,0 +1,93 @@
+package boltdb
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+func TestMergeNews(t *testing.T) {
+	// The store is created in the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	s, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	const url = "https://www.utusan.com.my/berita/a"
+	_, err = s.Insert([]*model.News{
+		{Id: "old-1", Url: "http://www.utusan.com.my//berita/a", Datetime: day, Title: "Kilang terbakar"},
+		{Id: "old-2", Url: url + "/?utm_source=facebook", Datetime: day.Add(time.Hour), ClusterId: "old-1"},
+		{Id: "other", Url: "https://www.nst.com.my/news/b", Datetime: day, ClusterId: "old-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SaveStories(day, []*model.Story{{Id: "story", LastDatetime: day, NewsIds: []string{"old-1", "other", "old-2"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveDelivery(&model.Delivery{Id: "delivery", WebhookId: "webhook", NewsIds: []string{"old-2"}, Datetime: day}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveAlerts([]*model.Alert{{Id: "alert", SearchId: "search", NewsId: "old-1", Created: day}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.MergeNews([]*store.Merge{{Id: "new", Url: url, From: "old-1", Duplicates: []string{"old-2"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := s.Get("new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Url != url || n.Title != "Kilang terbakar" {
+		t.Errorf("got %+v", n)
+	}
+	for _, id := range []string{"old-1", "old-2"} {
+		if _, err := s.Get(id); err != store.ErrNotFound {
+			t.Errorf("%s: got %v, expected %v", id, err, store.ErrNotFound)
+		}
+	}
+	if n, err := s.Get("other"); err != nil || n.ClusterId != "new" {
+		t.Errorf("got %+v, %v", n, err)
+	}
+
+	story, err := s.GetStory("story")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"new", "other"}; !reflect.DeepEqual(story.NewsIds, expected) {
+		t.Errorf("got the story news %q, expected %q", story.NewsIds, expected)
+	}
+
+	deliveries, err := s.GetDeliveries("webhook", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || !reflect.DeepEqual(deliveries[0].NewsIds, []string{"new"}) {
+		t.Errorf("got the deliveries %+v", deliveries)
+	}
+
+	alerts, err := s.GetAlerts("search", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].NewsId != "new" {
+		t.Errorf("got the alerts %+v", alerts)
+	}
+}
@@ -0,0 +1,249 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveSearch(search *model.SavedSearch) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(search); err != nil {
+		return errors.Wrap(err, "[boltdb] gob.Encode() error")
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(searchesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.Put([]byte(search.Id), buf.Bytes())
+	})
+}
+
+func (s *Store) GetSearches(owner string) ([]*model.SavedSearch, error) {
+	var list = make([]*model.SavedSearch, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(searchesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			search := &model.SavedSearch{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(search); err != nil {
+				return nil
+			}
+
+			if owner == "" || search.Owner == owner {
+				list = append(list, search)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+
+	return list, nil
+}
+
+func (s *Store) GetSearch(id string) (*model.SavedSearch, error) {
+	var search *model.SavedSearch
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(searchesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return store.ErrNotFound
+		}
+
+		search = &model.SavedSearch{}
+		return gob.NewDecoder(bytes.NewBuffer(v)).Decode(search)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return search, nil
+}
+
+func (s *Store) DeleteSearch(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(searchesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		if b.Get([]byte(id)) == nil {
+			return store.ErrNotFound
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.Wrap(err, "[boltdb] DeleteSearch() Delete error")
+		}
+
+		alerts := tx.Bucket([]byte(alertsBucket))
+		if alerts == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		var keys [][]byte
+		if err := alerts.ForEach(func(k, v []byte) error {
+			alert := &model.Alert{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(alert); err == nil && alert.SearchId == id {
+				keys = append(keys, k)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, k := range keys {
+			if err := alerts.Delete(k); err != nil {
+				return errors.Wrap(err, "[boltdb] DeleteSearch() Delete alert error")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) SaveAlerts(alerts []*model.Alert) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(alertsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		for _, alert := range alerts {
+			if b.Get([]byte(alert.Id)) != nil {
+				continue
+			}
+
+			buf := &bytes.Buffer{}
+			if err := gob.NewEncoder(buf).Encode(alert); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Encode() error")
+			}
+
+			if err := b.Put([]byte(alert.Id), buf.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) GetAlerts(searchId string, limit int) ([]*model.Alert, error) {
+	list, err := s.queryAlerts(func(alert *model.Alert) bool {
+		return alert.SearchId == searchId
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Created.After(list[j].Created)
+	})
+
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetPendingAlerts() ([]*model.Alert, error) {
+	list, err := s.queryAlerts(func(alert *model.Alert) bool {
+		return alert.Delivered == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+
+	return list, nil
+}
+
+func (s *Store) SetDelivered(ids []string, delivered time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(alertsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		for _, id := range ids {
+			v := b.Get([]byte(id))
+			if v == nil {
+				continue
+			}
+
+			alert := &model.Alert{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(alert); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Decode() error")
+			}
+
+			alert.Delivered = &delivered
+
+			buf := &bytes.Buffer{}
+			if err := gob.NewEncoder(buf).Encode(alert); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Encode() error")
+			}
+
+			if err := b.Put([]byte(id), buf.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// queryAlerts returns the alerts selected by the match function.
+func (s *Store) queryAlerts(match func(alert *model.Alert) bool) ([]*model.Alert, error) {
+	var list = make([]*model.Alert, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(alertsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			alert := &model.Alert{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(alert); err != nil {
+				return nil
+			}
+
+			if match(alert) {
+				list = append(list, alert)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
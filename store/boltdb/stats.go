@@ -0,0 +1,46 @@
+package boltdb
+
+import (
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// The news are decoded whole, they are counted as they are read by get.
+
+func (s *Store) CountBy(filter store.Filter, group string, limit int) ([]*store.Count, error) {
+	list, err := s.get(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	counter := store.NewCounter(group)
+	for _, n := range list {
+		counter.Add(n)
+	}
+
+	return counter.Counts(limit), nil
+}
+
+func (s *Store) Histogram(filter store.Filter, interval string, group string, loc *time.Location) ([]*store.Bucket, error) {
+	list, err := s.get(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := store.NewHistogram(interval, group, loc)
+	for _, n := range list {
+		histogram.Add(n)
+	}
+
+	return histogram.Buckets(), nil
+}
+
+func (s *Store) TopAuthors(filter store.Filter, limit int) ([]*store.AuthorCount, error) {
+	list, err := s.get(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.TopAuthors(list, limit), nil
+}
@@ -4,15 +4,27 @@ import (
 	"bytes"
 	"encoding/gob"
 	"log"
-	"time"
+	"sort"
 
 	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
 	"github.com/boltdb/bolt"
 	"github.com/getsentry/raven-go"
 	"github.com/pkg/errors"
 )
 
 const bucket = "news"
+const storiesBucket = "stories"
+const webhooksBucket = "webhooks"
+
+// deliveriesBucket has a bucket per webhook, keyed by a sequence.
+const deliveriesBucket = "deliveries"
+const searchesBucket = "searches"
+
+// alertsBucket is keyed by the alert id.
+const alertsBucket = "alerts"
+const apiKeysBucket = "api_keys"
+const topicsBucket = "topics"
 
 type Store struct {
 	db *bolt.DB
@@ -27,7 +39,21 @@ func NewStore() (*Store, error) {
 	gob.Register(&model.News{})
 	gob.Register(&model.Picture{})
 
+	gob.Register(&model.Story{})
+	gob.Register(&model.Webhook{})
+	gob.Register(&model.Delivery{})
+	gob.Register(&model.SavedSearch{})
+	gob.Register(&model.Alert{})
+	gob.Register(&model.ApiKey{})
+	gob.Register(&model.Topic{})
+
 	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{storiesBucket, webhooksBucket, deliveriesBucket, searchesBucket, alertsBucket, apiKeysBucket, topicsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+
 		_, err := tx.CreateBucketIfNotExists([]byte(bucket))
 		return err
 	}); err != nil {
@@ -41,7 +67,7 @@ func NewStore() (*Store, error) {
 	}, nil
 }
 
-func (s *Store) Insert(news []*model.News) error {
+func (s *Store) Insert(news []*model.News) ([]*model.News, error) {
 	log.Println("Add")
 	var data = make(map[string][]byte)
 	for _, v := range news {
@@ -51,51 +77,118 @@ func (s *Store) Insert(news []*model.News) error {
 		if err := gob.NewEncoder(buf).Encode(v); err != nil {
 			err = errors.Wrap(err, "[boltdb] gob.Encode() error")
 			raven.CaptureError(err, map[string]string{"module": "boltdb"})
-			return err
+			return nil, err
 		}
 
 		data[v.Id] = buf.Bytes()
 	}
 
+	var inserted []*model.News
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(bucket))
 		if b == nil {
 			return bolt.ErrBucketNotFound
 		}
 
-		for key, v := range data {
-			keyBytes := []byte(key)
+		inserted = nil
+		for _, v := range news {
+			keyBytes := []byte(v.Id)
 			// Check if it already exists
 			if b.Get(keyBytes) != nil {
 				log.Println("already exist")
 				continue
 			}
 
-			err := b.Put(keyBytes, v)
+			err := b.Put(keyBytes, data[v.Id])
 			if err != nil {
 				return errors.Wrap(err, "[boltdb] Add() Put error")
 			}
+			inserted = append(inserted, v)
 		}
 
 		return nil
 	})
 
 	if err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+func (s *Store) Update(news []*model.News) error {
+	var data = make(map[string][]byte)
+	for _, v := range news {
+		buf := &bytes.Buffer{}
+
+		if err := gob.NewEncoder(buf).Encode(v); err != nil {
+			err = errors.Wrap(err, "[boltdb] gob.Encode() error")
+			raven.CaptureError(err, map[string]string{"module": "boltdb"})
+			return err
+		}
+
+		data[v.Id] = buf.Bytes()
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		for key, v := range data {
+			keyBytes := []byte(key)
+			// Only replace the news that already exist
+			if b.Get(keyBytes) == nil {
+				continue
+			}
+
+			err := b.Put(keyBytes, v)
+			if err != nil {
+				return errors.Wrap(err, "[boltdb] Update() Put error")
+			}
+		}
+
 		return nil
+	})
+}
+
+func (s *Store) Get(id string) (*model.News, error) {
+	var value []byte
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		// The value is only valid during the transaction
+		if v := b.Get([]byte(id)); v != nil {
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if value == nil {
+		return nil, store.ErrNotFound
+	}
+
+	return s.decode([]byte(id), value)
 }
 
 func (s *Store) GetByKeywords(keywords []string) ([]*model.News, error) {
 	return nil, nil
 }
 
-func (s *Store) GetAll(from time.Time, until time.Time) ([]*model.News, error) {
-	return s.get(from, until, "")
+func (s *Store) GetAll(filter store.Filter) ([]*model.News, error) {
+	return s.get(filter)
 }
 
-func (s *Store) get(from time.Time, until time.Time, providerId string) ([]*model.News, error) {
+func (s *Store) get(filter store.Filter) ([]*model.News, error) {
 	//if days == 0 {
 	//	// default to 1 day
 	//	days -= 1
@@ -136,7 +229,7 @@ func (s *Store) get(from time.Time, until time.Time, providerId string) ([]*mode
 				continue
 			}
 
-			if (!from.IsZero() && n.Datetime.After(from)) || (!until.IsZero() && n.Datetime.Before(until)) {
+			if !filter.Match(n) {
 				continue
 			}
 
@@ -242,6 +335,26 @@ func (s *Store) GetByProvider(provider string) ([]*model.News, error) {
 	return nil, nil
 }
 
+func (s *Store) GetByCluster(clusterId string) ([]*model.News, error) {
+	all, err := s.get(store.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*model.News
+	for _, n := range all {
+		if n.ClusterId == clusterId {
+			list = append(list, n)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Datetime.After(list[j].Datetime)
+	})
+
+	return list, nil
+}
+
 //// Create a meta bucket to store the last update datetime.
 //func (s *Store) GetLatestNewsTime(providerId string) (time.Time) {
 //	list, err := s.get(providerId)
@@ -294,5 +407,10 @@ func (s *Store) decode(key []byte, value []byte) (*model.News, error) {
 		return nil, err
 	}
 
+	// The news stored before the paragraphs were kept.
+	if n.Paragraphs == nil {
+		n.SetContent(n.Content)
+	}
+
 	return n, nil
 }
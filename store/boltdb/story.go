@@ -0,0 +1,125 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveStories(since time.Time, stories []*model.Story) error {
+	var data = make(map[string][]byte)
+	for _, v := range stories {
+		buf := &bytes.Buffer{}
+
+		if err := gob.NewEncoder(buf).Encode(v); err != nil {
+			return errors.Wrap(err, "[boltdb] gob.Encode() error")
+		}
+
+		data[v.Id] = buf.Bytes()
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(storiesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		var deleteKeys [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			story := &model.Story{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(story); err != nil || !story.LastDatetime.Before(since) {
+				deleteKeys = append(deleteKeys, append([]byte{}, k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range deleteKeys {
+			if err := b.Delete(k); err != nil {
+				return errors.Wrap(err, "[boltdb] SaveStories() Delete error")
+			}
+		}
+
+		for key, v := range data {
+			if err := b.Put([]byte(key), v); err != nil {
+				return errors.Wrap(err, "[boltdb] SaveStories() Put error")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) GetStories(from time.Time, until time.Time) ([]*model.Story, error) {
+	var list = make([]*model.Story, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(storiesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			story := &model.Story{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(story); err != nil {
+				return nil
+			}
+
+			// from is the latest datetime, until is the earliest.
+			if (!from.IsZero() && story.FirstDatetime.After(from)) || (!until.IsZero() && story.LastDatetime.Before(until)) {
+				return nil
+			}
+
+			list = append(list, story)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastDatetime.After(list[j].LastDatetime)
+	})
+
+	return list, nil
+}
+
+func (s *Store) GetStory(id string) (*model.Story, error) {
+	story := &model.Story{}
+	var found bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(storiesBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+
+		found = true
+		return gob.NewDecoder(bytes.NewBuffer(v)).Decode(story)
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "[boltdb] GetStory() error")
+	}
+
+	if !found {
+		return nil, store.ErrNotFound
+	}
+
+	return story, nil
+}
@@ -0,0 +1,138 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sort"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveTopic(topic *model.Topic) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(topic); err != nil {
+		return errors.Wrap(err, "[boltdb] gob.Encode() error")
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(topicsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.Put([]byte(topic.Id), buf.Bytes())
+	})
+}
+
+func (s *Store) GetTopics() ([]*model.Topic, error) {
+	var list = make([]*model.Topic, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(topicsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			topic := &model.Topic{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(topic); err != nil {
+				return nil
+			}
+
+			list = append(list, topic)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Id < list[j].Id
+	})
+
+	return list, nil
+}
+
+func (s *Store) GetTopic(id string) (*model.Topic, error) {
+	var topic *model.Topic
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(topicsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return store.ErrNotFound
+		}
+
+		topic = &model.Topic{}
+		return gob.NewDecoder(bytes.NewBuffer(v)).Decode(topic)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return topic, nil
+}
+
+func (s *Store) DeleteTopic(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(topicsBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		if b.Get([]byte(id)) == nil {
+			return store.ErrNotFound
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.Wrap(err, "[boltdb] DeleteTopic() Delete error")
+		}
+		return nil
+	})
+}
+
+// SetTopics decodes the news and encodes them back with their topics.
+func (s *Store) SetTopics(topics map[string][]string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		for id, list := range topics {
+			v := b.Get([]byte(id))
+			if v == nil {
+				continue
+			}
+
+			n := &model.News{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(n); err != nil {
+				return errors.Wrap(err, "[boltdb] SetTopics() gob.Decode() error")
+			}
+			n.Topics = list
+
+			buf := &bytes.Buffer{}
+			if err := gob.NewEncoder(buf).Encode(n); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Encode() error")
+			}
+
+			if err := b.Put([]byte(id), buf.Bytes()); err != nil {
+				return errors.Wrap(err, "[boltdb] SetTopics() Put error")
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,246 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveWebhook(webhook *model.Webhook) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(webhook); err != nil {
+		return errors.Wrap(err, "[boltdb] gob.Encode() error")
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(webhooksBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.Put([]byte(webhook.Id), buf.Bytes())
+	})
+}
+
+func (s *Store) GetWebhooks() ([]*model.Webhook, error) {
+	var list = make([]*model.Webhook, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(webhooksBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return b.ForEach(func(k, v []byte) error {
+			webhook := &model.Webhook{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(webhook); err != nil {
+				return nil
+			}
+
+			list = append(list, webhook)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Created.Before(list[j].Created)
+	})
+
+	return list, nil
+}
+
+func (s *Store) GetWebhook(id string) (*model.Webhook, error) {
+	var webhook *model.Webhook
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(webhooksBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		v := b.Get([]byte(id))
+		if v == nil {
+			return store.ErrNotFound
+		}
+
+		webhook = &model.Webhook{}
+		return gob.NewDecoder(bytes.NewBuffer(v)).Decode(webhook)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (s *Store) DeleteWebhook(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(webhooksBucket))
+		if b == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		if b.Get([]byte(id)) == nil {
+			return store.ErrNotFound
+		}
+
+		if err := b.Delete([]byte(id)); err != nil {
+			return errors.Wrap(err, "[boltdb] DeleteWebhook() Delete error")
+		}
+
+		deliveries := tx.Bucket([]byte(deliveriesBucket))
+		if deliveries == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		if deliveries.Bucket([]byte(id)) == nil {
+			return nil
+		}
+		return deliveries.DeleteBucket([]byte(id))
+	})
+}
+
+func (s *Store) SaveDelivery(delivery *model.Delivery) error {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(delivery); err != nil {
+		return errors.Wrap(err, "[boltdb] gob.Encode() error")
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket([]byte(deliveriesBucket))
+		if deliveries == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		b, err := deliveries.CreateBucketIfNotExists([]byte(delivery.WebhookId))
+		if err != nil {
+			return err
+		}
+
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		return b.Put(key, buf.Bytes())
+	})
+}
+
+func (s *Store) GetDeliveries(webhookId string, limit int) ([]*model.Delivery, error) {
+	var list = make([]*model.Delivery, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket([]byte(deliveriesBucket))
+		if deliveries == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		b := deliveries.Bucket([]byte(webhookId))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil && len(list) < limit; k, v = c.Prev() {
+			delivery := &model.Delivery{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(delivery); err != nil {
+				continue
+			}
+
+			list = append(list, delivery)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (s *Store) GetRetries(until time.Time) ([]*model.Delivery, error) {
+	var list []*model.Delivery
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket([]byte(deliveriesBucket))
+		if deliveries == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		return deliveries.ForEach(func(webhookId, _ []byte) error {
+			b := deliveries.Bucket(webhookId)
+			if b == nil {
+				return nil
+			}
+
+			return b.ForEach(func(k, v []byte) error {
+				delivery := &model.Delivery{}
+				if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(delivery); err != nil {
+					return nil
+				}
+
+				if delivery.Retry != nil && !delivery.Retry.After(until) {
+					list = append(list, delivery)
+				}
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Retry.Before(*list[j].Retry)
+	})
+
+	return list, nil
+}
+
+func (s *Store) SetRetried(delivery *model.Delivery) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		deliveries := tx.Bucket([]byte(deliveriesBucket))
+		if deliveries == nil {
+			return bolt.ErrBucketNotFound
+		}
+
+		b := deliveries.Bucket([]byte(delivery.WebhookId))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			saved := &model.Delivery{}
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(saved); err != nil {
+				continue
+			}
+
+			if saved.Id != delivery.Id || saved.Attempt != delivery.Attempt {
+				continue
+			}
+
+			saved.Retry = nil
+			buf := &bytes.Buffer{}
+			if err := gob.NewEncoder(buf).Encode(saved); err != nil {
+				return errors.Wrap(err, "[boltdb] gob.Encode() error")
+			}
+			return b.Put(k, buf.Bytes())
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/simhash"
+)
+
+// The largest hamming distance between the SimHash of two near-duplicate news.
+// Unrelated news are around 32 bits apart, a lightly edited copy of a wire story within 10.
+const duplicateDistance = 10
+
+// Only the news published within the window are compared.
+const clusterWindow = 7 * 24 * time.Hour
+
+// clusterFields are the fields of the news read by the index, see hash.
+var clusterFields = []string{"id", "datetime", "title", "content", "cluster_id"}
+
+// clusterIndex assigns the near-duplicate news, e.g the same wire story published by several newspapers, to the same cluster.
+type clusterIndex struct {
+	hashes     []uint64
+	clusterIds []string
+}
+
+func newClusterIndex(store NewsStore) (*clusterIndex, error) {
+	now := time.Now()
+	list, err := store.GetAll(Filter{From: now, Until: now.Add(-clusterWindow), Fields: clusterFields})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &clusterIndex{}
+	for _, n := range list {
+		clusterId := n.ClusterId
+		if clusterId == "" {
+			clusterId = n.Id
+		}
+
+		c.add(hash(n), clusterId)
+	}
+
+	return c, nil
+}
+
+func (c *clusterIndex) add(hash uint64, clusterId string) {
+	c.hashes = append(c.hashes, hash)
+	c.clusterIds = append(c.clusterIds, clusterId)
+}
+
+// assign sets the cluster of the news to the closest near-duplicate, or to the news itself.
+func (c *clusterIndex) assign(n *model.News) {
+	h := hash(n)
+
+	best := duplicateDistance + 1
+	n.ClusterId = n.Id
+	for i, v := range c.hashes {
+		if d := simhash.Distance(h, v); d < best {
+			best = d
+			n.ClusterId = c.clusterIds[i]
+		}
+	}
+
+	c.add(h, n.ClusterId)
+}
+
+func hash(n *model.News) uint64 {
+	return simhash.Hash(n.Title + "\n" + n.Content)
+}
+
+// Dedupe keeps the first news of every cluster.
+func Dedupe(list []*model.News) []*model.News {
+	var seen = make(map[string]struct{})
+	var result = make([]*model.News, 0, len(list))
+
+	for _, v := range list {
+		clusterId := v.ClusterId
+		if clusterId == "" {
+			clusterId = v.Id
+		}
+
+		if _, exist := seen[clusterId]; exist {
+			continue
+		}
+
+		seen[clusterId] = struct{}{}
+		result = append(result, v)
+	}
+
+	return result
+}
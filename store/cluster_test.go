@@ -0,0 +1,117 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// recentStore returns the news of GetAll, the other methods are not used.
+type recentStore struct {
+	NewsStore
+	list   []*model.News
+	filter Filter
+}
+
+func (s *recentStore) GetAll(filter Filter) ([]*model.News, error) {
+	s.filter = filter
+	return s.list, nil
+}
+
+func TestClusterIndex(t *testing.T) {
+	const story = "The government will allocate an additional RM2 billion for education, health and housing next year, the finance minister said today. " +
+		"He said the allocation would be distributed to all states, especially to the rural areas which still lack basic facilities such as schools and clinics."
+
+	s := &recentStore{list: []*model.News{
+		{Id: "a", Title: "RM2 billion for education", Content: story},
+		{Id: "b", ClusterId: "x", Title: "Two men arrested", Content: "Two men were arrested after a car chase that ended with a crash near the Causeway early this morning."},
+	}}
+	c, err := newClusterIndex(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the news of the last week are compared, with the fields of their hash.
+	if week := s.filter.From.Sub(s.filter.Until); week != clusterWindow || time.Since(s.filter.From) > time.Minute {
+		t.Errorf("got the filter %+v", s.filter)
+	}
+	if expected := []string{"id", "datetime", "title", "content", "cluster_id"}; !reflect.DeepEqual(s.filter.Fields, expected) {
+		t.Errorf("got the fields %v, expected %v", s.filter.Fields, expected)
+	}
+
+	// The same story published by another newspaper joins the cluster of the first.
+	copied := &model.News{Id: "c", Title: "RM2 billion for education", Content: story + " - Bernama"}
+	c.assign(copied)
+	if copied.ClusterId != "a" {
+		t.Errorf("got the cluster %q, expected a", copied.ClusterId)
+	}
+
+	// A stored news keeps its cluster.
+	arrested := &model.News{Id: "d", Title: "Two men arrested", Content: "Two men were arrested after a car chase that ended with a crash near the Causeway early this morning."}
+	c.assign(arrested)
+	if arrested.ClusterId != "x" {
+		t.Errorf("got the cluster %q, expected x", arrested.ClusterId)
+	}
+
+	other := &model.News{Id: "e", Title: "Flood in Kelantan", Content: "Thousands of flood victims were moved to the relief centres as the water rose in Kota Bharu."}
+	c.assign(other)
+	if other.ClusterId != "e" {
+		t.Errorf("got the cluster %q, expected e", other.ClusterId)
+	}
+
+	// The news assigned are compared with the next ones.
+	again := &model.News{Id: "f", Title: other.Title, Content: other.Content}
+	c.assign(again)
+	if again.ClusterId != "e" {
+		t.Errorf("got the cluster %q, expected e", again.ClusterId)
+	}
+}
+
+func TestClusterDistance(t *testing.T) {
+	n := &model.News{Id: "n", Title: "Banjir", Content: "Banjir di Kelantan"}
+	h := hash(n)
+
+	tests := []struct {
+		// The bits flipped in the hash of the indexed news.
+		flipped  uint64
+		expected string
+	}{
+		{0, "near"},
+		{1<<duplicateDistance - 1, "near"},
+		{1<<(duplicateDistance+1) - 1, "n"},
+	}
+
+	for _, test := range tests {
+		c := &clusterIndex{}
+		c.add(h^test.flipped, "near")
+
+		c.assign(n)
+		if n.ClusterId != test.expected {
+			t.Errorf("%b: got %q, expected %q", test.flipped, n.ClusterId, test.expected)
+		}
+	}
+
+	// The closest cluster wins.
+	c := &clusterIndex{}
+	c.add(h^0x7, "far")
+	c.add(h^0x1, "near")
+	c.add(h^0x3, "middle")
+	c.assign(n)
+	if n.ClusterId != "near" {
+		t.Errorf("got %q, expected near", n.ClusterId)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	list := []*model.News{{Id: "a"}, {Id: "b", ClusterId: "a"}, {Id: "c", ClusterId: "c"}, {Id: "d", ClusterId: "c"}, {Id: "e"}}
+
+	var ids []string
+	for _, n := range Dedupe(list) {
+		ids = append(ids, n.Id)
+	}
+	if expected := []string{"a", "c", "e"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got %v, expected %v", ids, expected)
+	}
+}
@@ -1,3 +1,5 @@
 package store
 
+import "github.com/pkg/errors"
 
+var ErrNotFound = errors.New("News not found !")
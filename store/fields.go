@@ -0,0 +1,89 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewsFields are the fields of a news, by their JSON name, that Filter.Fields can select.
+var NewsFields = []string{
+	"id", "author", "datetime", "title", "location", "content", "paragraphs", "html", "pictures", "tags", "url",
+	"source", "source.name", "source.id", "source.category", "source.subcategory", "source.tags", "source.url",
+	"lead", "summary", "language", "cluster_id", "entities", "topics", "canonical_url", "description", "section", "modified", "annotations",
+}
+
+// DefaultFields are the fields of the listings, every field but the full bodies.
+var DefaultFields = []string{
+	"id", "author", "datetime", "title", "location", "pictures", "tags", "url", "source",
+	"lead", "summary", "language", "cluster_id", "entities", "topics", "canonical_url", "description", "section", "modified", "annotations",
+}
+
+// BodyFields are the full bodies of a news, left out of the DefaultFields.
+var BodyFields = []string{"content", "paragraphs", "html"}
+
+// IsNewsField returns true if the field is one of NewsFields.
+func IsNewsField(field string) bool {
+	for _, f := range NewsFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFields returns the fields of their names, e.g title and source.name. The id is always returned.
+// It defaults to the DefaultFields, * stands for them. The BodyFields alone are added to the DefaultFields,
+// e.g content returns the DefaultFields and the content.
+func ParseFields(names []string) ([]string, error) {
+	var fields = []string{"id"}
+	var bodies []string
+	var explicit bool
+	for _, field := range names {
+		field = strings.TrimSpace(field)
+
+		switch {
+		case field == "":
+		case field == "id":
+			explicit = true
+		case field == "*":
+			// The id of the DefaultFields is the first one.
+			fields = append(fields, DefaultFields[1:]...)
+			explicit = true
+		case isBodyField(field):
+			bodies = append(bodies, field)
+		case IsNewsField(field):
+			fields = append(fields, field)
+			explicit = true
+		default:
+			return nil, fmt.Errorf("Unknown field %s", field)
+		}
+	}
+
+	if !explicit {
+		fields = append([]string(nil), DefaultFields...)
+	}
+	return append(fields, bodies...), nil
+}
+
+func isBodyField(field string) bool {
+	for _, f := range BodyFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Selects returns true if the field is read by the store, a nested field is selected by its parent, e.g source selects source.name.
+func (f Filter) Selects(field string) bool {
+	if len(f.Fields) == 0 {
+		return true
+	}
+
+	for _, v := range f.Fields {
+		if v == field || strings.HasPrefix(field, v+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	defaults := func(bodies ...string) []string {
+		return append(append([]string(nil), DefaultFields...), bodies...)
+	}
+
+	tests := []struct {
+		names    []string
+		expected []string
+	}{
+		{nil, DefaultFields},
+		{[]string{""}, DefaultFields},
+		{[]string{"id"}, []string{"id"}},
+		{[]string{"title", " source.name "}, []string{"id", "title", "source.name"}},
+		// The bodies alone are added to the default fields.
+		{[]string{"content"}, defaults("content")},
+		{[]string{"content", "html"}, defaults("content", "html")},
+		{[]string{"*", "paragraphs"}, defaults("paragraphs")},
+		{[]string{"id", "content"}, []string{"id", "content"}},
+		{[]string{"title", "content"}, []string{"id", "title", "content"}},
+	}
+
+	for _, test := range tests {
+		fields, err := ParseFields(test.names)
+		if err != nil {
+			t.Errorf("%q: %v", test.names, err)
+			continue
+		}
+		if !reflect.DeepEqual(fields, test.expected) {
+			t.Errorf("%q: got %v, expected %v", test.names, fields, test.expected)
+		}
+	}
+
+	if _, err := ParseFields([]string{"title", "unknown"}); err == nil {
+		t.Error("got no error for an unknown field")
+	}
+}
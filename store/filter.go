@@ -0,0 +1,80 @@
+package store
+
+import (
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// Filter selects the news returned by NewsStore.GetAll. The zero values are not filtered.
+type Filter struct {
+	// From is the latest datetime, Until is the earliest.
+	From     time.Time
+	Until    time.Time
+	Language string
+	// Entity is the id of an entity mentioned, EntityType the type of any entity mentioned.
+	Entity     string
+	EntityType string
+	// Newspaper is the id of the newspaper, Tag a tag of the news or of its source in any case, see HasTag.
+	Newspaper string
+	Tag       string
+	// Topic is the id of a topic of the news, see package taxonomy.
+	Topic string
+
+	// Fields are the fields of the news read by the store, see NewsFields. The id is always read.
+	// The stores may read more, nil reads every field.
+	Fields []string
+}
+
+// Match returns true if the news is selected, for the stores that can not filter in their queries.
+func (f Filter) Match(n *model.News) bool {
+	if !f.From.IsZero() && n.Datetime.After(f.From) {
+		return false
+	}
+
+	if !f.Until.IsZero() && n.Datetime.Before(f.Until) {
+		return false
+	}
+
+	if f.Language != "" && n.Language != f.Language {
+		return false
+	}
+
+	if f.Newspaper != "" && n.Source.NewspaperId != f.Newspaper {
+		return false
+	}
+
+	if f.Tag != "" && !HasTag(n, f.Tag) {
+		return false
+	}
+
+	if f.Topic != "" && !HasTopic(n, f.Topic) {
+		return false
+	}
+
+	if f.Entity != "" || f.EntityType != "" {
+		var found bool
+		for _, e := range n.Entities {
+			if (f.Entity == "" || e.Id == f.Entity) && (f.EntityType == "" || e.Type == f.EntityType) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasTopic returns true if the topic is one of the news.
+func HasTopic(n *model.News, topic string) bool {
+	for _, v := range n.Topics {
+		if v == topic {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/provider"
+)
+
+// A field is degraded if it is missing from more than this share of the news of the source, e.g after a redesign.
+const degradedShare = 0.2
+
+// HealthFields are the fields of the news read by Health.
+var HealthFields = []string{"source.id", "datetime", "annotations"}
+
+// SourceHealth is how the fields of the news of a newspaper were extracted, see provider.ExtractedFields.
+type SourceHealth struct {
+	Newspaper string    `json:"newspaper"`
+	News      int       `json:"news"`
+	Latest    time.Time `json:"latest"`
+
+	// The news whose strategies are recorded, the news scraped before the record are not counted in the fields.
+	Recorded int `json:"recorded"`
+
+	// The number of news by strategy, e.g jsonld or selector, by field.
+	Fields map[string]map[string]int `json:"fields"`
+
+	// The fields missing from more than a fifth of the recorded news, sorted.
+	Degraded []string `json:"degraded"`
+}
+
+// Health returns the health of the newspapers of the news, by the strategies recorded by the providers, sorted by newspaper.
+func Health(list []*model.News) []*SourceHealth {
+	var sources = make(map[string]*SourceHealth)
+	for _, n := range list {
+		h := sources[n.Source.NewspaperId]
+		if h == nil {
+			h = &SourceHealth{Newspaper: n.Source.NewspaperId, Fields: make(map[string]map[string]int), Degraded: []string{}}
+			for _, field := range provider.ExtractedFields {
+				h.Fields[field] = make(map[string]int)
+			}
+			sources[n.Source.NewspaperId] = h
+		}
+
+		h.News++
+		if n.Datetime.After(h.Latest) {
+			h.Latest = n.Datetime
+		}
+
+		recorded := false
+		for _, field := range provider.ExtractedFields {
+			if strategy, exist := n.Annotations[provider.ExtractionKey(field)]; exist {
+				h.Fields[field][strategy]++
+				recorded = true
+			}
+		}
+		if recorded {
+			h.Recorded++
+		}
+	}
+
+	var result = make([]*SourceHealth, 0, len(sources))
+	for _, h := range sources {
+		for _, field := range provider.ExtractedFields {
+			if h.Recorded > 0 && float64(h.Fields[field][provider.StrategyMissing]) > degradedShare*float64(h.Recorded) {
+				h.Degraded = append(h.Degraded, field)
+			}
+		}
+		sort.Strings(h.Degraded)
+		result = append(result, h)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Newspaper < result[j].Newspaper
+	})
+	return result
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestHealth(t *testing.T) {
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	extracted := func(title, author, datetime, image string) map[string]string {
+		return map[string]string{"extract.title": title, "extract.author": author, "extract.datetime": datetime, "extract.image": image}
+	}
+
+	list := []*model.News{
+		{Datetime: day, Source: model.BhSources[0], Annotations: extracted("jsonld", "jsonld", "jsonld", "opengraph")},
+		{Datetime: day.Add(time.Hour), Source: model.BhSources[1], Annotations: extracted("jsonld", "selector", "jsonld", "missing")},
+		{Datetime: day.Add(2 * time.Hour), Source: model.BhSources[0], Annotations: extracted("selector", "missing", "selector", "opengraph")},
+		// Scraped before the strategies were recorded.
+		{Datetime: day.Add(3 * time.Hour), Source: model.BhSources[0]},
+		{Datetime: day, Source: model.NstSources[0], Annotations: extracted("jsonld", "jsonld", "jsonld", "jsonld")},
+	}
+
+	report := Health(list)
+	if len(report) != 2 || report[0].Newspaper != model.BharianId || report[1].Newspaper != model.NstId {
+		t.Fatalf("got %v", report)
+	}
+
+	bh := report[0]
+	if bh.News != 4 || bh.Recorded != 3 || !bh.Latest.Equal(day.Add(3*time.Hour)) {
+		t.Errorf("got %+v", bh)
+	}
+	if expected := map[string]int{"jsonld": 1, "selector": 1, "missing": 1}; !reflect.DeepEqual(bh.Fields["author"], expected) {
+		t.Errorf("got the authors %v, expected %v", bh.Fields["author"], expected)
+	}
+
+	// A third of the authors and of the images are missing.
+	if expected := []string{"author", "image"}; !reflect.DeepEqual(bh.Degraded, expected) {
+		t.Errorf("got %v, expected %v", bh.Degraded, expected)
+	}
+	if len(report[1].Degraded) != 0 || report[1].Fields["title"]["jsonld"] != 1 {
+		t.Errorf("got %+v", report[1])
+	}
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/ahmadmuzakkir/scrapenews/canonical"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// MergeFields are the fields of the news read by Merges.
+var MergeFields = []string{"datetime", "url", "canonical_url"}
+
+// Merge moves a news to the id of its canonical url and removes its duplicates, see NewsStore.MergeNews.
+type Merge struct {
+	// The id and the canonical url of the news kept.
+	Id  string `json:"id"`
+	Url string `json:"url"`
+
+	// The id of the news moved to Id, empty if the news kept already has the id.
+	From string `json:"from,omitempty"`
+
+	// The ids of the duplicates removed.
+	Duplicates []string `json:"duplicates,omitempty"`
+}
+
+// Merges returns the merges of the news stored before their ids were generated from their canonical url,
+// see model.News.GenerateId. The canonical url is the one the page declares, as the scrape does, see canonical.Declared.
+// Of the news sharing a canonical url, the news with its id is kept, else the first scraped.
+// The news already under the id of their canonical url are left out.
+func Merges(list []*model.News) []*Merge {
+	var groups = make(map[string][]*model.News)
+	var urls = make(map[string]string)
+	var ids []string
+	for _, n := range list {
+		v := &model.News{Url: canonical.Declared(n.Url, n.CanonicalUrl)}
+		v.GenerateId()
+		if _, exist := groups[v.Id]; !exist {
+			ids = append(ids, v.Id)
+			urls[v.Id] = v.Url
+		}
+		groups[v.Id] = append(groups[v.Id], n)
+	}
+	sort.Strings(ids)
+
+	var merges []*Merge
+	for _, id := range ids {
+		group := groups[id]
+		sort.SliceStable(group, func(i, j int) bool {
+			if (group[i].Id == id) != (group[j].Id == id) {
+				return group[i].Id == id
+			}
+			return group[i].Datetime.Before(group[j].Datetime)
+		})
+
+		kept := group[0]
+		url := urls[id]
+		if kept.Id == id && len(group) == 1 && kept.Url == url {
+			continue
+		}
+
+		m := &Merge{Id: id, Url: url}
+		if kept.Id != id {
+			m.From = kept.Id
+		}
+		for _, n := range group[1:] {
+			m.Duplicates = append(m.Duplicates, n.Id)
+		}
+		merges = append(merges, m)
+	}
+
+	return merges
+}
+
+// MovedIds returns the ids of the news kept by the merges, by the ids of the news moved or removed.
+func MovedIds(merges []*Merge) map[string]string {
+	var moved = make(map[string]string)
+	for _, m := range merges {
+		if m.From != "" {
+			moved[m.From] = m.Id
+		}
+		for _, id := range m.Duplicates {
+			moved[id] = m.Id
+		}
+	}
+	return moved
+}
+
+// RewriteIds replaces the moved ids of the list, e.g the news ids of a story, and removes the ids repeated by the merges.
+// It returns false if the list is unchanged.
+func RewriteIds(ids []string, moved map[string]string) ([]string, bool) {
+	var changed bool
+	var seen = make(map[string]struct{})
+	var list = make([]string, 0, len(ids))
+	for _, id := range ids {
+		if to, exist := moved[id]; exist {
+			id = to
+			changed = true
+		}
+		if _, exist := seen[id]; exist {
+			continue
+		}
+		seen[id] = struct{}{}
+		list = append(list, id)
+	}
+	return list, changed
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestMerges(t *testing.T) {
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	canonicalId := func(url string) string {
+		n := &model.News{Url: url}
+		n.GenerateId()
+		return n.Id
+	}
+
+	const article = "https://www.utusan.com.my/berita/a"
+	list := []*model.News{
+		// The ids of the raw urls, scraped before the canonical urls.
+		{Id: "old-1", Url: "http://www.utusan.com.my//berita/a", Datetime: day.Add(time.Hour)},
+		{Id: "old-2", Url: "http://www.utusan.com.my/berita/a/?utm_source=facebook", Datetime: day},
+		{Id: "old-3", Url: "https://www.nst.com.my/news/b/", Datetime: day},
+		// Already canonical, and a duplicate of it.
+		{Id: canonicalId("https://www.nst.com.my/news/c"), Url: "https://www.nst.com.my/news/c", Datetime: day.Add(time.Hour)},
+		{Id: "old-4", Url: "https://www.nst.com.my/news/c/amp", Datetime: day},
+		{Id: canonicalId("https://www.nst.com.my/news/d"), Url: "https://www.nst.com.my/news/d", Datetime: day},
+	}
+
+	expected := map[string]*Merge{
+		canonicalId(article):                         {Id: canonicalId(article), Url: article, From: "old-2", Duplicates: []string{"old-1"}},
+		canonicalId("https://www.nst.com.my/news/b"): {Id: canonicalId("https://www.nst.com.my/news/b"), Url: "https://www.nst.com.my/news/b", From: "old-3"},
+		canonicalId("https://www.nst.com.my/news/c"): {Id: canonicalId("https://www.nst.com.my/news/c"), Url: "https://www.nst.com.my/news/c", Duplicates: []string{"old-4"}},
+	}
+
+	merges := Merges(list)
+	if len(merges) != len(expected) {
+		t.Fatalf("got %d merges, expected %d", len(merges), len(expected))
+	}
+	for _, m := range merges {
+		if !reflect.DeepEqual(m, expected[m.Id]) {
+			t.Errorf("got %+v, expected %+v", m, expected[m.Id])
+		}
+	}
+
+	// The merged news are left out.
+	if merges := Merges([]*model.News{{Id: canonicalId(article), Url: article}}); len(merges) != 0 {
+		t.Errorf("got %v", merges)
+	}
+}
+
+func TestMergesDeclaredCanonical(t *testing.T) {
+	canonicalId := func(url string) string {
+		n := &model.News{Url: url}
+		n.GenerateId()
+		return n.Id
+	}
+
+	// The id of a new scrape is the one of the canonical url the page declares, the stored news is moved to it.
+	const declared = "https://www.utusan.com.my/berita/nasional/kilang-terbakar-1"
+	list := []*model.News{
+		{Id: "old-1", Url: "http://www.utusan.com.my/berita/nasional/1?utm_source=facebook", CanonicalUrl: declared},
+		// The home of the site, or another site, is not the canonical url of the article.
+		{Id: "old-2", Url: "https://www.nst.com.my/news/b", CanonicalUrl: "https://www.nst.com.my/"},
+		{Id: "old-3", Url: "https://www.bharian.com.my/berita/c", CanonicalUrl: "https://www.facebook.com/bharian/c"},
+	}
+
+	expected := map[string]*Merge{
+		canonicalId(declared):                              {Id: canonicalId(declared), Url: declared, From: "old-1"},
+		canonicalId("https://www.nst.com.my/news/b"):       {Id: canonicalId("https://www.nst.com.my/news/b"), Url: "https://www.nst.com.my/news/b", From: "old-2"},
+		canonicalId("https://www.bharian.com.my/berita/c"): {Id: canonicalId("https://www.bharian.com.my/berita/c"), Url: "https://www.bharian.com.my/berita/c", From: "old-3"},
+	}
+
+	merges := Merges(list)
+	if len(merges) != len(expected) {
+		t.Fatalf("got %d merges, expected %d", len(merges), len(expected))
+	}
+	for _, m := range merges {
+		if !reflect.DeepEqual(m, expected[m.Id]) {
+			t.Errorf("got %+v, expected %+v", m, expected[m.Id])
+		}
+	}
+
+	// A news scraped with the declared canonical url is left out.
+	if merges := Merges([]*model.News{{Id: canonicalId(declared), Url: declared, CanonicalUrl: declared}}); len(merges) != 0 {
+		t.Errorf("got %v", merges)
+	}
+}
@@ -0,0 +1,64 @@
+package mysql
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+func (s *Store) SaveApiKey(key *model.ApiKey) error {
+	_, err := s.db.Exec("INSERT INTO api_keys(id, name, hash, prefix, scopes, rate_limit, expires, revoked, created) VALUES (?,?,?,?,?,?,?,?,?) "+
+		"ON DUPLICATE KEY UPDATE name = VALUES(name), hash = VALUES(hash), prefix = VALUES(prefix), scopes = VALUES(scopes), "+
+		"rate_limit = VALUES(rate_limit), expires = VALUES(expires), revoked = VALUES(revoked)",
+		key.Id, key.Name, key.Hash, key.Prefix, strings.Join(key.Scopes, ","), key.RateLimit,
+		key.Expires, key.Revoked, key.Created)
+	return err
+}
+
+func (s *Store) GetApiKeys() ([]*model.ApiKey, error) {
+	return s.queryApiKeys("1 = 1")
+}
+
+func (s *Store) GetApiKey(id string) (*model.ApiKey, error) {
+	list, err := s.queryApiKeys("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) AddApiKeyUsage(id string, requests int64, lastUsed time.Time) error {
+	_, err := s.db.Exec("UPDATE api_keys SET requests = requests + ?, last_used = ? WHERE id = ?", requests, lastUsed, id)
+	return err
+}
+
+func (s *Store) queryApiKeys(where string, args ...interface{}) ([]*model.ApiKey, error) {
+	rows, err := s.db.Query("SELECT id, name, hash, prefix, scopes, rate_limit, expires, revoked, created, requests, last_used FROM api_keys WHERE "+where+" ORDER BY created", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.ApiKey, 0)
+	for rows.Next() {
+		var scopes string
+		v := &model.ApiKey{}
+
+		if err := rows.Scan(&v.Id, &v.Name, &v.Hash, &v.Prefix, &scopes, &v.RateLimit, &v.Expires, &v.Revoked, &v.Created, &v.Requests, &v.LastUsed); err != nil {
+			return nil, err
+		}
+
+		v.Scopes = splitList(scopes)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
@@ -0,0 +1,74 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// The largest number of news ids in a single query.
+const entitiesBatch = 500
+
+// insertEntities replaces the entities of the news.
+func insertEntities(tx *sql.Tx, n *model.News) error {
+	if _, err := tx.Exec("DELETE FROM entities WHERE news_id = ?", n.Id); err != nil {
+		return err
+	}
+
+	for _, e := range n.Entities {
+		_, err := tx.Exec("INSERT INTO entities(news_id, entity_id, type, name, count) VALUES (?,?,?,?,?)", n.Id, e.Id, e.Type, e.Name, e.Count)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadEntities fills the entities of the news.
+func (s *Store) loadEntities(list []*model.News) error {
+	var byId = make(map[string]*model.News, len(list))
+	for _, n := range list {
+		byId[n.Id] = n
+	}
+
+	for start := 0; start < len(list); start += entitiesBatch {
+		end := start + entitiesBatch
+		if end > len(list) {
+			end = len(list)
+		}
+
+		var args []interface{}
+		for _, n := range list[start:end] {
+			args = append(args, n.Id)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+		rows, err := s.db.Query("SELECT news_id, entity_id, type, name, count FROM entities WHERE news_id IN ("+placeholders+") ORDER BY id", args...)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var newsId string
+			e := &model.Entity{}
+			if err := rows.Scan(&newsId, &e.Id, &e.Type, &e.Name, &e.Count); err != nil {
+				rows.Close()
+				return err
+			}
+
+			if n, exist := byId[newsId]; exist {
+				n.Entities = append(n.Entities, e)
+			}
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -18,9 +18,21 @@ var migrate = []string{
 		newspaper_subcategory varchar(255),
 		newspaper_tags TEXT,
 		newspaper_url varchar(255),
+		cluster_id varchar(255),
+		language varchar(8),
+		annotations TEXT,
+		html MEDIUMTEXT,
+		summary TEXT,
+		lead_text TEXT,
+		topics TEXT,
+		canonical_url varchar(255),
+		description TEXT,
+		section varchar(255),
+		modified timestamp null,
 	
 		primary key (id),
-		unique (gen_id)
+		unique (gen_id),
+		index (cluster_id)
 	) default charset = utf8mb4;
 	`,
 	`
@@ -29,6 +41,10 @@ var migrate = []string{
 		news_id varchar(255) not null references news(gen_id),
 		url varchar(255),
 		caption varchar(255),
+		hash char(64),
+		width int,
+		height int,
+		mime_type varchar(255),
 	
 		primary key (id),
 		CONSTRAINT pictures_news_id_foreign FOREIGN KEY (news_id) REFERENCES news(gen_id) ON DELETE CASCADE
@@ -36,7 +52,157 @@ var migrate = []string{
 	`,
 }
 
+// alter adds the columns introduced after the tables were first created.
+// The duplicate column errors on an up to date database are ignored.
+var alter = []string{
+	`ALTER TABLE pictures ADD COLUMN hash char(64);`,
+	`ALTER TABLE pictures ADD COLUMN width int;`,
+	`ALTER TABLE pictures ADD COLUMN height int;`,
+	`ALTER TABLE pictures ADD COLUMN mime_type varchar(255);`,
+	`ALTER TABLE news ADD COLUMN cluster_id varchar(255), ADD INDEX (cluster_id);`,
+	`ALTER TABLE news ADD COLUMN language varchar(8);`,
+	`ALTER TABLE news ADD COLUMN annotations TEXT;`,
+	`ALTER TABLE news ADD COLUMN html MEDIUMTEXT;`,
+	`ALTER TABLE news ADD COLUMN summary TEXT;`,
+	`ALTER TABLE news ADD COLUMN lead_text TEXT;`,
+	`ALTER TABLE news ADD COLUMN topics TEXT;`,
+	`ALTER TABLE news ADD COLUMN canonical_url varchar(255);`,
+	`ALTER TABLE news ADD COLUMN description TEXT;`,
+	`ALTER TABLE news ADD COLUMN section varchar(255);`,
+	`ALTER TABLE news ADD COLUMN modified timestamp null;`,
+	`
+	CREATE TABLE IF NOT EXISTS entities(
+		id bigint not null auto_increment,
+		news_id varchar(255) not null,
+		entity_id varchar(255) not null,
+		type varchar(32),
+		name varchar(255),
+		count int,
+	
+		primary key (id),
+		index (news_id),
+		index (entity_id),
+		CONSTRAINT entities_news_id_foreign FOREIGN KEY (news_id) REFERENCES news(gen_id) ON DELETE CASCADE
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS stories(
+		id varchar(255) not null,
+		label varchar(255),
+		keywords TEXT,
+		first_datetime timestamp null,
+		last_datetime timestamp null,
+		newspapers TEXT,
+		news_ids TEXT,
+	
+		primary key (id),
+		index (last_datetime)
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS webhooks(
+		id varchar(255) not null,
+		url TEXT not null,
+		secret varchar(255),
+		newspapers TEXT,
+		tags TEXT,
+		keywords TEXT,
+		created timestamp null,
+	
+		primary key (id)
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS deliveries(
+		seq bigint not null auto_increment,
+		id varchar(255) not null,
+		webhook_id varchar(255) not null,
+		news_ids TEXT,
+		attempt int,
+		datetime timestamp null,
+		status_code int,
+		error TEXT,
+		duration bigint,
+	
+		primary key (seq),
+		index (webhook_id, datetime)
+	) default charset = utf8mb4;
+	`,
+	`ALTER TABLE deliveries ADD COLUMN retry timestamp null, ADD INDEX (retry);`,
+	`
+	CREATE TABLE IF NOT EXISTS searches(
+		id varchar(255) not null,
+		owner varchar(255) not null,
+		name varchar(255),
+		query TEXT,
+		newspapers TEXT,
+		language varchar(8),
+		frequency varchar(16),
+		notifier varchar(32),
+		target TEXT,
+		created timestamp null,
+	
+		primary key (id),
+		index (owner)
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS alerts(
+		id varchar(255) not null,
+		search_id varchar(255) not null,
+		news_id varchar(255),
+		title varchar(255),
+		url varchar(255),
+		newspaper varchar(255),
+		datetime timestamp null,
+		created timestamp null,
+		delivered timestamp null,
+	
+		primary key (id),
+		index (search_id, created),
+		index (delivered)
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS api_keys(
+		id varchar(255) not null,
+		name varchar(255),
+		hash char(64) not null,
+		prefix varchar(32),
+		scopes varchar(255),
+		rate_limit int,
+		expires timestamp null,
+		revoked timestamp null,
+		created timestamp null,
+		requests bigint not null default 0,
+		last_used timestamp null,
+	
+		primary key (id),
+		unique (hash)
+	) default charset = utf8mb4;
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS topics(
+		id varchar(255) not null,
+		names TEXT,
+		aliases TEXT,
+		created timestamp null,
+		updated timestamp null,
+	
+		primary key (id)
+	) default charset = utf8mb4;
+	`,
+}
+
 var drop = []string{
 	`DROP TABLE IF EXISTS news;`,
 	`DROP TABLE IF EXISTS pictures;`,
+	`DROP TABLE IF EXISTS stories;`,
+	`DROP TABLE IF EXISTS entities;`,
+	`DROP TABLE IF EXISTS webhooks;`,
+	`DROP TABLE IF EXISTS deliveries;`,
+	`DROP TABLE IF EXISTS searches;`,
+	`DROP TABLE IF EXISTS alerts;`,
+	`DROP TABLE IF EXISTS api_keys;`,
+	`DROP TABLE IF EXISTS topics;`,
 }
@@ -16,9 +16,21 @@ CREATE TABLE news(
     newspaper_subcategory varchar(255),
     newspaper_tags TEXT,
     newspaper_url varchar(255),
+    cluster_id varchar(255),
+    language varchar(8),
+    annotations TEXT,
+    html MEDIUMTEXT,
+    summary TEXT,
+    lead TEXT,
+    topics TEXT,
+    canonical_url varchar(255),
+    description TEXT,
+    section varchar(255),
+    modified timestamp null,
 
     primary key (id),
-    unique (gen_id)
+    unique (gen_id),
+    index (cluster_id)
 );
 
 DROP TABLE IF EXISTS pictures;
@@ -28,7 +40,142 @@ CREATE TABLE pictures(
     news_id bigint not null references news(gen_id),
     url varchar(255),
     caption varchar(255),
+    hash char(64),
+    width int,
+    height int,
+    mime_type varchar(255),
 
     primary key (id),
     CONSTRAINT pictures_news_id_foreign FOREIGN KEY (news_id) REFERENCES news(gen_id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+DROP TABLE IF EXISTS stories;
+
+CREATE TABLE stories(
+    id varchar(255) not null,
+    label varchar(255),
+    keywords TEXT,
+    first_datetime timestamp null,
+    last_datetime timestamp null,
+    newspapers TEXT,
+    news_ids TEXT,
+
+    primary key (id),
+    index (last_datetime)
+);
+
+DROP TABLE IF EXISTS entities;
+
+CREATE TABLE entities(
+    id bigint not null auto_increment,
+    news_id varchar(255) not null,
+    entity_id varchar(255) not null,
+    type varchar(32),
+    name varchar(255),
+    count int,
+
+    primary key (id),
+    index (news_id),
+    index (entity_id),
+    CONSTRAINT entities_news_id_foreign FOREIGN KEY (news_id) REFERENCES news(gen_id) ON DELETE CASCADE
+);
+
+DROP TABLE IF EXISTS webhooks;
+
+CREATE TABLE webhooks(
+    id varchar(255) not null,
+    url TEXT not null,
+    secret varchar(255),
+    newspapers TEXT,
+    tags TEXT,
+    keywords TEXT,
+    created timestamp null,
+
+    primary key (id)
+);
+
+DROP TABLE IF EXISTS deliveries;
+
+CREATE TABLE deliveries(
+    seq bigint not null auto_increment,
+    id varchar(255) not null,
+    webhook_id varchar(255) not null,
+    news_ids TEXT,
+    attempt int,
+    datetime timestamp null,
+    status_code int,
+    error TEXT,
+    duration bigint,
+    retry timestamp null,
+
+    primary key (seq),
+    index (webhook_id, datetime),
+    index (retry)
+);
+
+DROP TABLE IF EXISTS searches;
+
+CREATE TABLE searches(
+    id varchar(255) not null,
+    owner varchar(255) not null,
+    name varchar(255),
+    query TEXT,
+    newspapers TEXT,
+    language varchar(8),
+    frequency varchar(16),
+    notifier varchar(32),
+    target TEXT,
+    created timestamp null,
+
+    primary key (id),
+    index (owner)
+);
+
+DROP TABLE IF EXISTS alerts;
+
+CREATE TABLE alerts(
+    id varchar(255) not null,
+    search_id varchar(255) not null,
+    news_id varchar(255),
+    title varchar(255),
+    url varchar(255),
+    newspaper varchar(255),
+    datetime timestamp null,
+    created timestamp null,
+    delivered timestamp null,
+
+    primary key (id),
+    index (search_id, created),
+    index (delivered)
+);
+
+DROP TABLE IF EXISTS api_keys;
+
+CREATE TABLE api_keys(
+    id varchar(255) not null,
+    name varchar(255),
+    hash char(64) not null,
+    prefix varchar(32),
+    scopes varchar(255),
+    rate_limit int,
+    expires timestamp null,
+    revoked timestamp null,
+    created timestamp null,
+    requests bigint not null default 0,
+    last_used timestamp null,
+
+    primary key (id),
+    unique (hash)
+);
+
+DROP TABLE IF EXISTS topics;
+
+CREATE TABLE topics(
+    id varchar(255) not null,
+    names TEXT,
+    aliases TEXT,
+    created timestamp null,
+    updated timestamp null,
+
+    primary key (id)
+);
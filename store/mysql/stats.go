@@ -0,0 +1,103 @@
+package mysql
+
+import (
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+// groupColumns are the columns of the groups counted by the queries.
+// The tags are a list in a column, they are counted as they are read.
+var groupColumns = map[string]string{
+	store.GroupNewspaper:   "news.newspaper_id",
+	store.GroupCategory:    "news.newspaper_category",
+	store.GroupSubcategory: "news.newspaper_subcategory",
+	store.GroupAuthor:      "news.author",
+	store.GroupLocation:    "news.location",
+}
+
+func (s *Store) CountBy(filter store.Filter, group string, limit int) ([]*store.Count, error) {
+	column, exist := groupColumns[group]
+	if !exist {
+		filter.Fields = store.GroupFields(group)
+		list, err := s.GetAll(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		counter := store.NewCounter(group)
+		for _, n := range list {
+			counter.Add(n)
+		}
+		return counter.Counts(limit), nil
+	}
+
+	where, args := filterWhere(filter)
+	q := "SELECT TRIM(" + column + ") AS k, COUNT(*) FROM news WHERE " + where + " AND TRIM(IFNULL(" + column + ", '')) <> ''" +
+		" GROUP BY k ORDER BY COUNT(*) DESC, k"
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query counts")
+	}
+	defer rows.Close()
+
+	var list = make([]*store.Count, 0)
+	for rows.Next() {
+		c := &store.Count{}
+		if err := rows.Scan(&c.Key, &c.Count); err != nil {
+			return nil, errors.Wrap(err, "error scan counts")
+		}
+		list = append(list, c)
+	}
+
+	return list, rows.Err()
+}
+
+// Histogram reads the datetimes, they are bucketed in the location as they are read.
+func (s *Store) Histogram(filter store.Filter, interval string, group string, loc *time.Location) ([]*store.Bucket, error) {
+	filter.Fields = append([]string{"datetime"}, store.GroupFields(group)...)
+	list, err := s.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := store.NewHistogram(interval, group, loc)
+	for _, n := range list {
+		histogram.Add(n)
+	}
+
+	return histogram.Buckets(), nil
+}
+
+func (s *Store) TopAuthors(filter store.Filter, limit int) ([]*store.AuthorCount, error) {
+	where, args := filterWhere(filter)
+	q := "SELECT TRIM(news.author) AS a, IFNULL(news.newspaper_id, '') AS p, COUNT(*) FROM news WHERE " + where +
+		" AND TRIM(IFNULL(news.author, '')) <> '' GROUP BY a, p ORDER BY COUNT(*) DESC, a, p"
+	if limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query authors")
+	}
+	defer rows.Close()
+
+	var list = make([]*store.AuthorCount, 0)
+	for rows.Next() {
+		a := &store.AuthorCount{}
+		if err := rows.Scan(&a.Author, &a.Newspaper, &a.Count); err != nil {
+			return nil, errors.Wrap(err, "error scan authors")
+		}
+		list = append(list, a)
+	}
+
+	return list, rows.Err()
+}
@@ -2,21 +2,22 @@ package mysql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
-	"time"
 
 	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
 	_ "github.com/go-sql-driver/mysql"
 )
 
-//Database encapsulates database
+// Database encapsulates database
 type Store struct {
 	db *sql.DB
 }
 
-//Begins a transaction
+// Begins a transaction
 func (s *Store) begin() (tx *sql.Tx) {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -78,28 +79,36 @@ func (s *Store) Migrate() error {
 			return fmt.Errorf("sql exec error: %s; query: %q", err, q)
 		}
 	}
+
+	for _, q := range alter {
+		_, err := s.db.Exec(q)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "duplicate column") {
+			return fmt.Errorf("sql exec error: %s; query: %q", err, q)
+		}
+	}
 	return nil
 }
 
-func (s *Store) Insert(news []*model.News) error {
+func (s *Store) Insert(news []*model.News) ([]*model.News, error) {
 	tx := s.begin()
 
 	stmt, err := tx.Prepare("INSERT IGNORE INTO news(gen_id,author,datetime,title,location,content,tags,url," +
-		"newspaper_name,newspaper_id,newspaper_category,newspaper_subcategory,newspaper_tags,newspaper_url) " +
-		"VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+		"newspaper_name,newspaper_id,newspaper_category,newspaper_subcategory,newspaper_tags,newspaper_url,cluster_id,language,annotations,html,summary,lead_text,topics,canonical_url,description,section,modified) " +
+		"VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 	defer stmt.Close()
 
-	stmtPicture, err := tx.Prepare("INSERT INTO pictures(news_id, url, caption) VALUES (?,?,?)")
+	stmtPicture, err := tx.Prepare("INSERT INTO pictures(news_id, url, caption, hash, width, height, mime_type) VALUES (?,?,?,?,?,?,?)")
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 	defer stmtPicture.Close()
 
+	var inserted []*model.News
 	for _, n := range news {
 		var tags string
 		if n.Tags != nil && len(n.Tags) > 0 {
@@ -122,51 +131,175 @@ func (s *Store) Insert(news []*model.News) error {
 		}
 
 		res, err := stmt.Exec(n.Id, n.Author, n.Datetime, n.Title, n.Location, n.Content, tags, n.Url,
-			n.Source.NewspaperName, n.Source.NewspaperId, n.Source.OriginalCategory, n.Source.OriginalSubcategory, newspaperTags, n.Source.Url)
+			n.Source.NewspaperName, n.Source.NewspaperId, n.Source.OriginalCategory, n.Source.OriginalSubcategory, newspaperTags, n.Source.Url, n.ClusterId, n.Language, encodeAnnotations(n.Annotations), n.Html, n.Summary, n.Lead, strings.Join(n.Topics, ","),
+			n.CanonicalUrl, n.Description, n.Section, n.Modified)
 		if err != nil {
 			tx.Rollback()
-			return err
+			return nil, err
 		}
 
-		id, err := res.LastInsertId()
-		if err != nil {
+		// The news already exists, its pictures and entities are already inserted.
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			continue
+		}
+		inserted = append(inserted, n)
+
+		if err := insertEntities(tx, n); err != nil {
 			tx.Rollback()
-			return err
+			return nil, err
 		}
 
+		// pictures.news_id references news.gen_id
 		for _, pic := range n.Pictures {
-			stmtPicture.Exec(id, pic.ImageUrl, pic.Caption)
+			stmtPicture.Exec(n.Id, pic.ImageUrl, pic.Caption, pic.Hash, pic.Width, pic.Height, pic.MimeType)
 		}
 
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return inserted, nil
 }
 
-func (s *Store) GetAll(from time.Time, until time.Time) ([]*model.News, error) {
-	var err error
-	var rows *sql.Rows
+func (s *Store) GetAll(filter store.Filter) ([]*model.News, error) {
+	where, args := filterWhere(filter)
+	return s.queryNews(filter, where, args...)
+}
 
-	stmt, err := s.db.Prepare("SELECT news.id, news.gen_id, news.author, news.datetime, news.title, news.location, news.content, news.tags, news.url, news.newspaper_name, news.newspaper_id, news.newspaper_category, news.newspaper_subcategory, news.newspaper_tags, news.newspaper_url, pictures.url, pictures.caption FROM news WHERE news.datetime >= $1 and news.datetime <= $2 INNER JOIN pictures ON pictures.news_id = news.id ORDER BY news.id")
-	if err != nil {
-		return nil, err
+// filterWhere returns the where clause of the filter, along with its arguments.
+func filterWhere(filter store.Filter) (string, []interface{}) {
+	var where = []string{"1 = 1"}
+	var args []interface{}
+
+	if !filter.From.IsZero() {
+		where = append(where, "news.datetime <= ?")
+		args = append(args, filter.From)
+	}
+
+	if !filter.Until.IsZero() {
+		where = append(where, "news.datetime >= ?")
+		args = append(args, filter.Until)
 	}
 
-	if !from.IsZero() && !until.IsZero() {
-		rows, err = stmt.Query(from, until)
-	} else if !from.IsZero() {
-		rows, err = stmt.Query(from, nil)
-	} else if !until.IsZero() {
-		rows, err = stmt.Query(nil, until)
-	} else {
-		rows, err = stmt.Query(nil, nil)
+	if filter.Language != "" {
+		where = append(where, "news.language = ?")
+		args = append(args, filter.Language)
 	}
 
+	if filter.Newspaper != "" {
+		where = append(where, "news.newspaper_id = ?")
+		args = append(args, filter.Newspaper)
+	}
+
+	// The tags are stored separated by commas, the tag is matched between two.
+	if filter.Tag != "" {
+		where = append(where, "CONCAT(',', LOWER(IFNULL(news.tags, '')), ',', LOWER(IFNULL(news.newspaper_tags, '')), ',') LIKE ? ESCAPE '!'")
+		args = append(args, "%,"+likeEscape(strings.ToLower(strings.TrimSpace(filter.Tag)))+",%")
+	}
+
+	// The topics are stored separated by commas too.
+	if filter.Topic != "" {
+		where = append(where, "CONCAT(',', IFNULL(news.topics, ''), ',') LIKE ? ESCAPE '!'")
+		args = append(args, "%,"+likeEscape(filter.Topic)+",%")
+	}
+
+	if filter.Entity != "" || filter.EntityType != "" {
+		var entityWhere = []string{"1 = 1"}
+		if filter.Entity != "" {
+			entityWhere = append(entityWhere, "entity_id = ?")
+			args = append(args, filter.Entity)
+		}
+		if filter.EntityType != "" {
+			entityWhere = append(entityWhere, "type = ?")
+			args = append(args, filter.EntityType)
+		}
+
+		where = append(where, "news.gen_id IN (SELECT news_id FROM entities WHERE "+strings.Join(entityWhere, " AND ")+")")
+	}
+
+	return strings.Join(where, " AND "), args
+}
+
+// likeEscape escapes the wildcards of a LIKE pattern, with the ! escape character.
+func likeEscape(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+// newsRow is a row read by queryNews, the columns that are not fields of the news are decoded after the scan.
+type newsRow struct {
+	pk          int64
+	news        model.News
+	tags        string
+	sourceTags  string
+	topics      string
+	annotations string
+	picture     model.Picture
+}
+
+// newsColumn is a column read by queryNews if any of its fields is selected, see store.Filter.Fields.
+type newsColumn struct {
+	fields []string
+	expr   string
+	dest   func(r *newsRow) interface{}
+}
+
+var newsColumns = []newsColumn{
+	{[]string{"author"}, "news.author", func(r *newsRow) interface{} { return &r.news.Author }},
+	{[]string{"datetime"}, "news.datetime", func(r *newsRow) interface{} { return &r.news.Datetime }},
+	{[]string{"title"}, "news.title", func(r *newsRow) interface{} { return &r.news.Title }},
+	{[]string{"location"}, "news.location", func(r *newsRow) interface{} { return &r.news.Location }},
+	{[]string{"content", "paragraphs"}, "news.content", func(r *newsRow) interface{} { return &r.news.Content }},
+	{[]string{"tags"}, "news.tags", func(r *newsRow) interface{} { return &r.tags }},
+	{[]string{"url"}, "news.url", func(r *newsRow) interface{} { return &r.news.Url }},
+	{[]string{"source.name"}, "news.newspaper_name", func(r *newsRow) interface{} { return &r.news.Source.NewspaperName }},
+	{[]string{"source.id"}, "news.newspaper_id", func(r *newsRow) interface{} { return &r.news.Source.NewspaperId }},
+	{[]string{"source.category"}, "news.newspaper_category", func(r *newsRow) interface{} { return &r.news.Source.OriginalCategory }},
+	{[]string{"source.subcategory"}, "news.newspaper_subcategory", func(r *newsRow) interface{} { return &r.news.Source.OriginalSubcategory }},
+	{[]string{"source.tags"}, "news.newspaper_tags", func(r *newsRow) interface{} { return &r.sourceTags }},
+	{[]string{"source.url"}, "news.newspaper_url", func(r *newsRow) interface{} { return &r.news.Source.Url }},
+	{[]string{"cluster_id"}, "IFNULL(news.cluster_id, '')", func(r *newsRow) interface{} { return &r.news.ClusterId }},
+	{[]string{"language"}, "IFNULL(news.language, '')", func(r *newsRow) interface{} { return &r.news.Language }},
+	{[]string{"annotations"}, "IFNULL(news.annotations, '')", func(r *newsRow) interface{} { return &r.annotations }},
+	{[]string{"html"}, "IFNULL(news.html, '')", func(r *newsRow) interface{} { return &r.news.Html }},
+	{[]string{"summary"}, "IFNULL(news.summary, '')", func(r *newsRow) interface{} { return &r.news.Summary }},
+	{[]string{"lead"}, "IFNULL(news.lead_text, '')", func(r *newsRow) interface{} { return &r.news.Lead }},
+	{[]string{"topics"}, "IFNULL(news.topics, '')", func(r *newsRow) interface{} { return &r.topics }},
+	{[]string{"canonical_url"}, "IFNULL(news.canonical_url, '')", func(r *newsRow) interface{} { return &r.news.CanonicalUrl }},
+	{[]string{"description"}, "IFNULL(news.description, '')", func(r *newsRow) interface{} { return &r.news.Description }},
+	{[]string{"section"}, "IFNULL(news.section, '')", func(r *newsRow) interface{} { return &r.news.Section }},
+	{[]string{"modified"}, "news.modified", func(r *newsRow) interface{} { return &r.news.Modified }},
+	{[]string{"pictures"}, "IFNULL(pictures.url, '')", func(r *newsRow) interface{} { return &r.picture.ImageUrl }},
+	{[]string{"pictures"}, "IFNULL(pictures.caption, '')", func(r *newsRow) interface{} { return &r.picture.Caption }},
+	{[]string{"pictures"}, "IFNULL(pictures.hash, '')", func(r *newsRow) interface{} { return &r.picture.Hash }},
+	{[]string{"pictures"}, "IFNULL(pictures.width, 0)", func(r *newsRow) interface{} { return &r.picture.Width }},
+	{[]string{"pictures"}, "IFNULL(pictures.height, 0)", func(r *newsRow) interface{} { return &r.picture.Height }},
+	{[]string{"pictures"}, "IFNULL(pictures.mime_type, '')", func(r *newsRow) interface{} { return &r.picture.MimeType }},
+}
+
+// queryNews returns the news matching the where clause along with their pictures, latest first.
+// Only the columns of the fields selected by the filter are read, the pictures and the entities too.
+func (s *Store) queryNews(filter store.Filter, where string, args ...interface{}) ([]*model.News, error) {
+	var columns []newsColumn
+	var exprs = []string{"news.id", "news.gen_id"}
+	for _, c := range newsColumns {
+		for _, field := range c.fields {
+			if filter.Selects(field) {
+				columns = append(columns, c)
+				exprs = append(exprs, c.expr)
+				break
+			}
+		}
+	}
+
+	var join string
+	if filter.Selects("pictures") {
+		join = " LEFT JOIN pictures ON pictures.news_id = news.gen_id"
+	}
+
+	rows, err := s.db.Query("SELECT "+strings.Join(exprs, ", ")+" FROM news"+join+" WHERE "+where+" ORDER BY news.datetime DESC, news.id", args...)
 	if err != nil {
 		return nil, err
 	}
@@ -174,39 +307,123 @@ func (s *Store) GetAll(from time.Time, until time.Time) ([]*model.News, error) {
 	defer rows.Close()
 
 	var list []*model.News
-	var lastNews *model.News
-	var lastNewsPk int64
+	var last *newsRow
 	for rows.Next() {
-		var pk int64
-		var tags string
-		n := model.News{}
-		newsSource := model.NewsSource{}
-		var sourceTags string
+		row := &newsRow{}
 
-		picture := model.Picture{}
-		rows.Scan(&pk, &n.Id, &n.Author, &n.Datetime, &n.Title, &n.Location, &n.Content, &tags, &n.Url, &newsSource.NewspaperName, &newsSource.NewspaperId, &newsSource.OriginalCategory, &newsSource.OriginalSubcategory, &sourceTags, &newsSource.Url, &picture.ImageUrl, &picture.Caption)
+		var dest = []interface{}{&row.pk, &row.news.Id}
+		for _, c := range columns {
+			dest = append(dest, c.dest(row))
+		}
 
-		newsSource.Tags = strings.Split(sourceTags, ",")
-		n.Tags = strings.Split(tags, ",")
-		n.Source = newsSource
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
 
-		if lastNewsPk == pk && picture.ImageUrl != "" {
-			lastNews.Pictures = append(lastNews.Pictures, &picture)
+		if last != nil && last.pk == row.pk {
+			if row.picture.ImageUrl != "" {
+				picture := row.picture
+				last.news.Pictures = append(last.news.Pictures, &picture)
+			}
 			continue
 		}
 
-		if picture.ImageUrl != "" {
+		n := &row.news
+		if filter.Selects("tags") {
+			n.Tags = strings.Split(row.tags, ",")
+		}
+		if filter.Selects("source.tags") {
+			n.Source.Tags = strings.Split(row.sourceTags, ",")
+		}
+		n.Topics = splitList(row.topics)
+		n.Annotations = decodeAnnotations(row.annotations)
+		n.SetContent(n.Content)
+
+		if row.picture.ImageUrl != "" {
+			picture := row.picture
 			n.Pictures = append(n.Pictures, &picture)
 		}
 
-		list = append(list, &n)
-		lastNews = &n
-		lastNewsPk = pk
+		list = append(list, n)
+		last = row
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if filter.Selects("entities") {
+		if err := s.loadEntities(list); err != nil {
+			return nil, err
+		}
 	}
 
 	return list, nil
 }
 
+func (s *Store) Update(news []*model.News) error {
+	if len(news) == 0 {
+		return nil
+	}
+
+	tx := s.begin()
+
+	stmt, err := tx.Prepare("UPDATE news SET author = ?, datetime = ?, title = ?, location = ?, content = ?, tags = ?, url = ?, language = ?, annotations = ?, html = ?, summary = ?, lead_text = ?, topics = ?, canonical_url = ?, description = ?, section = ?, modified = ? WHERE gen_id = ?")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	stmtPicture, err := tx.Prepare("INSERT INTO pictures(news_id, url, caption, hash, width, height, mime_type) VALUES (?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmtPicture.Close()
+
+	for _, n := range news {
+		_, err := stmt.Exec(n.Author, n.Datetime, n.Title, n.Location, n.Content, strings.Join(n.Tags, ","), n.Url, n.Language, encodeAnnotations(n.Annotations), n.Html, n.Summary, n.Lead, strings.Join(n.Topics, ","),
+			n.CanonicalUrl, n.Description, n.Section, n.Modified, n.Id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM pictures WHERE news_id = ?", n.Id); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := insertEntities(tx, n); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		for _, pic := range n.Pictures {
+			if _, err := stmtPicture.Exec(n.Id, pic.ImageUrl, pic.Caption, pic.Hash, pic.Width, pic.Height, pic.MimeType); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) Get(id string) (*model.News, error) {
+	list, err := s.queryNews(store.Filter{}, "news.gen_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
 func (s *Store) GetByKeywords(keywords []string) ([]*model.News, error) {
 	return nil, nil
 }
@@ -214,3 +431,32 @@ func (s *Store) GetByKeywords(keywords []string) ([]*model.News, error) {
 func (s *Store) GetByProvider(provider string) ([]*model.News, error) {
 	return nil, nil
 }
+
+func (s *Store) GetByCluster(clusterId string) ([]*model.News, error) {
+	return s.queryNews(store.Filter{}, "news.cluster_id = ?", clusterId)
+}
+
+// The annotations are stored as a JSON object.
+func encodeAnnotations(annotations map[string]string) string {
+	if len(annotations) == 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(annotations)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func decodeAnnotations(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+
+	var annotations map[string]string
+	if err := json.Unmarshal([]byte(s), &annotations); err != nil {
+		return nil
+	}
+	return annotations
+}
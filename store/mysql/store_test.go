@@ -0,0 +1,73 @@
+package mysql
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+func TestGetAllFields(t *testing.T) {
+	// The test needs a database, the news it inserts are left there.
+	address := os.Getenv("MYSQL_ADDRESS")
+	if address == "" {
+		t.Skip("MYSQL_ADDRESS is not set")
+	}
+
+	s, err := NewStore(address, os.Getenv("MYSQL_USERNAME"), os.Getenv("MYSQL_PASSWORD"), os.Getenv("MYSQL_DATABASE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The newspaper of the test run, the news of the previous runs are not read.
+	newspaper := fmt.Sprintf("test-%d", time.Now().UnixNano())
+
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	_, err = s.Insert([]*model.News{{
+		Id: newspaper, Author: "Ahmad", Datetime: day, Title: "Kilang terbakar", Content: "Sebuah kilang terbakar.", Url: "https://www.bharian.com.my/berita/a",
+		Html: "<p>Sebuah kilang terbakar.</p>", Summary: "Sebuah kilang terbakar.", Lead: "Sebuah kilang terbakar.", Tags: []string{"kebakaran"},
+		Source:   model.NewsSource{NewspaperName: "Berita Harian", NewspaperId: newspaper, Url: "https://www.bharian.com.my"},
+		Pictures: []*model.Picture{{ImageUrl: "https://www.bharian.com.my/a.jpg", Caption: "Kilang"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		fields []string
+		check  func(n *model.News) bool
+	}{
+		// The selected fields are read.
+		{[]string{"id", "title", "source.name"}, func(n *model.News) bool {
+			return n.Title == "Kilang terbakar" && n.Source.NewspaperName == "Berita Harian" &&
+				n.Content == "" && n.Author == "" && n.Url == "" && n.Html == "" && n.Summary == "" && n.Lead == "" &&
+				n.Source.Url == "" && len(n.Pictures) == 0 && len(n.Tags) == 0
+		}},
+		{[]string{"id", "paragraphs", "pictures"}, func(n *model.News) bool {
+			return n.Content == "Sebuah kilang terbakar." && len(n.Paragraphs) == 1 && len(n.Pictures) == 1 &&
+				n.Pictures[0].Caption == "Kilang" && n.Title == "" && n.Html == ""
+		}},
+		{[]string{"id", "html", "summary", "lead", "tags"}, func(n *model.News) bool {
+			return n.Html == "<p>Sebuah kilang terbakar.</p>" && n.Summary == "Sebuah kilang terbakar." && n.Lead == "Sebuah kilang terbakar." &&
+				len(n.Tags) == 1 && n.Content == "" && n.Title == ""
+		}},
+		// Every field is read without the fields.
+		{nil, func(n *model.News) bool {
+			return n.Title == "Kilang terbakar" && n.Author == "Ahmad" && n.Content == "Sebuah kilang terbakar." && n.Lead == "Sebuah kilang terbakar." &&
+				n.Source.Url == "https://www.bharian.com.my" && len(n.Pictures) == 1
+		}},
+	}
+
+	for _, test := range tests {
+		list, err := s.GetAll(store.Filter{Newspaper: newspaper, Fields: test.fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != 1 || list[0].Id != newspaper || !test.check(list[0]) {
+			t.Errorf("%v: got %+v", test.fields, list)
+		}
+	}
+}
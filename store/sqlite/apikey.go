@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveApiKey(key *model.ApiKey) error {
+	tx := s.begin()
+
+	// An upsert on the id would still fail on the unique hash of the same row.
+	res, err := tx.Exec("UPDATE api_keys SET name = ?, hash = ?, prefix = ?, scopes = ?, rate_limit = ?, expires = ?, revoked = ? WHERE id = ?",
+		key.Name, key.Hash, key.Prefix, strings.Join(key.Scopes, ","), key.RateLimit, nullTime(key.Expires), nullTime(key.Revoked), key.Id)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error update api key")
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		_, err := tx.Exec("INSERT INTO api_keys(id, name, hash, prefix, scopes, rate_limit, expires, revoked, created) VALUES (?,?,?,?,?,?,?,?,?)",
+			key.Id, key.Name, key.Hash, key.Prefix, strings.Join(key.Scopes, ","), key.RateLimit,
+			nullTime(key.Expires), nullTime(key.Revoked), key.Created.UTC())
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error insert api key")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) GetApiKeys() ([]*model.ApiKey, error) {
+	return s.queryApiKeys("1 = 1")
+}
+
+func (s *Store) GetApiKey(id string) (*model.ApiKey, error) {
+	list, err := s.queryApiKeys("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) AddApiKeyUsage(id string, requests int64, lastUsed time.Time) error {
+	_, err := s.db.Exec("UPDATE api_keys SET requests = requests + ?, last_used = ? WHERE id = ?", requests, lastUsed.UTC(), id)
+	if err != nil {
+		return errors.Wrap(err, "error update api key usage")
+	}
+	return nil
+}
+
+func (s *Store) queryApiKeys(where string, args ...interface{}) ([]*model.ApiKey, error) {
+	rows, err := s.db.Query("SELECT id, name, hash, prefix, scopes, rate_limit, expires, revoked, created, requests, last_used FROM api_keys WHERE "+where+" ORDER BY created", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query api keys")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.ApiKey, 0)
+	for rows.Next() {
+		var scopes string
+		v := &model.ApiKey{}
+
+		if err := rows.Scan(&v.Id, &v.Name, &v.Hash, &v.Prefix, &scopes, &v.RateLimit, &v.Expires, &v.Revoked, &v.Created, &v.Requests, &v.LastUsed); err != nil {
+			return nil, errors.Wrap(err, "error scan api keys")
+		}
+
+		v.Scopes = splitList(scopes)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
+
+// nullTime returns the time in UTC, or nil for NULL.
+func nullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
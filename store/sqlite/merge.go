@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) MergeNews(merges []*store.Merge) error {
+	if len(merges) == 0 {
+		return nil
+	}
+
+	tx := s.begin()
+
+	for _, m := range merges {
+		for _, id := range m.Duplicates {
+			for _, q := range []string{
+				"DELETE FROM pictures WHERE news_id IN (SELECT rowid FROM news WHERE gen_id = ?)",
+				"DELETE FROM entities WHERE news_id = ?",
+				"DELETE FROM news WHERE gen_id = ?",
+			} {
+				if _, err := tx.Exec(q, id); err != nil {
+					tx.Rollback()
+					return errors.Wrap(err, "error delete duplicate")
+				}
+			}
+		}
+
+		// The pictures refer to the rowid, they are kept.
+		from := m.Id
+		if m.From != "" {
+			from = m.From
+		}
+		if _, err := tx.Exec("UPDATE news SET gen_id = ?, url = ? WHERE gen_id = ?", m.Id, m.Url, from); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error update news id")
+		}
+		if _, err := tx.Exec("UPDATE entities SET news_id = ? WHERE news_id = ?", m.Id, from); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error update entities")
+		}
+
+		for _, id := range append([]string{from}, m.Duplicates...) {
+			if _, err := tx.Exec("UPDATE news SET cluster_id = ? WHERE cluster_id = ?", m.Id, id); err != nil {
+				tx.Rollback()
+				return errors.Wrap(err, "error update cluster id")
+			}
+		}
+	}
+
+	// The stories, the deliveries and the alerts keep referring to the news.
+	moved := store.MovedIds(merges)
+	for _, table := range []string{"stories", "deliveries"} {
+		if err := rewriteNewsIds(tx, table, moved); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for from, id := range moved {
+		if _, err := tx.Exec("UPDATE alerts SET news_id = ? WHERE news_id = ?", id, from); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error update alerts")
+		}
+	}
+
+	return tx.Commit()
+}
+
+// rewriteNewsIds replaces the moved ids of the comma separated news_ids of the rows of the table, see store.RewriteIds.
+func rewriteNewsIds(tx *sql.Tx, table string, moved map[string]string) error {
+	rows, err := tx.Query("SELECT id, news_ids FROM " + table)
+	if err != nil {
+		return errors.Wrap(err, "error query "+table)
+	}
+
+	var changed = make(map[string][]string)
+	for rows.Next() {
+		var id string
+		var newsIds sql.NullString
+		if err := rows.Scan(&id, &newsIds); err != nil {
+			rows.Close()
+			return errors.Wrap(err, "error scan "+table)
+		}
+
+		if ids, ok := store.RewriteIds(splitList(newsIds.String), moved); ok {
+			changed[id] = ids
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error query "+table)
+	}
+
+	for id, ids := range changed {
+		if _, err := tx.Exec("UPDATE "+table+" SET news_ids = ? WHERE id = ?", strings.Join(ids, ","), id); err != nil {
+			return errors.Wrap(err, "error update "+table)
+		}
+	}
+	return nil
+}
@@ -16,14 +16,152 @@ var migrate = []string{
 		newspaper_category TEXT,
 		newspaper_subcategory TEXT,
 		newspaper_tags TEXT, 
-		newspaper_url TEXT
+		newspaper_url TEXT,
+		cluster_id TEXT,
+		language TEXT,
+		annotations TEXT,
+		html TEXT,
+		summary TEXT,
+		lead TEXT,
+		topics TEXT,
+		canonical_url TEXT,
+		description TEXT,
+		section TEXT,
+		modified TIMESTAMP
 	);
 	`,
 	`
 	CREATE TABLE IF NOT EXISTS pictures(
 		news_id INTEGER,
 		url TEXT,
-		caption TEXT
+		caption TEXT,
+		hash TEXT,
+		width INTEGER,
+		height INTEGER,
+		mime_type TEXT
+	);
+	`,
+}
+
+// alter adds the columns introduced after the tables were first created.
+// The duplicate column errors on an up to date database are ignored.
+var alter = []string{
+	`ALTER TABLE pictures ADD COLUMN hash TEXT;`,
+	`ALTER TABLE pictures ADD COLUMN width INTEGER;`,
+	`ALTER TABLE pictures ADD COLUMN height INTEGER;`,
+	`ALTER TABLE pictures ADD COLUMN mime_type TEXT;`,
+	`ALTER TABLE news ADD COLUMN cluster_id TEXT;`,
+	`CREATE INDEX IF NOT EXISTS news_cluster_id ON news(cluster_id);`,
+	`ALTER TABLE news ADD COLUMN language TEXT;`,
+	`ALTER TABLE news ADD COLUMN annotations TEXT;`,
+	`ALTER TABLE news ADD COLUMN html TEXT;`,
+	`ALTER TABLE news ADD COLUMN summary TEXT;`,
+	`ALTER TABLE news ADD COLUMN lead TEXT;`,
+	`ALTER TABLE news ADD COLUMN topics TEXT;`,
+	`ALTER TABLE news ADD COLUMN canonical_url TEXT;`,
+	`ALTER TABLE news ADD COLUMN description TEXT;`,
+	`ALTER TABLE news ADD COLUMN section TEXT;`,
+	`ALTER TABLE news ADD COLUMN modified TIMESTAMP;`,
+	`
+	CREATE TABLE IF NOT EXISTS entities(
+		news_id TEXT NOT NULL,
+		entity_id TEXT NOT NULL,
+		type TEXT,
+		name TEXT,
+		count INTEGER
+	);
+	`,
+	`CREATE INDEX IF NOT EXISTS entities_news_id ON entities(news_id);`,
+	`CREATE INDEX IF NOT EXISTS entities_entity_id ON entities(entity_id);`,
+	`
+	CREATE TABLE IF NOT EXISTS stories(
+		id TEXT NOT NULL UNIQUE,
+		label TEXT,
+		keywords TEXT,
+		first_datetime TIMESTAMP,
+		last_datetime TIMESTAMP,
+		newspapers TEXT,
+		news_ids TEXT
+	);
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS webhooks(
+		id TEXT NOT NULL UNIQUE,
+		url TEXT NOT NULL,
+		secret TEXT,
+		newspapers TEXT,
+		tags TEXT,
+		keywords TEXT,
+		created TIMESTAMP
+	);
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS deliveries(
+		id TEXT NOT NULL,
+		webhook_id TEXT NOT NULL,
+		news_ids TEXT,
+		attempt INTEGER,
+		datetime TIMESTAMP,
+		status_code INTEGER,
+		error TEXT,
+		duration INTEGER
+	);
+	`,
+	`CREATE INDEX IF NOT EXISTS deliveries_webhook_id ON deliveries(webhook_id, datetime);`,
+	`ALTER TABLE deliveries ADD COLUMN retry TIMESTAMP;`,
+	`CREATE INDEX IF NOT EXISTS deliveries_retry ON deliveries(retry);`,
+	`
+	CREATE TABLE IF NOT EXISTS searches(
+		id TEXT NOT NULL UNIQUE,
+		owner TEXT NOT NULL,
+		name TEXT,
+		query TEXT,
+		newspapers TEXT,
+		language TEXT,
+		frequency TEXT,
+		notifier TEXT,
+		target TEXT,
+		created TIMESTAMP
+	);
+	`,
+	`CREATE INDEX IF NOT EXISTS searches_owner ON searches(owner);`,
+	`
+	CREATE TABLE IF NOT EXISTS alerts(
+		id TEXT NOT NULL UNIQUE,
+		search_id TEXT NOT NULL,
+		news_id TEXT,
+		title TEXT,
+		url TEXT,
+		newspaper TEXT,
+		datetime TIMESTAMP,
+		created TIMESTAMP,
+		delivered TIMESTAMP
+	);
+	`,
+	`CREATE INDEX IF NOT EXISTS alerts_search_id ON alerts(search_id, created);`,
+	`CREATE INDEX IF NOT EXISTS alerts_delivered ON alerts(delivered);`,
+	`
+	CREATE TABLE IF NOT EXISTS api_keys(
+		id TEXT NOT NULL UNIQUE,
+		name TEXT,
+		hash TEXT NOT NULL UNIQUE,
+		prefix TEXT,
+		scopes TEXT,
+		rate_limit INTEGER,
+		expires TIMESTAMP,
+		revoked TIMESTAMP,
+		created TIMESTAMP,
+		requests INTEGER NOT NULL DEFAULT 0,
+		last_used TIMESTAMP
+	);
+	`,
+	`
+	CREATE TABLE IF NOT EXISTS topics(
+		id TEXT NOT NULL UNIQUE,
+		names TEXT,
+		aliases TEXT,
+		created TIMESTAMP,
+		updated TIMESTAMP
 	);
 	`,
 }
@@ -31,4 +169,12 @@ var migrate = []string{
 var drop = []string{
 	`DROP TABLE IF EXISTS news;`,
 	`DROP TABLE IF EXISTS pictures;`,
+	`DROP TABLE IF EXISTS stories;`,
+	`DROP TABLE IF EXISTS entities;`,
+	`DROP TABLE IF EXISTS webhooks;`,
+	`DROP TABLE IF EXISTS deliveries;`,
+	`DROP TABLE IF EXISTS searches;`,
+	`DROP TABLE IF EXISTS alerts;`,
+	`DROP TABLE IF EXISTS api_keys;`,
+	`DROP TABLE IF EXISTS topics;`,
 }
@@ -14,13 +14,144 @@ CREATE TABLE news(
     newspaper_category TEXT,
     newspaper_subcategory TEXT,
     newspaper_tags TEXT, 
-    newspaper_url TEXT
+    newspaper_url TEXT,
+    cluster_id TEXT,
+    language TEXT,
+    annotations TEXT,
+    html TEXT,
+    summary TEXT,
+    lead TEXT,
+    topics TEXT,
+    canonical_url TEXT,
+    description TEXT,
+    section TEXT,
+    modified TIMESTAMP
 );
 
+CREATE INDEX news_cluster_id ON news(cluster_id);
+
 DROP TABLE IF EXISTS pictures;
 
 CREATE TABLE pictures(
     news_id INTEGER,
     url TEXT,
-    caption TEXT
-);
\ No newline at end of file
+    caption TEXT,
+    hash TEXT,
+    width INTEGER,
+    height INTEGER,
+    mime_type TEXT
+);
+
+DROP TABLE IF EXISTS stories;
+
+CREATE TABLE stories(
+    id TEXT NOT NULL UNIQUE,
+    label TEXT,
+    keywords TEXT,
+    first_datetime TIMESTAMP,
+    last_datetime TIMESTAMP,
+    newspapers TEXT,
+    news_ids TEXT
+);
+
+DROP TABLE IF EXISTS entities;
+
+CREATE TABLE entities(
+    news_id TEXT NOT NULL,
+    entity_id TEXT NOT NULL,
+    type TEXT,
+    name TEXT,
+    count INTEGER
+);
+
+CREATE INDEX entities_news_id ON entities(news_id);
+CREATE INDEX entities_entity_id ON entities(entity_id);
+
+DROP TABLE IF EXISTS webhooks;
+
+CREATE TABLE webhooks(
+    id TEXT NOT NULL UNIQUE,
+    url TEXT NOT NULL,
+    secret TEXT,
+    newspapers TEXT,
+    tags TEXT,
+    keywords TEXT,
+    created TIMESTAMP
+);
+
+DROP TABLE IF EXISTS deliveries;
+
+CREATE TABLE deliveries(
+    id TEXT NOT NULL,
+    webhook_id TEXT NOT NULL,
+    news_ids TEXT,
+    attempt INTEGER,
+    datetime TIMESTAMP,
+    status_code INTEGER,
+    error TEXT,
+    duration INTEGER,
+    retry TIMESTAMP
+);
+
+CREATE INDEX deliveries_webhook_id ON deliveries(webhook_id, datetime);
+CREATE INDEX deliveries_retry ON deliveries(retry);
+
+DROP TABLE IF EXISTS searches;
+
+CREATE TABLE searches(
+    id TEXT NOT NULL UNIQUE,
+    owner TEXT NOT NULL,
+    name TEXT,
+    query TEXT,
+    newspapers TEXT,
+    language TEXT,
+    frequency TEXT,
+    notifier TEXT,
+    target TEXT,
+    created TIMESTAMP
+);
+
+CREATE INDEX searches_owner ON searches(owner);
+
+DROP TABLE IF EXISTS alerts;
+
+CREATE TABLE alerts(
+    id TEXT NOT NULL UNIQUE,
+    search_id TEXT NOT NULL,
+    news_id TEXT,
+    title TEXT,
+    url TEXT,
+    newspaper TEXT,
+    datetime TIMESTAMP,
+    created TIMESTAMP,
+    delivered TIMESTAMP
+);
+
+CREATE INDEX alerts_search_id ON alerts(search_id, created);
+CREATE INDEX alerts_delivered ON alerts(delivered);
+
+DROP TABLE IF EXISTS api_keys;
+
+CREATE TABLE api_keys(
+    id TEXT NOT NULL UNIQUE,
+    name TEXT,
+    hash TEXT NOT NULL UNIQUE,
+    prefix TEXT,
+    scopes TEXT,
+    rate_limit INTEGER,
+    expires TIMESTAMP,
+    revoked TIMESTAMP,
+    created TIMESTAMP,
+    requests INTEGER NOT NULL DEFAULT 0,
+    last_used TIMESTAMP
+);
+
+DROP TABLE IF EXISTS topics;
+
+CREATE TABLE topics(
+    id TEXT NOT NULL UNIQUE,
+    names TEXT,
+    aliases TEXT,
+    created TIMESTAMP,
+    updated TIMESTAMP
+);
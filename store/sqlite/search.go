@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveSearch(search *model.SavedSearch) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO searches(id, owner, name, query, newspapers, language, frequency, notifier, target, created) VALUES (?,?,?,?,?,?,?,?,?,?)",
+		search.Id, search.Owner, search.Name, search.Query, strings.Join(search.Newspapers, ","), search.Language,
+		search.Frequency, search.Notifier, search.Target, search.Created.UTC())
+	if err != nil {
+		return errors.Wrap(err, "error insert search")
+	}
+	return nil
+}
+
+func (s *Store) GetSearches(owner string) ([]*model.SavedSearch, error) {
+	if owner == "" {
+		return s.querySearches("1 = 1")
+	}
+	return s.querySearches("owner = ?", owner)
+}
+
+func (s *Store) GetSearch(id string) (*model.SavedSearch, error) {
+	list, err := s.querySearches("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) DeleteSearch(id string) error {
+	tx := s.begin()
+
+	res, err := tx.Exec("DELETE FROM searches WHERE id = ?", id)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error delete search")
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return store.ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM alerts WHERE search_id = ?", id); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error delete alerts")
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) querySearches(where string, args ...interface{}) ([]*model.SavedSearch, error) {
+	rows, err := s.db.Query("SELECT id, owner, name, query, newspapers, language, frequency, notifier, target, created FROM searches WHERE "+where+" ORDER BY created", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query searches")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.SavedSearch, 0)
+	for rows.Next() {
+		var newspapers string
+		v := &model.SavedSearch{}
+
+		if err := rows.Scan(&v.Id, &v.Owner, &v.Name, &v.Query, &newspapers, &v.Language, &v.Frequency, &v.Notifier, &v.Target, &v.Created); err != nil {
+			return nil, errors.Wrap(err, "error scan searches")
+		}
+
+		v.Newspapers = splitList(newspapers)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *Store) SaveAlerts(alerts []*model.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	tx := s.begin()
+
+	stmt, err := tx.Prepare("INSERT OR IGNORE INTO alerts(id, search_id, news_id, title, url, newspaper, datetime, created) VALUES (?,?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error prepare alerts")
+	}
+
+	defer stmt.Close()
+
+	for _, v := range alerts {
+		if _, err := stmt.Exec(v.Id, v.SearchId, v.NewsId, v.Title, v.Url, v.Newspaper, v.Datetime.UTC(), v.Created.UTC()); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error insert alert")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) GetAlerts(searchId string, limit int) ([]*model.Alert, error) {
+	return s.queryAlerts("search_id = ? ORDER BY created DESC, rowid DESC LIMIT ?", searchId, limit)
+}
+
+func (s *Store) GetPendingAlerts() ([]*model.Alert, error) {
+	return s.queryAlerts("delivered IS NULL ORDER BY created, rowid")
+}
+
+func (s *Store) SetDelivered(ids []string, delivered time.Time) error {
+	tx := s.begin()
+
+	stmt, err := tx.Prepare("UPDATE alerts SET delivered = ? WHERE id = ?")
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error prepare alerts")
+	}
+
+	defer stmt.Close()
+
+	for _, id := range ids {
+		if _, err := stmt.Exec(delivered.UTC(), id); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error update alert")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) queryAlerts(where string, args ...interface{}) ([]*model.Alert, error) {
+	rows, err := s.db.Query("SELECT id, search_id, news_id, title, url, newspaper, datetime, created, delivered FROM alerts WHERE "+where, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query alerts")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.Alert, 0)
+	for rows.Next() {
+		v := &model.Alert{}
+
+		if err := rows.Scan(&v.Id, &v.SearchId, &v.NewsId, &v.Title, &v.Url, &v.Newspaper, &v.Datetime, &v.Created, &v.Delivered); err != nil {
+			return nil, errors.Wrap(err, "error scan alerts")
+		}
+
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
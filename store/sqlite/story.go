@@ -0,0 +1,95 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveStories(since time.Time, stories []*model.Story) error {
+	tx := s.begin()
+
+	if _, err := tx.Exec("DELETE FROM stories WHERE last_datetime >= ?", since.UTC()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error delete stories")
+	}
+
+	stmt, err := tx.Prepare("INSERT OR REPLACE INTO stories(id, label, keywords, first_datetime, last_datetime, newspapers, news_ids) VALUES (?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error insert stories")
+	}
+	defer stmt.Close()
+
+	for _, v := range stories {
+		_, err := stmt.Exec(v.Id, v.Label, strings.Join(v.Keywords, ","), v.FirstDatetime.UTC(), v.LastDatetime.UTC(),
+			strings.Join(v.Newspapers, ","), strings.Join(v.NewsIds, ","))
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error insert stories")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) GetStories(from time.Time, until time.Time) ([]*model.Story, error) {
+	// from is the latest datetime, until is the earliest.
+	var where = []string{"1 = 1"}
+	var args []interface{}
+
+	if !from.IsZero() {
+		where = append(where, "first_datetime <= ?")
+		args = append(args, from.UTC())
+	}
+
+	if !until.IsZero() {
+		where = append(where, "last_datetime >= ?")
+		args = append(args, until.UTC())
+	}
+
+	return s.queryStories(strings.Join(where, " AND "), args...)
+}
+
+func (s *Store) GetStory(id string) (*model.Story, error) {
+	list, err := s.queryStories("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) queryStories(where string, args ...interface{}) ([]*model.Story, error) {
+	rows, err := s.db.Query("SELECT id, label, keywords, first_datetime, last_datetime, newspapers, news_ids FROM stories WHERE "+where+" ORDER BY last_datetime DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.Story, 0)
+	for rows.Next() {
+		var keywords, newspapers, newsIds sql.NullString
+		v := &model.Story{}
+
+		if err := rows.Scan(&v.Id, &v.Label, &keywords, &v.FirstDatetime, &v.LastDatetime, &newspapers, &newsIds); err != nil {
+			return nil, err
+		}
+
+		v.Keywords = strings.Split(keywords.String, ",")
+		v.Newspapers = strings.Split(newspapers.String, ",")
+		v.NewsIds = strings.Split(newsIds.String, ",")
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
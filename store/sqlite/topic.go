@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+// The names of the topics are encoded as the annotations of the news, a JSON object.
+
+func (s *Store) SaveTopic(topic *model.Topic) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO topics(id, names, aliases, created, updated) VALUES (?,?,?,?,?)",
+		topic.Id, encodeAnnotations(topic.Names), strings.Join(topic.Aliases, ","), topic.Created.UTC(), topic.Updated.UTC())
+	if err != nil {
+		return errors.Wrap(err, "error insert topic")
+	}
+	return nil
+}
+
+func (s *Store) GetTopics() ([]*model.Topic, error) {
+	return s.queryTopics("1 = 1")
+}
+
+func (s *Store) GetTopic(id string) (*model.Topic, error) {
+	list, err := s.queryTopics("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) DeleteTopic(id string) error {
+	res, err := s.db.Exec("DELETE FROM topics WHERE id = ?", id)
+	if err != nil {
+		return errors.Wrap(err, "error delete topic")
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) SetTopics(topics map[string][]string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+
+	tx := s.begin()
+
+	stmt, err := tx.Prepare("UPDATE news SET topics = ? WHERE gen_id = ?")
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error prepare update topics")
+	}
+	defer stmt.Close()
+
+	for id, list := range topics {
+		if _, err := stmt.Exec(strings.Join(list, ","), id); err != nil {
+			tx.Rollback()
+			return errors.Wrap(err, "error update topics")
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) queryTopics(where string, args ...interface{}) ([]*model.Topic, error) {
+	rows, err := s.db.Query("SELECT id, names, aliases, created, updated FROM topics WHERE "+where+" ORDER BY id", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query topics")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.Topic, 0)
+	for rows.Next() {
+		var names, aliases string
+		v := &model.Topic{}
+
+		if err := rows.Scan(&v.Id, &names, &aliases, &v.Created, &v.Updated); err != nil {
+			return nil, errors.Wrap(err, "error scan topics")
+		}
+
+		v.Names = decodeAnnotations(names)
+		v.Aliases = splitList(aliases)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
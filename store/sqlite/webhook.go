@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+	"github.com/pkg/errors"
+)
+
+func (s *Store) SaveWebhook(webhook *model.Webhook) error {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO webhooks(id, url, secret, newspapers, tags, keywords, created) VALUES (?,?,?,?,?,?,?)",
+		webhook.Id, webhook.Url, webhook.Secret, strings.Join(webhook.Newspapers, ","), strings.Join(webhook.Tags, ","),
+		strings.Join(webhook.Keywords, ","), webhook.Created.UTC())
+	if err != nil {
+		return errors.Wrap(err, "error insert webhook")
+	}
+	return nil
+}
+
+func (s *Store) GetWebhooks() ([]*model.Webhook, error) {
+	return s.queryWebhooks("1 = 1")
+}
+
+func (s *Store) GetWebhook(id string) (*model.Webhook, error) {
+	list, err := s.queryWebhooks("id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	return list[0], nil
+}
+
+func (s *Store) DeleteWebhook(id string) error {
+	tx := s.begin()
+
+	res, err := tx.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error delete webhook")
+	}
+
+	if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+		tx.Rollback()
+		return store.ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM deliveries WHERE webhook_id = ?", id); err != nil {
+		tx.Rollback()
+		return errors.Wrap(err, "error delete deliveries")
+	}
+
+	return tx.Commit()
+}
+
+func (s *Store) queryWebhooks(where string, args ...interface{}) ([]*model.Webhook, error) {
+	rows, err := s.db.Query("SELECT id, url, secret, newspapers, tags, keywords, created FROM webhooks WHERE "+where+" ORDER BY created", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query webhooks")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.Webhook, 0)
+	for rows.Next() {
+		var newspapers, tags, keywords string
+		v := &model.Webhook{}
+
+		if err := rows.Scan(&v.Id, &v.Url, &v.Secret, &newspapers, &tags, &keywords, &v.Created); err != nil {
+			return nil, errors.Wrap(err, "error scan webhooks")
+		}
+
+		v.Newspapers = splitList(newspapers)
+		v.Tags = splitList(tags)
+		v.Keywords = splitList(keywords)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
+
+func (s *Store) SaveDelivery(delivery *model.Delivery) error {
+	_, err := s.db.Exec("INSERT INTO deliveries(id, webhook_id, news_ids, attempt, datetime, status_code, error, duration, retry) VALUES (?,?,?,?,?,?,?,?,?)",
+		delivery.Id, delivery.WebhookId, strings.Join(delivery.NewsIds, ","), delivery.Attempt, delivery.Datetime.UTC(),
+		delivery.StatusCode, delivery.Error, delivery.Duration, nullTime(delivery.Retry))
+	if err != nil {
+		return errors.Wrap(err, "error insert delivery")
+	}
+	return nil
+}
+
+func (s *Store) GetDeliveries(webhookId string, limit int) ([]*model.Delivery, error) {
+	return s.queryDeliveries("webhook_id = ? ORDER BY datetime DESC, rowid DESC LIMIT ?", webhookId, limit)
+}
+
+func (s *Store) GetRetries(until time.Time) ([]*model.Delivery, error) {
+	return s.queryDeliveries("retry IS NOT NULL AND retry <= ? ORDER BY retry, rowid", until.UTC())
+}
+
+func (s *Store) SetRetried(delivery *model.Delivery) error {
+	_, err := s.db.Exec("UPDATE deliveries SET retry = NULL WHERE id = ? AND attempt = ?", delivery.Id, delivery.Attempt)
+	if err != nil {
+		return errors.Wrap(err, "error update delivery")
+	}
+	return nil
+}
+
+func (s *Store) queryDeliveries(where string, args ...interface{}) ([]*model.Delivery, error) {
+	rows, err := s.db.Query("SELECT id, webhook_id, news_ids, attempt, datetime, status_code, error, duration, retry FROM deliveries WHERE "+where, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error query deliveries")
+	}
+
+	defer rows.Close()
+
+	var list = make([]*model.Delivery, 0)
+	for rows.Next() {
+		var newsIds string
+		v := &model.Delivery{}
+
+		if err := rows.Scan(&v.Id, &v.WebhookId, &newsIds, &v.Attempt, &v.Datetime, &v.StatusCode, &v.Error, &v.Duration, &v.Retry); err != nil {
+			return nil, errors.Wrap(err, "error scan deliveries")
+		}
+
+		v.NewsIds = splitList(newsIds)
+		list = append(list, v)
+	}
+
+	return list, rows.Err()
+}
+
+// splitList splits a comma separated list, the empty list is nil.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestRetries(t *testing.T) {
+	// The store is created in the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	s, err := NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2018, 6, 11, 8, 0, 0, 0, time.UTC)
+	due, later := now.Add(-time.Minute), now.Add(time.Minute)
+	for _, v := range []*model.Delivery{
+		{Id: "a", WebhookId: "w", NewsIds: []string{"1"}, Attempt: 1, Datetime: now, Error: "failed", Retry: &due},
+		{Id: "a", WebhookId: "w", NewsIds: []string{"1"}, Attempt: 2, Datetime: now, Error: "failed", Retry: &later},
+		{Id: "b", WebhookId: "w", NewsIds: []string{"2"}, Attempt: 1, Datetime: now},
+	} {
+		if err := s.SaveDelivery(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	retries, err := s.GetRetries(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retries) != 1 || retries[0].Id != "a" || retries[0].Attempt != 1 || !retries[0].Retry.Equal(due) {
+		t.Fatalf("got %+v", retries)
+	}
+
+	if err := s.SetRetried(retries[0]); err != nil {
+		t.Fatal(err)
+	}
+	if retries, err := s.GetRetries(later); err != nil || len(retries) != 1 || retries[0].Attempt != 2 {
+		t.Errorf("got %+v, %v", retries, err)
+	}
+
+	// The deliveries without a retry have none.
+	deliveries, err := s.GetDeliveries("w", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 3 || deliveries[0].Retry != nil {
+		t.Errorf("got %+v", deliveries)
+	}
+}
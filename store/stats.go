@@ -0,0 +1,258 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// The groups of the news counted, see NewsStore.CountBy.
+const (
+	GroupNewspaper   = "newspaper"
+	GroupCategory    = "category"
+	GroupSubcategory = "subcategory"
+	GroupTag         = "tag"
+	GroupAuthor      = "author"
+	GroupLocation    = "location"
+)
+
+// The intervals of the histograms, see NewsStore.Histogram.
+const (
+	Hour = "hour"
+	Day  = "day"
+	Week = "week"
+)
+
+// Count is the number of news of a value of a group, e.g of a newspaper.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Bucket is the number of news of an interval, and of a value of a group if the news are grouped.
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Key   string    `json:"key,omitempty"`
+	Count int       `json:"count"`
+}
+
+// AuthorCount is the number of news of an author of a newspaper.
+type AuthorCount struct {
+	Author    string `json:"author"`
+	Newspaper string `json:"newspaper"`
+	Count     int    `json:"count"`
+}
+
+// ValidGroup returns true if the group is known.
+func ValidGroup(group string) bool {
+	switch group {
+	case GroupNewspaper, GroupCategory, GroupSubcategory, GroupTag, GroupAuthor, GroupLocation:
+		return true
+	}
+	return false
+}
+
+// ValidInterval returns true if the interval is known.
+func ValidInterval(interval string) bool {
+	switch interval {
+	case Hour, Day, Week:
+		return true
+	}
+	return false
+}
+
+// GroupFields returns the fields of the news read by GroupValues.
+func GroupFields(group string) []string {
+	switch group {
+	case GroupNewspaper:
+		return []string{"source.id"}
+	case GroupCategory:
+		return []string{"source.category"}
+	case GroupSubcategory:
+		return []string{"source.subcategory"}
+	case GroupTag:
+		return []string{"tags", "source.tags"}
+	case GroupAuthor:
+		return []string{"author"}
+	case GroupLocation:
+		return []string{"location"}
+	}
+	return nil
+}
+
+// GroupValues returns the values of the group of the news, without the empty values.
+// The tags are the tags of the news and of its source, in lower case.
+func GroupValues(n *model.News, group string) []string {
+	var values []string
+	switch group {
+	case GroupNewspaper:
+		values = []string{n.Source.NewspaperId}
+	case GroupCategory:
+		values = []string{n.Source.OriginalCategory}
+	case GroupSubcategory:
+		values = []string{n.Source.OriginalSubcategory}
+	case GroupAuthor:
+		values = []string{n.Author}
+	case GroupLocation:
+		values = []string{n.Location}
+	case GroupTag:
+		var seen = make(map[string]bool)
+		for _, t := range append(append([]string(nil), n.Tags...), n.Source.Tags...) {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if !seen[t] {
+				seen[t] = true
+				values = append(values, t)
+			}
+		}
+	}
+
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// HasTag returns true if the news or its source has the tag, in any case.
+func HasTag(n *model.News, tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range GroupValues(n, GroupTag) {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Counter counts the news by the values of a group, for the stores that can not count in their queries.
+type Counter struct {
+	group  string
+	counts map[string]int
+}
+
+func NewCounter(group string) *Counter {
+	return &Counter{group: group, counts: make(map[string]int)}
+}
+
+func (c *Counter) Add(n *model.News) {
+	for _, v := range GroupValues(n, c.group) {
+		c.counts[v]++
+	}
+}
+
+// Counts returns the counts, most first then by key, at most limit if it is positive.
+func (c *Counter) Counts(limit int) []*Count {
+	var list = make([]*Count, 0, len(c.counts))
+	for k, v := range c.counts {
+		list = append(list, &Count{Key: k, Count: v})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// BucketStart returns the start of the interval of the datetime in the location, the weeks start on Monday.
+func BucketStart(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+
+	switch interval {
+	case Hour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case Week:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// Histogram counts the news per interval, and by the values of a group if it is not empty.
+type Histogram struct {
+	interval string
+	group    string
+	loc      *time.Location
+	counts   map[Bucket]int
+}
+
+func NewHistogram(interval string, group string, loc *time.Location) *Histogram {
+	return &Histogram{interval: interval, group: group, loc: loc, counts: make(map[Bucket]int)}
+}
+
+func (h *Histogram) Add(n *model.News) {
+	start := BucketStart(n.Datetime, h.interval, h.loc)
+
+	if h.group == "" {
+		h.counts[Bucket{Start: start}]++
+		return
+	}
+
+	for _, v := range GroupValues(n, h.group) {
+		h.counts[Bucket{Start: start, Key: v}]++
+	}
+}
+
+// Buckets returns the buckets oldest first then by key, the intervals without news are left out.
+func (h *Histogram) Buckets() []*Bucket {
+	var list = make([]*Bucket, 0, len(h.counts))
+	for b, count := range h.counts {
+		b := b
+		b.Count = count
+		list = append(list, &b)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Start.Equal(list[j].Start) {
+			return list[i].Start.Before(list[j].Start)
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// TopAuthors returns the authors with the most news, most first, at most limit if it is positive.
+func TopAuthors(list []*model.News, limit int) []*AuthorCount {
+	var counts = make(map[AuthorCount]int)
+	for _, n := range list {
+		if author := strings.TrimSpace(n.Author); author != "" {
+			counts[AuthorCount{Author: author, Newspaper: n.Source.NewspaperId}]++
+		}
+	}
+
+	var result = make([]*AuthorCount, 0, len(counts))
+	for a, count := range counts {
+		a := a
+		a.Count = count
+		result = append(result, &a)
+	}
+
+	sortAuthors(result)
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// sortAuthors sorts the authors most first, then by name and newspaper.
+func sortAuthors(list []*AuthorCount) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		if list[i].Author != list[j].Author {
+			return list[i].Author < list[j].Author
+		}
+		return list[i].Newspaper < list[j].Newspaper
+	})
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestBucketStart(t *testing.T) {
+	loc := time.FixedZone("MYT", 8*3600)
+
+	// A Sunday at 23:30 in UTC is Monday at 07:30 in MYT.
+	datetime := time.Date(2018, 6, 10, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		interval string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{Hour, loc, time.Date(2018, 6, 11, 7, 0, 0, 0, loc)},
+		{Day, loc, time.Date(2018, 6, 11, 0, 0, 0, 0, loc)},
+		{Week, loc, time.Date(2018, 6, 11, 0, 0, 0, 0, loc)},
+		{Day, time.UTC, time.Date(2018, 6, 10, 0, 0, 0, 0, time.UTC)},
+		{Week, time.UTC, time.Date(2018, 6, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if got := BucketStart(datetime, test.interval, test.loc); !got.Equal(test.expected) {
+			t.Errorf("%s in %s: got %s, expected %s", test.interval, test.loc, got, test.expected)
+		}
+	}
+}
+
+func statsNews() []*model.News {
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	return []*model.News{
+		{Id: "1", Author: "Ali", Datetime: day.Add(time.Hour), Tags: []string{"Politik", "PRU14"}, Source: model.BhSources[1]},
+		{Id: "2", Author: "Ali", Datetime: day.Add(2 * time.Hour), Source: model.BhSources[0]},
+		{Id: "3", Author: "Ali", Datetime: day.Add(25 * time.Hour), Tags: []string{"politics"}, Source: model.NstSources[1]},
+		{Id: "4", Author: " ", Datetime: day.Add(26 * time.Hour), Tags: []string{" "}, Source: model.NstSources[1]},
+	}
+}
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter(GroupTag)
+	for _, n := range statsNews() {
+		counter.Add(n)
+	}
+
+	// The tags of the news and of the source are counted once per news, in lower case.
+	expected := []*Count{{"news", 4}, {"politics", 3}, {"nation", 1}, {"politik", 1}}
+	if got := counter.Counts(4); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogram(Day, GroupNewspaper, time.UTC)
+	for _, n := range statsNews() {
+		histogram.Add(n)
+	}
+
+	day := time.Date(2018, 6, 11, 0, 0, 0, 0, time.UTC)
+	expected := []*Bucket{
+		{Start: day, Key: model.BharianId, Count: 2},
+		{Start: day.AddDate(0, 0, 1), Key: model.NstId, Count: 2},
+	}
+	if got := histogram.Buckets(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestTopAuthors(t *testing.T) {
+	expected := []*AuthorCount{{"Ali", model.BharianId, 2}, {"Ali", model.NstId, 1}}
+	if got := TopAuthors(statsNews(), 0); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+}
+
+func TestFilterTag(t *testing.T) {
+	var ids []string
+	for _, n := range statsNews() {
+		if (Filter{Newspaper: model.NstId, Tag: "POLITICS"}).Match(n) {
+			ids = append(ids, n.Id)
+		}
+	}
+
+	if !reflect.DeepEqual(ids, []string{"3", "4"}) {
+		t.Errorf("got %v", ids)
+	}
+}
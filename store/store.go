@@ -7,8 +7,77 @@ import (
 )
 
 type NewsStore interface {
-	Insert(news []*model.News) error
+	// Insert returns the news that did not exist, the existing news are left as they are.
+	Insert(news []*model.News) ([]*model.News, error)
+	// Update replaces the scraped fields of news that already exist, matched by id.
+	Update(news []*model.News) error
+	Get(id string) (*model.News, error)
 	GetByKeywords(keywords []string) ([]*model.News, error)
-	GetAll(from time.Time, end time.Time) ([]*model.News, error)
+	GetAll(filter Filter) ([]*model.News, error)
 	GetByProvider(providerId string) ([]*model.News, error)
+	// GetByCluster returns the near-duplicates of the cluster, latest first.
+	GetByCluster(clusterId string) ([]*model.News, error)
+	// MergeNews moves the news to the id and the url of the merges, and deletes their duplicates along with their pictures
+	// and entities. The cluster ids of the news moved or deleted are replaced by the id, see Merges.
+	MergeNews(merges []*Merge) error
+
+	// CountBy returns the number of news of the filter by the values of the group, e.g GroupNewspaper, see Counter.Counts.
+	// A news is counted once for each of its tags.
+	CountBy(filter Filter, group string, limit int) ([]*Count, error)
+	// Histogram returns the number of news of the filter per interval in the location, by the values of the group
+	// if it is not empty, see Histogram.Buckets.
+	Histogram(filter Filter, interval string, group string, loc *time.Location) ([]*Bucket, error)
+	// TopAuthors returns the authors with the most news of the filter, most first, at most limit if it is positive.
+	TopAuthors(filter Filter, limit int) ([]*AuthorCount, error)
+
+	// SaveStories replaces the stories lasting until after since.
+	SaveStories(since time.Time, stories []*model.Story) error
+	// GetStories returns the stories running between until and from, latest first. The news are not filled.
+	GetStories(from time.Time, until time.Time) ([]*model.Story, error)
+	GetStory(id string) (*model.Story, error)
+
+	// SaveWebhook inserts the webhook, or replaces it if its id exists.
+	SaveWebhook(webhook *model.Webhook) error
+	GetWebhooks() ([]*model.Webhook, error)
+	GetWebhook(id string) (*model.Webhook, error)
+	// DeleteWebhook deletes the webhook along with its deliveries.
+	DeleteWebhook(id string) error
+	SaveDelivery(delivery *model.Delivery) error
+	// GetDeliveries returns the latest deliveries of the webhook, latest first.
+	GetDeliveries(webhookId string, limit int) ([]*model.Delivery, error)
+	// GetRetries returns the deliveries to attempt again by the time, oldest first.
+	GetRetries(until time.Time) ([]*model.Delivery, error)
+	// SetRetried clears the retry of the attempt of the delivery, see model.Delivery.Retry.
+	SetRetried(delivery *model.Delivery) error
+
+	// SaveSearch inserts the saved search, or replaces it if its id exists.
+	SaveSearch(search *model.SavedSearch) error
+	// GetSearches returns the saved searches of the owner, every saved search if the owner is empty.
+	GetSearches(owner string) ([]*model.SavedSearch, error)
+	GetSearch(id string) (*model.SavedSearch, error)
+	// DeleteSearch deletes the saved search along with its alerts.
+	DeleteSearch(id string) error
+	// SaveAlerts inserts the alerts, the alerts whose id exists are left as they are.
+	SaveAlerts(alerts []*model.Alert) error
+	// GetAlerts returns the latest alerts of the saved search, latest first.
+	GetAlerts(searchId string, limit int) ([]*model.Alert, error)
+	// GetPendingAlerts returns the alerts not delivered yet, oldest first.
+	GetPendingAlerts() ([]*model.Alert, error)
+	SetDelivered(ids []string, delivered time.Time) error
+
+	// SaveTopic inserts the topic of the taxonomy, or replaces it if its id exists.
+	SaveTopic(topic *model.Topic) error
+	// GetTopics returns the topics of the taxonomy, by id.
+	GetTopics() ([]*model.Topic, error)
+	GetTopic(id string) (*model.Topic, error)
+	DeleteTopic(id string) error
+	// SetTopics replaces the topics of the news by their id, the news that do not exist are skipped.
+	SetTopics(topics map[string][]string) error
+
+	// SaveApiKey inserts the API key, or replaces it if its id exists. The usage counters are left as they are.
+	SaveApiKey(key *model.ApiKey) error
+	GetApiKeys() ([]*model.ApiKey, error)
+	GetApiKey(id string) (*model.ApiKey, error)
+	// AddApiKeyUsage adds the requests to the usage counters of the API key.
+	AddApiKeyUsage(id string, requests int64, lastUsed time.Time) error
 }
@@ -0,0 +1,557 @@
+// Package storetest provides an in-memory store.NewsStore for the tests of the packages using the store.
+package storetest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// Memory keeps everything in slices, the tests fill them directly. It is safe for concurrent use,
+// the slices should only be read by the test once the code under test is done.
+type Memory struct {
+	News       []*model.News
+	Stories    []*model.Story
+	Webhooks   []*model.Webhook
+	Deliveries []*model.Delivery
+	Searches   []*model.SavedSearch
+	Alerts     []*model.Alert
+	Keys       []*model.ApiKey
+	Topics     []*model.Topic
+
+	// Filter is the filter of the last GetAll.
+	Filter store.Filter
+
+	mu sync.Mutex
+}
+
+var _ store.NewsStore = &Memory{}
+
+func (s *Memory) Insert(news []*model.News) ([]*model.News, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var inserted []*model.News
+	for _, n := range news {
+		if s.indexNews(n.Id) >= 0 {
+			continue
+		}
+		s.News = append(s.News, n)
+		inserted = append(inserted, n)
+	}
+	return inserted, nil
+}
+
+func (s *Memory) Update(news []*model.News) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, n := range news {
+		if i := s.indexNews(n.Id); i >= 0 {
+			s.News[i] = n
+		}
+	}
+	return nil
+}
+
+func (s *Memory) Get(id string) (*model.News, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := s.indexNews(id); i >= 0 {
+		return s.News[i], nil
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) indexNews(id string) int {
+	for i, v := range s.News {
+		if v.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetByKeywords returns the news whose title contains one of the keywords, ignoring the case.
+func (s *Memory) GetByKeywords(keywords []string) ([]*model.News, error) {
+	return s.match(func(n *model.News) bool {
+		for _, k := range keywords {
+			if strings.Contains(strings.ToLower(n.Title), strings.ToLower(k)) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (s *Memory) GetAll(filter store.Filter) ([]*model.News, error) {
+	s.mu.Lock()
+	s.Filter = filter
+	s.mu.Unlock()
+
+	return s.match(filter.Match), nil
+}
+
+func (s *Memory) GetByProvider(providerId string) ([]*model.News, error) {
+	return s.match(func(n *model.News) bool { return n.Source.NewspaperId == providerId }), nil
+}
+
+func (s *Memory) GetByCluster(clusterId string) ([]*model.News, error) {
+	list := s.match(func(n *model.News) bool { return n.ClusterId == clusterId })
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Datetime.After(list[j].Datetime) })
+	return list, nil
+}
+
+// match returns the news matched, in the order they were added.
+func (s *Memory) match(match func(n *model.News) bool) []*model.News {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []*model.News
+	for _, v := range s.News {
+		if match(v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func (s *Memory) MergeNews(merges []*store.Merge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	moved := store.MovedIds(merges)
+	var kept []*model.News
+	for _, n := range s.News {
+		for _, m := range merges {
+			if n.Id == m.From || (m.From == "" && n.Id == m.Id) {
+				n.Id, n.Url = m.Id, m.Url
+			}
+		}
+		if _, exist := moved[n.Id]; exist {
+			continue
+		}
+		if id, exist := moved[n.ClusterId]; exist {
+			n.ClusterId = id
+		}
+		kept = append(kept, n)
+	}
+	s.News = kept
+
+	for _, v := range s.Stories {
+		v.NewsIds, _ = store.RewriteIds(v.NewsIds, moved)
+	}
+	for _, v := range s.Deliveries {
+		v.NewsIds, _ = store.RewriteIds(v.NewsIds, moved)
+	}
+	for _, v := range s.Alerts {
+		if id, exist := moved[v.NewsId]; exist {
+			v.NewsId = id
+		}
+	}
+	return nil
+}
+
+func (s *Memory) CountBy(filter store.Filter, group string, limit int) ([]*store.Count, error) {
+	counter := store.NewCounter(group)
+	for _, v := range s.match(filter.Match) {
+		counter.Add(v)
+	}
+	return counter.Counts(limit), nil
+}
+
+func (s *Memory) Histogram(filter store.Filter, interval string, group string, loc *time.Location) ([]*store.Bucket, error) {
+	histogram := store.NewHistogram(interval, group, loc)
+	for _, v := range s.match(filter.Match) {
+		histogram.Add(v)
+	}
+	return histogram.Buckets(), nil
+}
+
+func (s *Memory) TopAuthors(filter store.Filter, limit int) ([]*store.AuthorCount, error) {
+	return store.TopAuthors(s.match(filter.Match), limit), nil
+}
+
+func (s *Memory) SaveStories(since time.Time, stories []*model.Story) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []*model.Story
+	for _, v := range s.Stories {
+		if v.LastDatetime.Before(since) {
+			kept = append(kept, v)
+		}
+	}
+	s.Stories = append(kept, stories...)
+	return nil
+}
+
+func (s *Memory) GetStories(from time.Time, until time.Time) ([]*model.Story, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// from is the latest datetime, until is the earliest.
+	var list = make([]*model.Story, 0)
+	for _, v := range s.Stories {
+		if (!from.IsZero() && v.FirstDatetime.After(from)) || (!until.IsZero() && v.LastDatetime.Before(until)) {
+			continue
+		}
+		list = append(list, v)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].LastDatetime.After(list[j].LastDatetime) })
+	return list, nil
+}
+
+// GetStory returns a copy of the story, the caller may fill its news.
+func (s *Memory) GetStory(id string) (*model.Story, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Stories {
+		if v.Id == id {
+			copy := *v
+			return &copy, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) SaveWebhook(webhook *model.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Webhooks {
+		if v.Id == webhook.Id {
+			s.Webhooks[i] = webhook
+			return nil
+		}
+	}
+	s.Webhooks = append(s.Webhooks, webhook)
+	return nil
+}
+
+func (s *Memory) GetWebhooks() ([]*model.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*model.Webhook{}, s.Webhooks...), nil
+}
+
+func (s *Memory) GetWebhook(id string) (*model.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Webhooks {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) DeleteWebhook(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Webhooks {
+		if v.Id == id {
+			s.Webhooks = append(s.Webhooks[:i], s.Webhooks[i+1:]...)
+
+			var deliveries []*model.Delivery
+			for _, d := range s.Deliveries {
+				if d.WebhookId != id {
+					deliveries = append(deliveries, d)
+				}
+			}
+			s.Deliveries = deliveries
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (s *Memory) SaveDelivery(delivery *model.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A copy, the retry is cleared by SetRetried.
+	v := *delivery
+	s.Deliveries = append(s.Deliveries, &v)
+	return nil
+}
+
+func (s *Memory) GetDeliveries(webhookId string, limit int) ([]*model.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list = make([]*model.Delivery, 0)
+	for i := len(s.Deliveries) - 1; i >= 0 && len(list) < limit; i-- {
+		if s.Deliveries[i].WebhookId == webhookId {
+			list = append(list, s.Deliveries[i])
+		}
+	}
+	return list, nil
+}
+
+func (s *Memory) GetRetries(until time.Time) ([]*model.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []*model.Delivery
+	for _, v := range s.Deliveries {
+		if v.Retry != nil && !v.Retry.After(until) {
+			list = append(list, v)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Retry.Before(*list[j].Retry)
+	})
+	return list, nil
+}
+
+func (s *Memory) SetRetried(delivery *model.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Deliveries {
+		if v.Id == delivery.Id && v.Attempt == delivery.Attempt {
+			v.Retry = nil
+		}
+	}
+	return nil
+}
+
+func (s *Memory) SaveSearch(search *model.SavedSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Searches {
+		if v.Id == search.Id {
+			s.Searches[i] = search
+			return nil
+		}
+	}
+	s.Searches = append(s.Searches, search)
+	return nil
+}
+
+func (s *Memory) GetSearches(owner string) ([]*model.SavedSearch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list = make([]*model.SavedSearch, 0)
+	for _, v := range s.Searches {
+		if owner == "" || v.Owner == owner {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+func (s *Memory) GetSearch(id string) (*model.SavedSearch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Searches {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) DeleteSearch(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Searches {
+		if v.Id == id {
+			s.Searches = append(s.Searches[:i], s.Searches[i+1:]...)
+
+			var alerts []*model.Alert
+			for _, a := range s.Alerts {
+				if a.SearchId != id {
+					alerts = append(alerts, a)
+				}
+			}
+			s.Alerts = alerts
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (s *Memory) SaveAlerts(alerts []*model.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, alert := range alerts {
+		exist := false
+		for _, v := range s.Alerts {
+			exist = exist || v.Id == alert.Id
+		}
+		if !exist {
+			s.Alerts = append(s.Alerts, alert)
+		}
+	}
+	return nil
+}
+
+func (s *Memory) GetAlerts(searchId string, limit int) ([]*model.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list = make([]*model.Alert, 0)
+	for i := len(s.Alerts) - 1; i >= 0 && len(list) < limit; i-- {
+		if s.Alerts[i].SearchId == searchId {
+			list = append(list, s.Alerts[i])
+		}
+	}
+	return list, nil
+}
+
+func (s *Memory) GetPendingAlerts() ([]*model.Alert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list []*model.Alert
+	for _, v := range s.Alerts {
+		if v.Delivered == nil {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+func (s *Memory) SetDelivered(ids []string, delivered time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Alerts {
+		for _, id := range ids {
+			if v.Id == id {
+				v.Delivered = &delivered
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Memory) SaveTopic(topic *model.Topic) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Topics {
+		if v.Id == topic.Id {
+			s.Topics[i] = topic
+			return nil
+		}
+	}
+	s.Topics = append(s.Topics, topic)
+	return nil
+}
+
+func (s *Memory) GetTopics() ([]*model.Topic, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*model.Topic{}, s.Topics...), nil
+}
+
+func (s *Memory) GetTopic(id string) (*model.Topic, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Topics {
+		if v.Id == id {
+			return v, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) DeleteTopic(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, v := range s.Topics {
+		if v.Id == id {
+			s.Topics = append(s.Topics[:i], s.Topics[i+1:]...)
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
+
+func (s *Memory) SetTopics(topics map[string][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.News {
+		if list, exist := topics[v.Id]; exist {
+			v.Topics = list
+		}
+	}
+	return nil
+}
+
+// SaveApiKey stores a copy of the API key, as a database would.
+func (s *Memory) SaveApiKey(key *model.ApiKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copy := *key
+	for i, v := range s.Keys {
+		if v.Id == key.Id {
+			copy.Requests, copy.LastUsed = v.Requests, v.LastUsed
+			s.Keys[i] = &copy
+			return nil
+		}
+	}
+	s.Keys = append(s.Keys, &copy)
+	return nil
+}
+
+func (s *Memory) GetApiKeys() ([]*model.ApiKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var list = make([]*model.ApiKey, 0, len(s.Keys))
+	for _, v := range s.Keys {
+		copy := *v
+		list = append(list, &copy)
+	}
+	return list, nil
+}
+
+func (s *Memory) GetApiKey(id string) (*model.ApiKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Keys {
+		if v.Id == id {
+			copy := *v
+			return &copy, nil
+		}
+	}
+	return nil, store.ErrNotFound
+}
+
+func (s *Memory) AddApiKeyUsage(id string, requests int64, lastUsed time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.Keys {
+		if v.Id == id {
+			v.Requests += requests
+			v.LastUsed = &lastUsed
+			return nil
+		}
+	}
+	return store.ErrNotFound
+}
@@ -5,37 +5,60 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ahmadmuzakkir/scrapenews/archive"
 	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/processor"
 	"github.com/ahmadmuzakkir/scrapenews/provider"
 	"github.com/pkg/errors"
 )
 
 var ErrProviderNotFound = errors.New("Provider not found !")
+var ErrArchiveDisabled = errors.New("The archive is disabled !")
+
+// Listener is notified of the news inserted by the Refresher, e.g to deliver the webhooks.
+// It is called from the refresh, a slow listener should do its work in the background.
+type Listener interface {
+	Inserted(news []*model.News)
+}
 
 type Refresher struct {
 	providers  map[string]provider.Provider
 	store      NewsStore
+	archive    *archive.Archive
+	pipelines  map[string]*processor.Pipeline
+	listeners  []Listener
 	lastupdate time.Time
 }
 
-func NewRefresher(httpClient *http.Client, store NewsStore) *Refresher {
+// NewRefresher creates the refresher.
+// The archive is optional, pass nil to disable archiving the raw pages.
+// The pipelines are keyed by the newspaper id, the news of a newspaper without a pipeline are stored as scraped.
+func NewRefresher(httpClient *http.Client, store NewsStore, archive *archive.Archive, pipelines map[string]*processor.Pipeline) *Refresher {
 	refresh := &Refresher{}
 	refresh.providers = make(map[string]provider.Provider)
-	refresh.providers[model.NstId] = provider.NewNst(httpClient)
-	refresh.providers[model.BharianId] = provider.NewBharian(httpClient)
-	refresh.providers[model.UtusanId] = provider.NewUtusan(httpClient)
+	refresh.providers[model.NstId] = provider.NewNst(httpClient, archive)
+	refresh.providers[model.BharianId] = provider.NewBharian(httpClient, archive)
+	refresh.providers[model.UtusanId] = provider.NewUtusan(httpClient, archive)
 
 	refresh.lastupdate = time.Now().AddDate(0, 0, -1)
 	refresh.store = store
+	refresh.archive = archive
+	refresh.pipelines = pipelines
 	return refresh
 }
 
+// AddListener adds a listener of the inserted news, it should be added before the first refresh.
+func (r *Refresher) AddListener(l Listener) {
+	r.listeners = append(r.listeners, l)
+}
+
 func (r *Refresher) Refresh() {
 	var workersCount = 10
 
 	type result struct {
-		news []*model.News
-		err  error
+		source model.NewsSource
+		news   []*model.News
+		err    error
 	}
 
 	jobs := make(chan model.NewsSource)
@@ -53,7 +76,7 @@ func (r *Refresher) Refresh() {
 				}
 
 				news, err := p.Scrape(j, 10, r.lastupdate)
-				results <- result{news: news, err: err}
+				results <- result{source: j, news: news, err: err}
 			}
 		}()
 	}
@@ -72,6 +95,11 @@ func (r *Refresher) Refresh() {
 		close(jobs)
 	}()
 
+	clusters, err := newClusterIndex(r.store)
+	if err != nil {
+		log.Println(err)
+	}
+
 	// Get the results
 	for i := 0; i < len(sources); i++ {
 		res := <-results
@@ -79,9 +107,111 @@ func (r *Refresher) Refresh() {
 			log.Println(res.err)
 			continue
 		}
-		err := r.store.Insert(res.news)
+
+		res.news = r.pipelines[res.source.NewspaperId].Run(res.news)
+
+		if clusters != nil {
+			for _, n := range res.news {
+				clusters.assign(n)
+			}
+		}
+
+		inserted, err := r.store.Insert(res.news)
 		if err != nil {
 			log.Println(err)
+			continue
+		}
+
+		if len(inserted) > 0 {
+			for _, l := range r.listeners {
+				l.Inserted(inserted)
+			}
+		}
+	}
+
+	for id, p := range r.pipelines {
+		log.Printf("pipeline %s: %s", id, p)
+	}
+}
+
+// Reparse re-runs the current extractors over every archived page and updates the stored news.
+// It returns the number of updated news.
+func (r *Refresher) Reparse() (int, error) {
+	if r.archive == nil {
+		return 0, ErrArchiveDisabled
+	}
+
+	ids, err := r.archive.Ids()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	var batch []*model.News
+	for _, id := range ids {
+		n, err := r.store.Get(id)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+
+		p := r.providers[n.Source.NewspaperId]
+		if p == nil {
+			log.Println(id, ErrProviderNotFound)
+			continue
+		}
+
+		body, archived, err := r.archive.Get(id)
+		if err != nil {
+			return count, err
+		}
+
+		// The parse replaces the pictures, the mirrored ones keep their hash.
+		stored := n.Pictures
+		// The relative dates are relative to the time the page was archived, not to the reparse.
+		if err := p.Parse(n.Url, body, archived, n); err != nil {
+			log.Println(id, err)
+			continue
+		}
+		keepMirrored(n.Pictures, stored)
+
+		// A dropped news is left as it is stored.
+		if len(r.pipelines[n.Source.NewspaperId].Run([]*model.News{n})) == 0 {
+			continue
+		}
+
+		batch = append(batch, n)
+		if len(batch) == 100 {
+			if err := r.store.Update(batch); err != nil {
+				return count, err
+			}
+			count += len(batch)
+			batch = nil
+		}
+	}
+
+	if err := r.store.Update(batch); err != nil {
+		return count, err
+	}
+	count += len(batch)
+
+	return count, nil
+}
+
+// keepMirrored copies the hash, the dimensions and the MIME type of the stored pictures onto the pictures of the same url.
+func keepMirrored(pictures []*model.Picture, stored []*model.Picture) {
+	mirrored := make(map[string]*model.Picture)
+	for _, p := range stored {
+		if p.Hash != "" {
+			mirrored[p.ImageUrl] = p
+		}
+	}
+
+	for _, p := range pictures {
+		if m, exist := mirrored[p.ImageUrl]; exist && p.Hash == "" {
+			p.Hash, p.Width, p.Height, p.MimeType = m.Hash, m.Width, m.Height, m.MimeType
 		}
 	}
 }
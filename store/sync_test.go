@@ -0,0 +1,31 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestKeepMirrored(t *testing.T) {
+	stored := []*model.Picture{
+		{ImageUrl: "https://example.com/a.jpg", Hash: "a", Width: 640, Height: 480, MimeType: "image/jpeg"},
+		{ImageUrl: "https://example.com/b.jpg"},
+	}
+	pictures := []*model.Picture{
+		{ImageUrl: "https://example.com/c.jpg", Caption: "Baru"},
+		{ImageUrl: "https://example.com/a.jpg", Caption: "Kilang"},
+		{ImageUrl: "https://example.com/b.jpg"},
+	}
+
+	keepMirrored(pictures, stored)
+
+	expected := []*model.Picture{
+		{ImageUrl: "https://example.com/c.jpg", Caption: "Baru"},
+		{ImageUrl: "https://example.com/a.jpg", Caption: "Kilang", Hash: "a", Width: 640, Height: 480, MimeType: "image/jpeg"},
+		{ImageUrl: "https://example.com/b.jpg"},
+	}
+	if !reflect.DeepEqual(pictures, expected) {
+		t.Errorf("got %v, expected %v", pictures, expected)
+	}
+}
@@ -0,0 +1,177 @@
+package story
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// The smallest cosine similarity between a news and the centre of a story to join it.
+const minSimilarity = 0.25
+
+// A story ends when it has no news for this long.
+const maxGap = 48 * time.Hour
+
+// The number of keywords of a story.
+const keywordsCount = 5
+
+type vector map[string]float64
+
+func (v vector) norm() float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+func cosine(a, b vector) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	var dot float64
+	for t, w := range a {
+		dot += w * b[t]
+	}
+
+	na, nb := a.norm(), b.norm()
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	return dot / (na * nb)
+}
+
+type cluster struct {
+	news     []*model.News
+	vectors  []vector
+	centroid vector
+	last     time.Time
+}
+
+func (c *cluster) add(n *model.News, v vector) {
+	c.news = append(c.news, n)
+	c.vectors = append(c.vectors, v)
+	for t, w := range v {
+		c.centroid[t] += w
+	}
+	if n.Datetime.After(c.last) {
+		c.last = n.Datetime
+	}
+}
+
+// label returns the title of the news closest to the centroid.
+func (c *cluster) label() string {
+	var best float64 = -1
+	var label string
+	for i, v := range c.vectors {
+		if s := cosine(v, c.centroid); s > best {
+			best = s
+			label = c.news[i].Title
+		}
+	}
+	return label
+}
+
+// keywords returns the heaviest terms of the centroid.
+func (c *cluster) keywords() []string {
+	terms := make([]string, 0, len(c.centroid))
+	for t := range c.centroid {
+		terms = append(terms, t)
+	}
+
+	sort.Slice(terms, func(i, j int) bool {
+		if c.centroid[terms[i]] == c.centroid[terms[j]] {
+			return terms[i] < terms[j]
+		}
+		return c.centroid[terms[i]] > c.centroid[terms[j]]
+	})
+
+	if len(terms) > keywordsCount {
+		terms = terms[:keywordsCount]
+	}
+	return terms
+}
+
+// vectors returns the TF-IDF vector of every news. The title counts twice, it names the event.
+func vectors(list []*model.News) []vector {
+	terms := make([][]string, len(list))
+	df := make(map[string]int)
+
+	for i, n := range list {
+		terms[i] = tokenize(n.Title+"\n"+n.Title+"\n"+n.Content, n.Language)
+
+		seen := make(map[string]struct{})
+		for _, t := range terms[i] {
+			if _, exist := seen[t]; !exist {
+				seen[t] = struct{}{}
+				df[t]++
+			}
+		}
+	}
+
+	result := make([]vector, len(list))
+	for i := range list {
+		v := make(vector)
+		for _, t := range terms[i] {
+			v[t]++
+		}
+
+		for t, tf := range v {
+			idf := math.Log(float64(1+len(list)) / float64(1+df[t]))
+			v[t] = (1 + math.Log(tf)) * idf
+		}
+
+		result[i] = v
+	}
+
+	return result
+}
+
+// group clusters the news by the similarity to the centre of each story, in the order they were published.
+// Only the clusters covered by at least two news are returned.
+func group(list []*model.News) []*cluster {
+	sorted := make([]*model.News, len(list))
+	copy(sorted, list)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Datetime.Before(sorted[j].Datetime)
+	})
+
+	vs := vectors(sorted)
+
+	var clusters []*cluster
+	for i, n := range sorted {
+		var best *cluster
+		var bestSimilarity = minSimilarity
+
+		for _, c := range clusters {
+			if n.Datetime.Sub(c.last) > maxGap {
+				continue
+			}
+
+			if s := cosine(vs[i], c.centroid); s >= bestSimilarity {
+				best = c
+				bestSimilarity = s
+			}
+		}
+
+		if best == nil {
+			best = &cluster{centroid: make(vector)}
+			clusters = append(clusters, best)
+		}
+
+		best.add(n, vs[i])
+	}
+
+	var result []*cluster
+	for _, c := range clusters {
+		if len(c.news) > 1 {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package story
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestGroup(t *testing.T) {
+	day := time.Date(2018, 6, 11, 8, 0, 0, 0, time.UTC)
+	news := func(id string, hours int, lang, title, content string) *model.News {
+		return &model.News{Id: id, Datetime: day.Add(time.Duration(hours) * time.Hour), Language: lang, Title: title, Content: content}
+	}
+
+	flood := []*model.News{
+		news("flood-ms", 0, "ms", "Banjir di Kelantan, 5,000 mangsa dipindahkan", "Lebih 5,000 mangsa banjir di Kelantan dipindahkan ke pusat pemindahan sementara."),
+		news("flood-en", 2, "en", "Kelantan flood: 5,000 victims evacuated", "More than 5,000 flood victims in Kelantan were evacuated to relief centres."),
+		news("flood-later", 20, "en", "Kelantan flood victims rise", "The number of flood victims in Kelantan evacuated to relief centres rose overnight."),
+	}
+	court := []*model.News{
+		news("court-ms", 1, "ms", "Bekas menteri didakwa di mahkamah atas tuduhan rasuah", "Bekas menteri itu didakwa di mahkamah atas tuduhan rasuah melibatkan RM2 juta."),
+		news("court-en", 3, "en", "Former minister charged in court with corruption", "The former minister was charged in court with corruption involving RM2 million."),
+	}
+	alone := news("alone", 4, "en", "Football team wins the league", "The team won the league after beating the champions on the last day.")
+	// The same news after the gap starts another story.
+	late := *flood[1]
+	late.Datetime = flood[0].Datetime.Add(maxGap + time.Hour)
+
+	// The terms shared by every news of the list weigh nothing, the lists have news of other stories.
+	tests := []struct {
+		name     string
+		list     []*model.News
+		expected [][]string
+	}{
+		{"a single news", []*model.News{flood[0]}, nil},
+		{"unrelated news", []*model.News{flood[0], court[1], alone}, nil},
+		{"across languages", []*model.News{flood[0], flood[1], court[1], alone}, [][]string{{"flood-ms", "flood-en"}}},
+		{"in the order published", []*model.News{court[1], alone, flood[2], flood[1], court[0], flood[0]}, [][]string{
+			{"flood-ms", "flood-en", "flood-later"},
+			{"court-ms", "court-en"},
+		}},
+		{"after the gap", []*model.News{flood[0], &late, court[1], alone}, nil},
+	}
+
+	for _, test := range tests {
+		var got [][]string
+		for _, c := range group(test.list) {
+			var ids []string
+			for _, n := range c.news {
+				ids = append(ids, n.Id)
+			}
+			got = append(got, ids)
+		}
+
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestVectors(t *testing.T) {
+	vs := vectors([]*model.News{
+		{Title: "Flood", Content: "Flood in Kelantan", Language: "en"},
+		{Title: "Court", Content: "Court in Kelantan", Language: "en"},
+	})
+
+	// A term of every news weighs nothing.
+	flood := vs[0][tokenize("flood", "en")[0]]
+	if kelantan := vs[0][tokenize("kelantan", "en")[0]]; kelantan != 0 || flood <= 0 {
+		t.Errorf("got %v", vs[0])
+	}
+	if cosine(vs[0], vs[1]) != 0 || cosine(vs[0], vs[0]) < 0.999 {
+		t.Errorf("got %v, %v", cosine(vs[0], vs[1]), cosine(vs[0], vs[0]))
+	}
+}
@@ -0,0 +1,129 @@
+package story
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// The stories are regrouped over the news published within the window.
+// The stories that ended before it are kept as they are.
+const window = 7 * 24 * time.Hour
+
+// fields are the fields of the news read by the grouping.
+var fields = []string{"id", "datetime", "title", "content", "language", "source.id"}
+
+// Job groups the recent news into stories, it is meant to run periodically in the background.
+type Job struct {
+	store store.NewsStore
+}
+
+func NewJob(store store.NewsStore) *Job {
+	return &Job{store: store}
+}
+
+func (j *Job) Run() error {
+	now := time.Now()
+	since := now.Add(-window)
+
+	list, err := j.store.GetAll(store.Filter{From: now, Until: since, Fields: fields})
+	if err != nil {
+		return err
+	}
+
+	existing, err := j.store.GetStories(now, since)
+	if err != nil {
+		return err
+	}
+
+	inWindow := make(map[string]struct{}, len(list))
+	for _, n := range list {
+		inWindow[n.Id] = struct{}{}
+	}
+
+	used := make(map[string]struct{})
+	var stories []*model.Story
+
+	for _, c := range group(list) {
+		s := &model.Story{
+			Id:            c.news[0].Id,
+			Label:         c.label(),
+			Keywords:      c.keywords(),
+			FirstDatetime: c.news[0].Datetime,
+			LastDatetime:  c.last,
+		}
+
+		newspapers := make(map[string]struct{})
+		for _, n := range c.news {
+			s.NewsIds = append(s.NewsIds, n.Id)
+			newspapers[n.Source.NewspaperId] = struct{}{}
+		}
+
+		// Keep the id of the story it continues, and its news published before the window.
+		if prev := closest(existing, s.NewsIds, used); prev != nil {
+			used[prev.Id] = struct{}{}
+			s.Id = prev.Id
+
+			var older []string
+			for _, id := range prev.NewsIds {
+				if _, exist := inWindow[id]; !exist {
+					older = append(older, id)
+				}
+			}
+
+			if len(older) > 0 {
+				s.NewsIds = append(older, s.NewsIds...)
+				for _, v := range prev.Newspapers {
+					newspapers[v] = struct{}{}
+				}
+				if prev.FirstDatetime.Before(s.FirstDatetime) {
+					s.FirstDatetime = prev.FirstDatetime
+				}
+			}
+		}
+
+		for v := range newspapers {
+			s.Newspapers = append(s.Newspapers, v)
+		}
+		sort.Strings(s.Newspapers)
+
+		stories = append(stories, s)
+	}
+
+	log.Printf("stories: %d news, %d stories", len(list), len(stories))
+
+	return j.store.SaveStories(since, stories)
+}
+
+// closest returns the unused story sharing the most news with the ids.
+func closest(stories []*model.Story, ids []string, used map[string]struct{}) *model.Story {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	var best *model.Story
+	var bestCount int
+	for _, s := range stories {
+		if _, exist := used[s.Id]; exist {
+			continue
+		}
+
+		var count int
+		for _, id := range s.NewsIds {
+			if _, exist := set[id]; exist {
+				count++
+			}
+		}
+
+		if count > bestCount {
+			best = s
+			bestCount = count
+		}
+	}
+
+	return best
+}
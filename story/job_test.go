@@ -0,0 +1,70 @@
+package story
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+)
+
+func TestJobRun(t *testing.T) {
+	now := time.Now()
+	news := func(id string, age time.Duration, newspaper, lang, title, content string) *model.News {
+		return &model.News{Id: id, Datetime: now.Add(-age), Language: lang, Title: title, Content: content,
+			Source: model.NewsSource{NewspaperId: newspaper}}
+	}
+
+	mem := &storetest.Memory{
+		News: []*model.News{
+			news("flood-ms", 3*time.Hour, model.BharianId, "ms", "Banjir di Kelantan, 5,000 mangsa dipindahkan", "Lebih 5,000 mangsa banjir di Kelantan dipindahkan ke pusat pemindahan sementara."),
+			news("flood-en", 2*time.Hour, model.NstId, "en", "Kelantan flood: 5,000 victims evacuated", "More than 5,000 flood victims in Kelantan were evacuated to relief centres."),
+			news("court", time.Hour, model.NstId, "en", "Former minister charged in court with corruption", "The former minister was charged in court with corruption involving RM2 million."),
+			news("football", time.Hour, model.UtusanId, "en", "Football team wins the league", "The team won the league after beating the champions on the last day."),
+			// Before the window, the news are not regrouped.
+			news("court-old-1", window+2*time.Hour, model.NstId, "en", "Former minister charged in court with corruption", "The former minister was charged in court with corruption."),
+			news("court-old-2", window+time.Hour, model.BharianId, "ms", "Bekas menteri didakwa di mahkamah atas tuduhan rasuah", "Bekas menteri itu didakwa di mahkamah atas tuduhan rasuah."),
+		},
+		Stories: []*model.Story{
+			// A story that ended before the window is kept as it is.
+			{Id: "ended", FirstDatetime: now.Add(-window - 48*time.Hour), LastDatetime: now.Add(-window - time.Hour), NewsIds: []string{"court-old-1", "court-old-2"}},
+			// The story continued keeps its id and its news published before the window.
+			{Id: "continued", FirstDatetime: now.Add(-window - time.Hour), LastDatetime: now.Add(-3 * time.Hour),
+				Newspapers: []string{model.UtusanId}, NewsIds: []string{"flood-older", "flood-ms"}},
+		},
+	}
+
+	if err := NewJob(mem).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if f := mem.Filter; f.From.Sub(f.Until) != window || time.Since(f.From) > time.Minute || !reflect.DeepEqual(f.Fields, fields) {
+		t.Errorf("got the filter %+v", f)
+	}
+
+	stories := make(map[string]*model.Story)
+	for _, s := range mem.Stories {
+		stories[s.Id] = s
+	}
+	if len(stories) != 2 || stories["ended"] == nil {
+		t.Fatalf("got %+v", mem.Stories)
+	}
+
+	s := stories["continued"]
+	if s == nil {
+		t.Fatalf("got %+v", mem.Stories)
+	}
+	if expected := []string{"flood-older", "flood-ms", "flood-en"}; !reflect.DeepEqual(s.NewsIds, expected) {
+		t.Errorf("got %v, expected %v", s.NewsIds, expected)
+	}
+	newspapers := []string{model.BharianId, model.NstId, model.UtusanId}
+	sort.Strings(newspapers)
+	if !reflect.DeepEqual(s.Newspapers, newspapers) {
+		t.Errorf("got %v, expected %v", s.Newspapers, newspapers)
+	}
+	if !s.FirstDatetime.Equal(now.Add(-window-time.Hour)) || !s.LastDatetime.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("got %v - %v", s.FirstDatetime, s.LastDatetime)
+	}
+}
@@ -0,0 +1,93 @@
+package story
+
+import (
+	"unicode"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+)
+
+// glossary maps the common Malay news terms to English, so the same event reported by NST and the Malay newspapers
+// shares more terms. Names of people and places are the same in both languages.
+var glossary = map[string]string{
+	"banjir":      "flood",
+	"mahkamah":    "court",
+	"polis":       "police",
+	"kerajaan":    "government",
+	"menteri":     "minister",
+	"perdana":     "prime",
+	"pilihanraya": "election",
+	"parti":       "party",
+	"rasuah":      "corruption",
+	"tuduhan":     "charge",
+	"didakwa":     "charged",
+	"dituduh":     "charged",
+	"bunuh":       "murder",
+	"kemalangan":  "accident",
+	"mangsa":      "victim",
+	"mati":        "dead",
+	"maut":        "killed",
+	"cedera":      "injured",
+	"kebakaran":   "fire",
+	"hujan":       "rain",
+	"sekolah":     "school",
+	"pelajar":     "student",
+	"hospital":    "hospital",
+	"bajet":       "budget",
+	"cukai":       "tax",
+	"harga":       "price",
+	"minyak":      "oil",
+	"rakyat":      "people",
+	"negeri":      "state",
+	"negara":      "country",
+	"jabatan":     "department",
+	"kementerian": "ministry",
+	"ditahan":     "arrested",
+	"tangkap":     "arrest",
+	"dadah":       "drug",
+	"penjara":     "prison",
+	"denda":       "fine",
+	"undang":      "law",
+	"parlimen":    "parliament",
+	"dewan":       "house",
+	"jenayah":     "crime",
+	"siasatan":    "investigation",
+	"mayat":       "body",
+	"wang":        "money",
+	"bilion":      "billion",
+	"juta":        "million",
+	"dipindahkan": "evacuated",
+	"pemindahan":  "evacuation",
+	"pusat":       "centre",
+	"hakim":       "judge",
+	"peguam":      "lawyer",
+	"ahli":        "member",
+	"syarikat":    "company",
+}
+
+// tokenize returns the stemmed topic terms of the text, with the Malay terms in the glossary translated to English.
+func tokenize(text string, lang string) []string {
+	var terms []string
+	for _, w := range language.Words(text) {
+		if len([]rune(w)) < 3 || isNumber(w) || language.IsStopword(w) {
+			continue
+		}
+
+		if t, exist := glossary[w]; exist {
+			terms = append(terms, language.Stem(t, language.English))
+			continue
+		}
+
+		terms = append(terms, language.Stem(w, lang))
+	}
+
+	return terms
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package summary
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+const (
+	// The number of sentences of a summary.
+	summaryLength = 3
+
+	damping    = 0.85
+	iterations = 50
+	tolerance  = 1e-6
+)
+
+// Summarise sets the summary and the lead of the news.
+// The lead is the first paragraph, the summary is the most central sentences by TextRank, in the order of the article.
+func Summarise(n *model.News) {
+	paragraphs := n.Paragraphs
+	if paragraphs == nil {
+		paragraphs = strings.Split(n.Content, "\n")
+	}
+
+	n.Lead = ""
+	for _, p := range paragraphs {
+		if p = strings.TrimSpace(p); p != "" {
+			n.Lead = p
+			break
+		}
+	}
+
+	sentences := language.Sentences(strings.Join(paragraphs, "\n"), n.Language)
+	n.Summary = strings.Join(TextRank(sentences, n.Language, summaryLength), " ")
+}
+
+// TextRank returns the count most central sentences, in their original order.
+// Two sentences are similar by the words they share, normalised by their lengths.
+func TextRank(sentences []string, lang string, count int) []string {
+	if len(sentences) <= count {
+		return sentences
+	}
+
+	tokens := make([]map[string]struct{}, len(sentences))
+	for i, s := range sentences {
+		tokens[i] = make(map[string]struct{})
+		for _, t := range language.Tokenize(s, lang) {
+			tokens[i][t] = struct{}{}
+		}
+	}
+
+	size := len(sentences)
+	weights := make([][]float64, size)
+	totals := make([]float64, size)
+	for i := range weights {
+		weights[i] = make([]float64, size)
+	}
+	for i := 0; i < size; i++ {
+		for j := i + 1; j < size; j++ {
+			w := similarity(tokens[i], tokens[j])
+			weights[i][j] = w
+			weights[j][i] = w
+			totals[i] += w
+			totals[j] += w
+		}
+	}
+
+	scores := make([]float64, size)
+	for i := range scores {
+		scores[i] = 1
+	}
+
+	for it := 0; it < iterations; it++ {
+		var delta float64
+		next := make([]float64, size)
+		for i := 0; i < size; i++ {
+			var sum float64
+			for j := 0; j < size; j++ {
+				if weights[j][i] > 0 {
+					sum += weights[j][i] / totals[j] * scores[j]
+				}
+			}
+			next[i] = 1 - damping + damping*sum
+			delta += math.Abs(next[i] - scores[i])
+		}
+		scores = next
+		if delta < tolerance {
+			break
+		}
+	}
+
+	ranked := make([]int, size)
+	for i := range ranked {
+		ranked[i] = i
+	}
+	// The earlier sentence wins a tie, news put the important first.
+	sort.SliceStable(ranked, func(a, b int) bool {
+		return scores[ranked[a]] > scores[ranked[b]]
+	})
+
+	top := ranked[:count]
+	sort.Ints(top)
+
+	var result []string
+	for _, i := range top {
+		result = append(result, sentences[i])
+	}
+	return result
+}
+
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+
+	var common int
+	for t := range a {
+		if _, exist := b[t]; exist {
+			common++
+		}
+	}
+
+	return float64(common) / (math.Log(float64(len(a))) + math.Log(float64(len(b))))
+}
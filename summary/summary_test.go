@@ -0,0 +1,74 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestTextRank(t *testing.T) {
+	sentences := []string{
+		"Banjir besar melanda Kelantan semalam.",
+		"Ribuan mangsa banjir di Kelantan dipindahkan ke pusat pemindahan.",
+		"Cuaca di Johor cerah sepanjang hari.",
+		"Pusat pemindahan di Kelantan menerima ribuan mangsa banjir.",
+		"Pasukan bola sepak negeri menang perlawanan akhir.",
+		"Kerajaan menyalurkan bantuan kepada mangsa banjir di pusat pemindahan.",
+	}
+
+	tests := []struct {
+		count    int
+		expected []string
+	}{
+		// The sentences about the flood victims share the most words, they are kept in their order.
+		{3, []string{sentences[1], sentences[3], sentences[5]}},
+		{1, []string{sentences[1]}},
+		// Fewer sentences than the count are returned as they are.
+		{6, sentences},
+		{10, sentences},
+	}
+
+	for _, test := range tests {
+		if got := TextRank(sentences, language.Malay, test.count); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%d: got %q, expected %q", test.count, got, test.expected)
+		}
+	}
+
+	// Without any similar sentences, the first ones are kept.
+	unrelated := []string{"Kilang terbakar.", "Harga minyak naik.", "Pasukan menang.", "Cuaca cerah."}
+	if got := TextRank(unrelated, language.Malay, 2); !reflect.DeepEqual(got, unrelated[:2]) {
+		t.Errorf("got %q, expected %q", got, unrelated[:2])
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	n := &model.News{Language: language.Malay}
+	n.SetParagraphs([]string{
+		"Sasterawan Negara Dr. A. Samad Said menerima anugerah semalam.",
+		"Anugerah itu disampaikan oleh Menteri Pelancongan. Dr. A. Samad Said menerima anugerah itu di Kuala Lumpur.",
+		"Majlis itu dihadiri 500 tetamu. Anugerah sastera itu diberikan kepada Dr. A. Samad Said atas sumbangan beliau.",
+		"Cuaca di ibu negara cerah.",
+	})
+
+	Summarise(n)
+
+	if n.Lead != "Sasterawan Negara Dr. A. Samad Said menerima anugerah semalam." {
+		t.Errorf("got the lead %q", n.Lead)
+	}
+
+	expected := "Sasterawan Negara Dr. A. Samad Said menerima anugerah semalam. " +
+		"Dr. A. Samad Said menerima anugerah itu di Kuala Lumpur. " +
+		"Anugerah sastera itu diberikan kepada Dr. A. Samad Said atas sumbangan beliau."
+	if n.Summary != expected {
+		t.Errorf("got the summary %q, expected %q", n.Summary, expected)
+	}
+
+	// The content is used without the paragraphs.
+	n = &model.News{Content: "\nKilang terbakar di Klang.\nTiada kecederaan."}
+	Summarise(n)
+	if n.Lead != "Kilang terbakar di Klang." || n.Summary != "Kilang terbakar di Klang. Tiada kecederaan." {
+		t.Errorf("got %q, %q", n.Lead, n.Summary)
+	}
+}
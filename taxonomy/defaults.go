@@ -0,0 +1,19 @@
+package taxonomy
+
+import "github.com/ahmadmuzakkir/scrapenews/model"
+
+// Defaults are the topics of a new store, covering the tags of the sources and the usual sections of the newspapers.
+var Defaults = []*model.Topic{
+	{Id: "news", Names: map[string]string{"en": "News", "ms": "Berita"}},
+	{Id: "nation", Names: map[string]string{"en": "Nation", "ms": "Nasional"}, Aliases: []string{"Dalam Negeri", "Tanah Air"}},
+	{Id: "politics", Names: map[string]string{"en": "Politics", "ms": "Politik"}, Aliases: []string{"Pilihan Raya", "PRU14", "GE14"}},
+	{Id: "crime", Names: map[string]string{"en": "Crime", "ms": "Jenayah"}, Aliases: []string{"Kes", "Crime & Courts", "Mahkamah", "Courts"}},
+	{Id: "government", Names: map[string]string{"en": "Government", "ms": "Kerajaan"}, Aliases: []string{"Government & Public Policy", "Dasar Awam"}},
+	{Id: "exclusive", Names: map[string]string{"en": "Exclusive", "ms": "Eksklusif"}},
+	{Id: "business", Names: map[string]string{"en": "Business", "ms": "Bisnes"}, Aliases: []string{"Ekonomi", "Economy", "Perniagaan"}},
+	{Id: "world", Names: map[string]string{"en": "World", "ms": "Dunia"}, Aliases: []string{"Antarabangsa", "International"}},
+	{Id: "sports", Names: map[string]string{"en": "Sports", "ms": "Sukan"}, Aliases: []string{"Sport", "Bola Sepak", "Football"}},
+	{Id: "entertainment", Names: map[string]string{"en": "Entertainment", "ms": "Hiburan"}, Aliases: []string{"Showbiz", "Selebriti"}},
+	{Id: "education", Names: map[string]string{"en": "Education", "ms": "Pendidikan"}},
+	{Id: "health", Names: map[string]string{"en": "Health", "ms": "Kesihatan"}},
+}
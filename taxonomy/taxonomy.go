@@ -0,0 +1,112 @@
+// Package taxonomy maps the free-form tags of the newspapers to canonical topics, e.g Jenayah and Kes to crime.
+package taxonomy
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// Slug returns the key of a tag, its lower case words separated by hyphens, e.g Crime & Courts is crime-courts.
+// The ids of the topics are slugs.
+func Slug(tag string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), "-")
+}
+
+// Taxonomy maps the tags to the topics by their id, names and aliases. It is safe for concurrent use,
+// the topics are replaced by Set after they change.
+type Taxonomy struct {
+	mu sync.RWMutex
+	// index maps the slugs to the ids of the topics.
+	index map[string][]string
+}
+
+func New(topics []*model.Topic) *Taxonomy {
+	t := &Taxonomy{}
+	t.Set(topics)
+	return t
+}
+
+// Set replaces the topics.
+func (t *Taxonomy) Set(topics []*model.Topic) {
+	index := make(map[string][]string)
+	add := func(tag string, id string) {
+		key := Slug(tag)
+		if key == "" {
+			return
+		}
+		for _, v := range index[key] {
+			if v == id {
+				return
+			}
+		}
+		index[key] = append(index[key], id)
+	}
+
+	for _, topic := range topics {
+		add(topic.Id, topic.Id)
+		for _, name := range topic.Names {
+			add(name, topic.Id)
+		}
+		for _, alias := range topic.Aliases {
+			add(alias, topic.Id)
+		}
+	}
+
+	t.mu.Lock()
+	t.index = index
+	t.mu.Unlock()
+}
+
+// Topics returns the ids of the topics of the tags of the news, of the tags of its source and of its subcategory,
+// e.g Kes of Berita Harian, sorted.
+func (t *Taxonomy) Topics(n *model.News) []string {
+	tags := append(append([]string{n.Source.OriginalSubcategory}, n.Tags...), n.Source.Tags...)
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var topics []string
+	seen := make(map[string]struct{})
+	for _, tag := range tags {
+		for _, id := range t.index[Slug(tag)] {
+			if _, exist := seen[id]; !exist {
+				seen[id] = struct{}{}
+				topics = append(topics, id)
+			}
+		}
+	}
+
+	sort.Strings(topics)
+	return topics
+}
+
+// Retag sets the topics of the news, it returns the topics of the news whose topics changed by their id.
+func (t *Taxonomy) Retag(list []*model.News) map[string][]string {
+	changed := make(map[string][]string)
+	for _, n := range list {
+		topics := t.Topics(n)
+		if !equal(topics, n.Topics) {
+			changed[n.Id] = topics
+		}
+		n.Topics = topics
+	}
+	return changed
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package taxonomy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Crime & Courts": "crime-courts",
+		" Jenayah ":      "jenayah",
+		"PRU14":          "pru14",
+		"dalam-negeri":   "dalam-negeri",
+		"&":              "",
+	}
+
+	for tag, expected := range tests {
+		if got := Slug(tag); got != expected {
+			t.Errorf("%q: got %q, expected %q", tag, got, expected)
+		}
+	}
+}
+
+func TestTopics(t *testing.T) {
+	taxonomy := New(Defaults)
+
+	tests := []struct {
+		news     *model.News
+		expected []string
+	}{
+		// The free-form tags of Utusan.
+		{&model.News{Tags: []string{"JENAYAH", "Pilihan Raya", "Kuala Lumpur"}, Source: model.UtusanSources[0]}, []string{"crime", "news", "politics"}},
+		// The subcategory of Berita Harian, Kes is crime.
+		{&model.News{Source: model.BhSources[2]}, []string{"crime", "news"}},
+		{&model.News{Source: model.NstSources[4]}, []string{"government", "news"}},
+		{&model.News{Tags: []string{""}}, nil},
+	}
+
+	for i, test := range tests {
+		if got := taxonomy.Topics(test.news); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%d: got %v, expected %v", i, got, test.expected)
+		}
+	}
+}
+
+func TestRetag(t *testing.T) {
+	taxonomy := New([]*model.Topic{{Id: "flood", Names: map[string]string{"ms": "Banjir"}, Aliases: []string{"Banjir Kilat"}}})
+
+	list := []*model.News{
+		{Id: "1", Tags: []string{"banjir kilat"}},
+		{Id: "2", Tags: []string{"Banjir"}, Topics: []string{"flood"}},
+		{Id: "3", Tags: []string{"sukan"}, Topics: []string{"flood"}},
+	}
+
+	expected := map[string][]string{"1": {"flood"}, "3": nil}
+	if got := taxonomy.Retag(list); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, expected %v", got, expected)
+	}
+	if !reflect.DeepEqual(list[0].Topics, []string{"flood"}) {
+		t.Errorf("got %v", list[0].Topics)
+	}
+
+	// The topics are replaced.
+	taxonomy.Set(nil)
+	if got := taxonomy.Retag(list); len(got) != 2 {
+		t.Errorf("got %v", got)
+	}
+}
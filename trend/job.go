@@ -0,0 +1,67 @@
+package trend
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+// Windows are the windows of the reports computed by the job, the others are computed when they are requested.
+var Windows = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// Job computes the trends of the Windows, it is meant to run periodically in the background.
+type Job struct {
+	store store.NewsStore
+
+	mu      sync.RWMutex
+	reports map[time.Duration]*Report
+}
+
+func NewJob(store store.NewsStore) *Job {
+	return &Job{store: store, reports: make(map[time.Duration]*Report)}
+}
+
+// Run computes the reports of the Windows ending now, over the news of the largest window and its baseline.
+func (j *Job) Run() error {
+	now := time.Now()
+	largest := Windows[len(Windows)-1]
+
+	list, err := j.store.GetAll(store.Filter{From: now, Until: now.Add(-largest - baseline), Fields: Fields})
+	if err != nil {
+		return err
+	}
+
+	reports := make(map[time.Duration]*Report, len(Windows))
+	for _, w := range Windows {
+		reports[w] = Detect(list, now, w)
+	}
+
+	j.mu.Lock()
+	j.reports = reports
+	j.mu.Unlock()
+
+	log.Printf("trends: %d news, %d trends within %s", len(list), len(reports[Windows[0]].Trends), Windows[0])
+
+	return nil
+}
+
+// Report returns the trends of the window. The reports of the Windows are those of the last run,
+// the other windows, and the Windows before the first run, are computed over the news ending now.
+func (j *Job) Report(window time.Duration) (*Report, error) {
+	j.mu.RLock()
+	report, exist := j.reports[window]
+	j.mu.RUnlock()
+	if exist {
+		return report, nil
+	}
+
+	now := time.Now()
+	list, err := j.store.GetAll(store.Filter{From: now, Until: now.Add(-window - baseline), Fields: Fields})
+	if err != nil {
+		return nil, err
+	}
+
+	return Detect(list, now, window), nil
+}
@@ -0,0 +1,286 @@
+package trend
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+// The kinds of trends.
+const (
+	Term   = "term"
+	Tag    = "tag"
+	Entity = "entity"
+)
+
+// The windows before the current one, over this long, are the baseline of the usual mentions.
+const baseline = 7 * 24 * time.Hour
+
+// A trend is mentioned by at least this many news within the window.
+const minCount = 3
+
+// The smallest z-score of a trend.
+const minScore = 2.0
+
+// The standard deviation of the baseline is at least this much, so a term seldom mentioned before does not trend
+// with a single news more than usual.
+const minDeviation = 1.0
+
+// The number of example news of a trend.
+const examplesCount = 3
+
+// Fields are the fields of the news read by Detect.
+var Fields = []string{"id", "datetime", "title", "content", "tags", "url", "source", "language", "entities"}
+
+// Example links a news mentioning the trend.
+type Example struct {
+	Id        string    `json:"id"`
+	Title     string    `json:"title"`
+	Url       string    `json:"url"`
+	Newspaper string    `json:"newspaper"`
+	Datetime  time.Time `json:"datetime"`
+}
+
+// Trend is a term, tag or entity mentioned by more news within the window than in the windows of the baseline.
+type Trend struct {
+	// Term is the word, the tag or the name of the entity, as it is most often written within the window.
+	Term string `json:"term"`
+	Kind string `json:"kind"`
+	// EntityId and EntityType are set for the entities, see the entity and entity_type parameters of the listings.
+	EntityId   string `json:"entity_id,omitempty"`
+	EntityType string `json:"entity_type,omitempty"`
+	Language   string `json:"language"`
+	// Count is the number of news mentioning it within the window.
+	Count int `json:"count"`
+	// Expected is the mean of the counts of the baseline windows, scaled to the number of news within the window.
+	Expected float64 `json:"expected"`
+	// Score is the number of standard deviations of Count above Expected.
+	Score float64 `json:"score"`
+	// News are the latest news of the window mentioning it.
+	News []*Example `json:"news"`
+}
+
+// Report holds the trends of a window, the strongest first.
+type Report struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// News is the number of news within the window per language.
+	News   map[string]int `json:"news"`
+	Trends []*Trend       `json:"trends"`
+}
+
+// mention is a term, tag or entity of a news.
+type mention struct {
+	kind       string
+	label      string
+	entityId   string
+	entityType string
+}
+
+// mentions returns the mentions of the news by their key. The words of the title and the content are stemmed, so
+// banjir and kebanjiran are the same term, the tags are case-insensitive.
+func mentions(n *model.News, lang string) map[string]*mention {
+	result := make(map[string]*mention)
+
+	for _, w := range language.Words(n.Title + "\n" + n.Content) {
+		if len([]rune(w)) < 3 || isNumber(w) || language.IsStopword(w) {
+			continue
+		}
+
+		key := Term + ":" + language.Stem(w, lang)
+		if _, exist := result[key]; !exist {
+			result[key] = &mention{kind: Term, label: w}
+		}
+	}
+
+	for _, t := range n.Tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+
+		key := Tag + ":" + strings.ToLower(t)
+		if _, exist := result[key]; !exist {
+			result[key] = &mention{kind: Tag, label: t}
+		}
+	}
+
+	for _, e := range n.Entities {
+		result[Entity+":"+e.Id] = &mention{kind: Entity, label: e.Name, entityId: e.Id, entityType: e.Type}
+	}
+
+	return result
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// counter counts the news mentioning a term per window, the window of the report first then the baseline from the latest.
+type counter struct {
+	mention *mention
+	counts  []int
+	labels  map[string]int
+	news    []*model.News
+}
+
+// label returns the label most often used within the window.
+func (c *counter) label() string {
+	var best string
+	var bestCount int
+	for l, count := range c.labels {
+		if count > bestCount || count == bestCount && l < best {
+			best = l
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// Detect returns the trends of the window ending at end, per language. The list holds the news of the window and of
+// the baseline before it, the news of an unknown language are detected from their title and content.
+func Detect(list []*model.News, end time.Time, window time.Duration) *Report {
+	windows := 1 + int(baseline/window)
+
+	docs := make(map[string][]int)
+	counters := make(map[string]map[string]*counter)
+
+	for _, n := range list {
+		age := end.Sub(n.Datetime)
+		if age < 0 || int(age/window) >= windows {
+			continue
+		}
+		i := int(age / window)
+
+		lang := n.Language
+		if lang == "" {
+			lang = language.Detect(n.Title + "\n" + n.Content)
+		}
+		if lang == "" {
+			continue
+		}
+
+		if _, exist := docs[lang]; !exist {
+			docs[lang] = make([]int, windows)
+			counters[lang] = make(map[string]*counter)
+		}
+		docs[lang][i]++
+
+		for key, m := range mentions(n, lang) {
+			c, exist := counters[lang][key]
+			if !exist {
+				c = &counter{mention: m, counts: make([]int, windows), labels: make(map[string]int)}
+				counters[lang][key] = c
+			}
+
+			c.counts[i]++
+			if i == 0 {
+				c.labels[m.label]++
+				c.news = append(c.news, n)
+			}
+		}
+	}
+
+	report := &Report{Start: end.Add(-window), End: end, News: make(map[string]int), Trends: []*Trend{}}
+
+	for lang, perLang := range counters {
+		report.News[lang] = docs[lang][0]
+
+		for _, c := range perLang {
+			if c.counts[0] < minCount {
+				continue
+			}
+
+			expected, score := zscore(c.counts, docs[lang])
+			if score < minScore {
+				continue
+			}
+
+			report.Trends = append(report.Trends, &Trend{
+				Term:       c.label(),
+				Kind:       c.mention.kind,
+				EntityId:   c.mention.entityId,
+				EntityType: c.mention.entityType,
+				Language:   lang,
+				Count:      c.counts[0],
+				Expected:   expected,
+				Score:      score,
+				News:       examples(c.news),
+			})
+		}
+	}
+
+	sort.Slice(report.Trends, func(i, j int) bool {
+		a, b := report.Trends[i], report.Trends[j]
+		switch {
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.Language != b.Language:
+			return a.Language < b.Language
+		case a.Kind != b.Kind:
+			return a.Kind < b.Kind
+		}
+		return a.Term < b.Term
+	})
+
+	return report
+}
+
+// zscore returns the count expected within the window and the z-score of the count against the baseline windows.
+// The counts of the baseline are scaled by the number of news of their window, so a busy day does not hide a quiet
+// one. The windows without news, e.g before the first scraping, are left out.
+func zscore(counts []int, docs []int) (float64, float64) {
+	var scaled []float64
+	for i := 1; i < len(counts); i++ {
+		if docs[i] == 0 {
+			continue
+		}
+		scaled = append(scaled, float64(counts[i])*float64(docs[0])/float64(docs[i]))
+	}
+
+	var mean, deviation float64
+	if len(scaled) > 0 {
+		for _, v := range scaled {
+			mean += v
+		}
+		mean /= float64(len(scaled))
+
+		for _, v := range scaled {
+			deviation += (v - mean) * (v - mean)
+		}
+		deviation = math.Sqrt(deviation / float64(len(scaled)))
+	}
+
+	return mean, (float64(counts[0]) - mean) / math.Max(deviation, minDeviation)
+}
+
+// examples returns the latest news of the list.
+func examples(list []*model.News) []*Example {
+	sorted := make([]*model.News, len(list))
+	copy(sorted, list)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Datetime.After(sorted[j].Datetime)
+	})
+
+	if len(sorted) > examplesCount {
+		sorted = sorted[:examplesCount]
+	}
+
+	result := make([]*Example, len(sorted))
+	for i, n := range sorted {
+		result[i] = &Example{Id: n.Id, Title: n.Title, Url: n.Url, Newspaper: n.Source.NewspaperId, Datetime: n.Datetime}
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package trend
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+)
+
+func TestZscore(t *testing.T) {
+	// The second baseline window has twice the news, its count is halved.
+	expected, score := zscore([]int{6, 2, 4, 2}, []int{10, 10, 20, 10})
+	if expected != 2 || score != 4 {
+		t.Errorf("got %v and %v, expected 2 and 4", expected, score)
+	}
+
+	// The windows without news are left out, the deviation is at least minDeviation.
+	expected, score = zscore([]int{3, 0, 0}, []int{5, 0, 5})
+	if expected != 0 || score != 3 {
+		t.Errorf("got %v and %v, expected 0 and 3", expected, score)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	end := time.Date(2018, 6, 11, 12, 0, 0, 0, time.UTC)
+	window := 6 * time.Hour
+
+	var list []*model.News
+	add := func(id string, age time.Duration, lang string, title string, tags []string, entities []*model.Entity) {
+		list = append(list, &model.News{
+			Id: id, Datetime: end.Add(-age), Language: lang, Title: title, Tags: tags, Entities: entities,
+			Url: "http://example.com/" + id, Source: model.BhSources[0],
+		})
+	}
+
+	// Banjir is mentioned in every window, kebakaran only within the last one.
+	for i := 0; i < 28; i++ {
+		age := time.Duration(i)*window + time.Hour
+		add("b"+string(rune('a'+i)), age, "ms", "Banjir di Kelantan", nil, nil)
+		add("c"+string(rune('a'+i)), age, "ms", "Mesyuarat kabinet", nil, nil)
+	}
+
+	najib := []*model.Entity{{Id: "najib-razak", Type: "person", Name: "Najib Razak"}}
+	add("k1", time.Hour, "ms", "Kebakaran di Kelantan", []string{"Kebakaran"}, najib)
+	add("k2", 2*time.Hour, "ms", "Kebakaran kilang", []string{"kebakaran"}, najib)
+	add("k3", 3*time.Hour, "ms", "Kebakaran rumah", []string{"kebakaran"}, najib)
+	add("k4", 4*time.Hour, "", "Kebakaran rumah kedai di ibu negara, katanya mangsa telah dipindahkan dari kawasan itu", nil, najib)
+
+	// Fire is spiking in English, after the window.
+	add("f1", -time.Hour, "en", "Fire at the factory", nil, nil)
+
+	report := Detect(list, end, window)
+
+	if report.News["ms"] != 6 || report.News["en"] != 0 {
+		t.Errorf("got news %v", report.News)
+	}
+
+	var found = make(map[string]*Trend)
+	for _, v := range report.Trends {
+		found[v.Kind+":"+v.Term] = v
+		if v.Language != "ms" {
+			t.Errorf("got the %s trend %s", v.Language, v.Term)
+		}
+	}
+
+	for _, key := range []string{"term:kebakaran", "tag:kebakaran", "entity:Najib Razak"} {
+		if _, exist := found[key]; !exist {
+			t.Errorf("%s is not trending: %v", key, found)
+		}
+	}
+	for _, key := range []string{"term:banjir", "term:kelantan", "term:kabinet"} {
+		if _, exist := found[key]; exist {
+			t.Errorf("%s is trending", key)
+		}
+	}
+
+	fire := found["term:kebakaran"]
+	if fire == nil {
+		return
+	}
+	if fire.Count != 4 || fire.Expected != 0 || math.Abs(fire.Score-4) > 1e-9 {
+		t.Errorf("got %+v", fire)
+	}
+	if len(fire.News) != examplesCount || fire.News[0].Id != "k1" || fire.News[2].Id != "k3" {
+		t.Errorf("got the examples %v, %v, %v", fire.News[0], fire.News[1], fire.News[2])
+	}
+
+	if e := found["entity:Najib Razak"]; e != nil && (e.EntityId != "najib-razak" || e.EntityType != "person") {
+		t.Errorf("got %+v", e)
+	}
+}
@@ -0,0 +1,363 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/language"
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store"
+)
+
+const (
+	// SignatureHeader is the HMAC-SHA256 of the body keyed by the secret of the webhook, e.g sha256=4f2a...
+	SignatureHeader = "X-Scrapenews-Signature"
+	// DeliveryHeader is the id of the delivery, the same for every attempt.
+	DeliveryHeader = "X-Scrapenews-Delivery"
+	EventHeader    = "X-Scrapenews-Event"
+
+	EventInserted = "news.inserted"
+
+	maxAttempts  = 5
+	workersCount = 4
+	queueSize    = 100
+)
+
+// Payload is the body posted to the webhooks.
+type Payload struct {
+	Event     string        `json:"event"`
+	WebhookId string        `json:"webhook_id"`
+	News      []*model.News `json:"news"`
+}
+
+// job is an attempt of a delivery.
+type job struct {
+	webhook *model.Webhook
+	news    []*model.News
+	body    []byte
+
+	// The id of the delivery, the same for every attempt.
+	id      string
+	attempt int
+	// The failed attempt retried, nil for the first attempt.
+	retried *model.Delivery
+}
+
+// Dispatcher delivers the inserted news to the matching webhooks, see store.Listener.
+// A failed delivery is retried with an exponential backoff, every attempt is saved in the store along with
+// the time of its retry, see Retry. The deliveries are not lost while the queue is full, or on a restart.
+type Dispatcher struct {
+	store      store.NewsStore
+	httpClient *http.Client
+	queue      chan job
+
+	mu sync.Mutex
+	// The ids of the deliveries queued or being attempted.
+	queued map[string]bool
+
+	// Backoff is the wait before the second attempt, it doubles at every attempt.
+	Backoff time.Duration
+}
+
+func NewDispatcher(store store.NewsStore, httpClient *http.Client) *Dispatcher {
+	d := &Dispatcher{
+		store:      store,
+		httpClient: httpClient,
+		queue:      make(chan job, queueSize),
+		queued:     make(map[string]bool),
+		Backoff:    30 * time.Second,
+	}
+
+	for w := 0; w < workersCount; w++ {
+		go func() {
+			for j := range d.queue {
+				d.deliver(j)
+			}
+		}()
+	}
+
+	return d
+}
+
+// Inserted queues the deliveries of the news to the webhooks they match.
+func (d *Dispatcher) Inserted(news []*model.News) {
+	webhooks, err := d.store.GetWebhooks()
+	if err != nil {
+		log.Println("webhook: ", err)
+		return
+	}
+
+	for _, w := range webhooks {
+		var matched []*model.News
+		for _, n := range news {
+			if Match(w, n) {
+				matched = append(matched, n)
+			}
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		body, err := json.Marshal(Payload{Event: EventInserted, WebhookId: w.Id, News: matched})
+		if err != nil {
+			log.Println("webhook: ", err)
+			continue
+		}
+
+		j := job{webhook: w, news: matched, body: body, id: NewId(), attempt: 1}
+		if d.enqueue(j) {
+			continue
+		}
+
+		// The delivery is saved as not attempted yet, it is retried by the next Retry.
+		log.Printf("webhook: %s queue is full, delivery %s is retried later", w.Id, j.id)
+		now := time.Now()
+		delivery := &model.Delivery{Id: j.id, WebhookId: w.Id, NewsIds: newsIds(matched), Datetime: now, Error: "queue is full", Retry: &now}
+		if err := d.store.SaveDelivery(delivery); err != nil {
+			log.Println("webhook: ", err)
+		}
+	}
+}
+
+// Retry queues the next attempt of the failed deliveries due, it is run every minute.
+// The news are read again from the store. The deliveries left while the queue is full are queued by the next Retry.
+func (d *Dispatcher) Retry() error {
+	retries, err := d.store.GetRetries(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, r := range retries {
+		d.mu.Lock()
+		queued := d.queued[r.Id]
+		d.mu.Unlock()
+		if queued {
+			continue
+		}
+
+		j, err := d.retryJob(r)
+		if err != nil {
+			return err
+		}
+
+		// The webhook was deleted, or the news.
+		if j == nil {
+			if err := d.store.SetRetried(r); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !d.enqueue(*j) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// retryJob returns the next attempt of the failed delivery, nil if its webhook or its news do not exist anymore.
+func (d *Dispatcher) retryJob(r *model.Delivery) (*job, error) {
+	w, err := d.store.GetWebhook(r.WebhookId)
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var news []*model.News
+	for _, id := range r.NewsIds {
+		n, err := d.store.Get(id)
+		if err == store.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		news = append(news, n)
+	}
+
+	if len(news) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(Payload{Event: EventInserted, WebhookId: w.Id, News: news})
+	if err != nil {
+		return nil, err
+	}
+
+	return &job{webhook: w, news: news, body: body, id: r.Id, attempt: r.Attempt + 1, retried: r}, nil
+}
+
+// enqueue returns false if the queue is full.
+func (d *Dispatcher) enqueue(j job) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	select {
+	case d.queue <- j:
+		d.queued[j.id] = true
+		return true
+	default:
+		return false
+	}
+}
+
+// deliver makes an attempt of the delivery, and saves the time of the next attempt if it failed.
+func (d *Dispatcher) deliver(j job) {
+	defer func() {
+		d.mu.Lock()
+		delete(d.queued, j.id)
+		d.mu.Unlock()
+	}()
+
+	delivery := &model.Delivery{
+		Id:        j.id,
+		WebhookId: j.webhook.Id,
+		NewsIds:   newsIds(j.news),
+		Attempt:   j.attempt,
+		Datetime:  time.Now(),
+	}
+
+	var err error
+	delivery.StatusCode, err = d.post(j.webhook, j.id, j.body)
+	delivery.Duration = int64(time.Since(delivery.Datetime) / time.Millisecond)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if delivery.Error != "" && j.attempt < maxAttempts {
+		retry := delivery.Datetime.Add(d.Backoff << uint(j.attempt-1))
+		delivery.Retry = &retry
+	}
+	if delivery.Error != "" && j.attempt >= maxAttempts {
+		log.Printf("webhook: %s delivery %s failed after %d attempts", j.webhook.Id, j.id, maxAttempts)
+	}
+
+	if err := d.store.SaveDelivery(delivery); err != nil {
+		log.Println("webhook: ", err)
+		return
+	}
+
+	if j.retried != nil {
+		if err := d.store.SetRetried(j.retried); err != nil {
+			log.Println("webhook: ", err)
+		}
+	}
+}
+
+func newsIds(news []*model.News) []string {
+	var ids []string
+	for _, n := range news {
+		ids = append(ids, n.Id)
+	}
+	return ids
+}
+
+// post returns the status code of the response, the error is set unless it is 2xx.
+func (d *Dispatcher) post(w *model.Webhook, deliveryId string, body []byte) (int, error) {
+	req, err := http.NewRequest("POST", w.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "scrapenews-webhook")
+	req.Header.Set(EventHeader, EventInserted)
+	req.Header.Set(DeliveryHeader, deliveryId)
+	req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of the body, the value of SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if the signature is the signature of the body, for the receivers.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Match returns true if the news matches every filter of the webhook.
+func Match(w *model.Webhook, n *model.News) bool {
+	if len(w.Newspapers) > 0 && !contains(w.Newspapers, n.Source.NewspaperId) {
+		return false
+	}
+
+	if len(w.Tags) > 0 {
+		var found bool
+		for _, t := range append(append([]string{}, n.Tags...), n.Source.Tags...) {
+			if contains(w.Tags, strings.ToLower(strings.TrimSpace(t))) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(w.Keywords) > 0 {
+		var found bool
+		for _, k := range w.Keywords {
+			if language.Contains(n.Title+"\n"+n.Content, k, n.Language) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewId returns a random id, also used for the secrets.
+func NewId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahmadmuzakkir/scrapenews/model"
+	"github.com/ahmadmuzakkir/scrapenews/store/storetest"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"news.inserted"}`)
+
+	signature := Sign("secret", body)
+	if len(signature) != len("sha256=")+64 || signature[:7] != "sha256=" {
+		t.Errorf("got %s", signature)
+	}
+	if signature != Sign("secret", body) {
+		t.Error("got a different signature of the same body")
+	}
+
+	if !Verify("secret", body, signature) {
+		t.Error("got the signature rejected")
+	}
+	if Verify("other", body, signature) {
+		t.Error("got the signature of another secret verified")
+	}
+	if Verify("secret", []byte(`{"event":"news.deleted"}`), signature) {
+		t.Error("got the signature of another body verified")
+	}
+	if Verify("secret", body, "") {
+		t.Error("got an empty signature verified")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	n := &model.News{
+		Title:    "Banjir di Kelantan",
+		Content:  "Ribuan mangsa kebanjiran dipindahkan.",
+		Tags:     []string{" Bencana "},
+		Language: "ms",
+		Source:   model.NewsSource{NewspaperId: "bharian", Tags: []string{"nasional"}},
+	}
+
+	tests := []struct {
+		webhook  *model.Webhook
+		expected bool
+	}{
+		{&model.Webhook{}, true},
+		{&model.Webhook{Newspapers: []string{"nst", "BHARIAN"}}, true},
+		{&model.Webhook{Newspapers: []string{"nst"}}, false},
+		// The tags of the news and of its source.
+		{&model.Webhook{Tags: []string{"bencana"}}, true},
+		{&model.Webhook{Tags: []string{"nasional"}}, true},
+		{&model.Webhook{Tags: []string{"sukan"}}, false},
+		// The keywords are stemmed.
+		{&model.Webhook{Keywords: []string{"politik", "banjir"}}, true},
+		{&model.Webhook{Keywords: []string{"mangsa banjir"}}, true},
+		{&model.Webhook{Keywords: []string{"banjir johor"}}, false},
+		// Every filter must match.
+		{&model.Webhook{Newspapers: []string{"bharian"}, Tags: []string{"bencana"}, Keywords: []string{"banjir"}}, true},
+		{&model.Webhook{Newspapers: []string{"bharian"}, Tags: []string{"sukan"}, Keywords: []string{"banjir"}}, false},
+	}
+
+	for _, test := range tests {
+		if got := Match(test.webhook, n); got != test.expected {
+			t.Errorf("%+v: got %v, expected %v", test.webhook, got, test.expected)
+		}
+	}
+}
+
+// waitDeliveries returns the deliveries of the webhook once there are count of them, the newest first.
+// The retries are made meanwhile, as the cron does.
+func waitDeliveries(t *testing.T, d *Dispatcher, mem *storetest.Memory, webhookId string, count int) []*model.Delivery {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		deliveries, _ := mem.GetDeliveries(webhookId, 100)
+		if len(deliveries) >= count {
+			return deliveries
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d deliveries, expected %d", len(deliveries), count)
+		}
+		if err := d.Retry(); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]byte
+
+	// The receiver fails the first 2 attempts.
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		bodies = append(bodies, body)
+		if len(requests) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	inserted := []*model.News{
+		{Id: "1", Source: model.NewsSource{NewspaperId: "bharian"}},
+		{Id: "2", Source: model.NewsSource{NewspaperId: "nst"}},
+	}
+	mem := &storetest.Memory{News: inserted, Webhooks: []*model.Webhook{
+		{Id: "w1", Url: receiver.URL, Secret: "secret", Newspapers: []string{"bharian"}},
+	}}
+	d := NewDispatcher(mem, receiver.Client())
+	d.Backoff = time.Millisecond
+
+	d.Inserted(inserted)
+
+	deliveries := waitDeliveries(t, d, mem, "w1", 3)
+	if len(deliveries) != 3 {
+		t.Fatalf("got %d deliveries, expected 3", len(deliveries))
+	}
+
+	for i, delivery := range deliveries {
+		attempt := 3 - i
+		if delivery.Attempt != attempt || delivery.Id != deliveries[0].Id || len(delivery.NewsIds) != 1 || delivery.NewsIds[0] != "1" {
+			t.Errorf("attempt %d: got %+v", attempt, delivery)
+		}
+		if attempt < 3 && (delivery.StatusCode != http.StatusServiceUnavailable || delivery.Error == "") {
+			t.Errorf("attempt %d: got %d, %q", attempt, delivery.StatusCode, delivery.Error)
+		}
+		// The retries made are cleared.
+		if delivery.Retry != nil {
+			t.Errorf("attempt %d: got the retry %v", attempt, delivery.Retry)
+		}
+	}
+	if deliveries[0].StatusCode != http.StatusOK || deliveries[0].Error != "" {
+		t.Errorf("got %d, %q", deliveries[0].StatusCode, deliveries[0].Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, r := range requests {
+		if r.Header.Get(DeliveryHeader) != deliveries[0].Id || r.Header.Get(EventHeader) != EventInserted {
+			t.Errorf("request %d: got %v", i, r.Header)
+		}
+		if !Verify("secret", bodies[i], r.Header.Get(SignatureHeader)) {
+			t.Errorf("request %d: got the signature %s rejected", i, r.Header.Get(SignatureHeader))
+		}
+
+		var payload Payload
+		if err := json.Unmarshal(bodies[i], &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.WebhookId != "w1" || len(payload.News) != 1 || payload.News[0].Id != "1" {
+			t.Errorf("request %d: got %+v", i, payload)
+		}
+	}
+}
+
+func TestDispatcherRetryDoesNotBlock(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	// More failing webhooks than workers, waiting an hour for their retry.
+	mem := &storetest.Memory{}
+	for _, id := range []string{"f1", "f2", "f3", "f4", "f5", "f6"} {
+		mem.Webhooks = append(mem.Webhooks, &model.Webhook{Id: id, Url: failing.URL})
+	}
+	d := NewDispatcher(mem, http.DefaultClient)
+	d.Backoff = time.Hour
+
+	d.Inserted([]*model.News{{Id: "1"}})
+	for _, id := range []string{"f1", "f2", "f3", "f4", "f5", "f6"} {
+		deliveries := waitDeliveries(t, d, mem, id, 1)
+		if retry := deliveries[0].Retry; retry == nil || retry.Sub(deliveries[0].Datetime) != time.Hour {
+			t.Errorf("%s: got the retry %v", id, retry)
+		}
+	}
+
+	mem.SaveWebhook(&model.Webhook{Id: "ok", Url: receiver.URL})
+	d.Inserted([]*model.News{{Id: "2"}})
+
+	deliveries := waitDeliveries(t, d, mem, "ok", 1)
+	if deliveries[0].Error != "" || deliveries[0].Attempt != 1 {
+		t.Errorf("got %+v", deliveries[0])
+	}
+}
+
+func TestDispatcherQueueFull(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	news := &model.News{Id: "1", Title: "Banjir"}
+	mem := &storetest.Memory{News: []*model.News{news}, Webhooks: []*model.Webhook{{Id: "w1", Url: receiver.URL}}}
+
+	// A dispatcher without workers, its queue is always full.
+	d := &Dispatcher{store: mem, httpClient: receiver.Client(), queue: make(chan job), queued: make(map[string]bool), Backoff: time.Hour}
+	d.Inserted([]*model.News{news})
+
+	// The delivery is saved, to be retried.
+	deliveries, _ := mem.GetDeliveries("w1", 10)
+	if len(deliveries) != 1 || deliveries[0].Attempt != 0 || deliveries[0].Retry == nil || deliveries[0].Error == "" {
+		t.Fatalf("got %+v", deliveries)
+	}
+	if err := d.Retry(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once there is a worker, the delivery is retried, as after a restart.
+	d = NewDispatcher(mem, receiver.Client())
+	deliveries = waitDeliveries(t, d, mem, "w1", 2)
+	if deliveries[0].Attempt != 1 || deliveries[0].Id != deliveries[1].Id || deliveries[0].Error != "" || deliveries[1].Retry != nil {
+		t.Errorf("got %+v, %+v", deliveries[0], deliveries[1])
+	}
+
+	// The retries of a deleted webhook are cleared.
+	mem.SaveDelivery(&model.Delivery{Id: "deleted", WebhookId: "w2", NewsIds: []string{"1"}, Attempt: 1, Retry: &news.Datetime})
+	if err := d.Retry(); err != nil {
+		t.Fatal(err)
+	}
+	if retries, _ := mem.GetRetries(time.Now()); len(retries) != 0 {
+		t.Errorf("got %+v", retries)
+	}
+}